@@ -2,42 +2,161 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
 // User represents an authenticated user
 type User struct {
-	Username string
-	Password string
+	Username     string
+	Password     string
+	PasswordHash string // bcrypt hash; takes precedence over Password when set
+}
+
+// checkPassword reports whether password matches the user's credentials
+func (u User) checkPassword(password string) bool {
+	if u.PasswordHash != "" {
+		return CheckPasswordHash(u.PasswordHash, password)
+	}
+	return subtle.ConstantTimeCompare([]byte(u.Password), []byte(password)) == 1
+}
+
+// SigningKey is an HMAC secret identified by the "kid" header of the tokens it signs
+type SigningKey struct {
+	ID     string
+	Secret []byte
 }
 
 // JWTMinter handles JWT token minting
 type JWTMinter struct {
-	// In a real implementation, this would be a database
+	mu    sync.RWMutex
 	users map[string]User
-	// Secret key for signing JWT tokens
-	secretKey []byte
+	// signingKey signs new tokens; verifyKeys holds it plus previously active keys
+	signingKey jwk.Key
+	verifyKeys jwk.Set
+	// lockout locks out username/IP pairs after repeated failed logins (nil disables it)
+	lockout  *loginLockout
+	clientIP func(r *http.Request) string
 }
 
-// NewJWTMinter creates a new JWT minter
+// NewJWTMinter creates a new JWT minter with a random signing key
 func NewJWTMinter(users map[string]User) (*JWTMinter, error) {
 	// Generate a random secret key
 	secretKey := make([]byte, 32)
 	if _, err := rand.Read(secretKey); err != nil {
 		return nil, fmt.Errorf("failed to generate secret key: %w", err)
 	}
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, fmt.Errorf("failed to generate key ID: %w", err)
+	}
+
+	return NewJWTMinterWithKeys(users, []SigningKey{{ID: hex.EncodeToString(kid), Secret: secretKey}}, hex.EncodeToString(kid))
+}
+
+// NewJWTMinterWithKeys creates a JWT minter that signs with the key named activeKID
+// and accepts tokens signed by any of keys
+func NewJWTMinterWithKeys(users map[string]User, keys []SigningKey, activeKID string) (*JWTMinter, error) {
+	m := &JWTMinter{
+		users:      users,
+		verifyKeys: jwk.NewSet(),
+		clientIP:   remoteIP,
+	}
+	for _, k := range keys {
+		if err := m.addKey(k); err != nil {
+			return nil, err
+		}
+	}
 
-	return &JWTMinter{
-		users:     users,
-		secretKey: secretKey,
-	}, nil
+	active, ok := m.verifyKeys.LookupKeyID(activeKID)
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKID)
+	}
+	m.signingKey = active
+	return m, nil
+}
+
+// SetLoginLockout rejects logins from a username/IP pair for window once it has
+// failed maxFailures times within window. A successful login clears the count.
+// Zero maxFailures disables the lockout.
+func (m *JWTMinter) SetLoginLockout(maxFailures int, window time.Duration) {
+	if maxFailures <= 0 {
+		m.lockout = nil
+		return
+	}
+	m.lockout = newLoginLockout(maxFailures, window)
+}
+
+// StartLoginLockoutSweep removes, every interval, the failed-login records of pairs
+// with no activity for retention so the lockout does not grow unbounded. Retention
+// shorter than the failure window is raised to it. It does nothing when the lockout
+// is disabled or interval is not positive.
+func (m *JWTMinter) StartLoginLockoutSweep(interval, retention time.Duration) {
+	if m.lockout == nil || interval <= 0 {
+		return
+	}
+	m.lockout.mu.Lock()
+	m.lockout.retention = max(retention, m.lockout.window)
+	m.lockout.mu.Unlock()
+	m.lockout.start(interval)
+}
+
+// StopLoginLockoutSweep stops the sweep started by StartLoginLockoutSweep
+func (m *JWTMinter) StopLoginLockoutSweep() {
+	if m.lockout != nil {
+		m.lockout.stopSweep()
+	}
+}
+
+// SetClientIPFunc sets how the client IP of a login is resolved; it defaults to
+// the host of the remote address
+func (m *JWTMinter) SetClientIPFunc(clientIP func(r *http.Request) string) {
+	m.clientIP = clientIP
+}
+
+// RotateKey makes key the signing key for new tokens; tokens signed with
+// earlier keys remain valid
+func (m *JWTMinter) RotateKey(key SigningKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.addKey(key); err != nil {
+		return err
+	}
+	m.signingKey, _ = m.verifyKeys.LookupKeyID(key.ID)
+	return nil
+}
+
+// addKey registers a verification key; callers other than constructors must hold mu
+func (m *JWTMinter) addKey(k SigningKey) error {
+	if k.ID == "" || len(k.Secret) == 0 {
+		return errors.New("signing key needs an ID and a secret")
+	}
+	if _, exists := m.verifyKeys.LookupKeyID(k.ID); exists {
+		return fmt.Errorf("duplicate signing key %q", k.ID)
+	}
+	key, err := jwk.Import(k.Secret)
+	if err != nil {
+		return fmt.Errorf("invalid signing key %q: %w", k.ID, err)
+	}
+	if err := key.Set(jwk.KeyIDKey, k.ID); err != nil {
+		return err
+	}
+	if err := key.Set(jwk.AlgorithmKey, jwa.HS256()); err != nil {
+		return err
+	}
+	return m.verifyKeys.AddKey(key)
 }
 
 // LoginRequest represents a login request
@@ -64,12 +183,30 @@ func (m *JWTMinter) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject locked out pairs without checking the password
+	lockoutKey := req.Username + "\x00" + m.clientIP(r)
+	if m.lockout != nil {
+		if wait := m.lockout.lockedFor(lockoutKey); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Too many failed login attempts", http.StatusTooManyRequests)
+			return
+		}
+	}
+
 	// Validate credentials
+	m.mu.RLock()
 	user, exists := m.users[req.Username]
-	if !exists || user.Password != req.Password {
+	m.mu.RUnlock()
+	if !exists || !user.checkPassword(req.Password) {
+		if m.lockout != nil {
+			m.lockout.fail(lockoutKey)
+		}
 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
+	if m.lockout != nil {
+		m.lockout.succeed(lockoutKey)
+	}
 
 	// Create token
 	token, err := jwt.NewBuilder().
@@ -83,8 +220,11 @@ func (m *JWTMinter) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Sign token
-	signed, err := jwt.Sign(token, jwt.WithKey(jwa.HS256(), m.secretKey))
+	// Sign token with the active key
+	m.mu.RLock()
+	signingKey := m.signingKey
+	m.mu.RUnlock()
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.HS256(), signingKey))
 	if err != nil {
 		http.Error(w, "Failed to sign token", http.StatusInternalServerError)
 		return
@@ -97,6 +237,21 @@ func (m *JWTMinter) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// AddUser registers a user that can log in from now on
+func (m *JWTMinter) AddUser(user User) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[user.Username] = user
+}
+
+// HasUser reports whether a user with the given name exists
+func (m *JWTMinter) HasUser(username string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, exists := m.users[username]
+	return exists
+}
+
 // Mount mounts the JWT minter to the given mux
 func (m *JWTMinter) Mount(mux *http.ServeMux) {
 	mux.HandleFunc("POST /auth/login", m.LoginHandler)
@@ -109,7 +264,11 @@ type Verifier interface {
 
 // VerifyToken verifies a JWT token and returns the claims if valid
 func (m *JWTMinter) VerifyToken(token string) (jwt.Token, error) {
-	parsed, err := jwt.Parse([]byte(token), jwt.WithKey(jwa.HS256(), m.secretKey))
+	// The key is selected by the token's kid header
+	m.mu.RLock()
+	keys := m.verifyKeys
+	m.mu.RUnlock()
+	parsed, err := jwt.Parse([]byte(token), jwt.WithKeySet(keys))
 	if err != nil {
 		return nil, err
 	}
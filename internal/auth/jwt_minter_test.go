@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lestrrat-go/jwx/v3/jws"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -115,3 +116,168 @@ func TestJWTMinter(t *testing.T) {
 		assert.Equal(t, "user", subject)
 	})
 }
+
+// login mints a token for the given credentials through the login handler
+func login(t *testing.T, minter *JWTMinter, username, password string) string {
+	t.Helper()
+	body, err := json.Marshal(LoginRequest{Username: username, Password: password})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	minter.LoginHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var loginResp LoginResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&loginResp))
+	return loginResp.Token
+}
+
+// tokenKeyID returns the kid header of a signed token
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+	msg, err := jws.Parse([]byte(token))
+	require.NoError(t, err)
+	kid, ok := msg.Signatures()[0].ProtectedHeaders().KeyID()
+	require.True(t, ok)
+	return kid
+}
+
+func TestJWTMinterKeyRotation(t *testing.T) {
+	users := map[string]User{"admin": {Username: "admin", Password: "admin123"}}
+	minter, err := NewJWTMinterWithKeys(users, []SigningKey{{ID: "2024-01", Secret: []byte("old-secret")}}, "2024-01")
+	require.NoError(t, err)
+
+	oldToken := login(t, minter, "admin", "admin123")
+	assert.Equal(t, "2024-01", tokenKeyID(t, oldToken))
+
+	// Rotate to a new key
+	require.NoError(t, minter.RotateKey(SigningKey{ID: "2024-02", Secret: []byte("new-secret")}))
+	newToken := login(t, minter, "admin", "admin123")
+	assert.Equal(t, "2024-02", tokenKeyID(t, newToken))
+
+	// Both the old and the new token verify
+	_, err = minter.VerifyToken(oldToken)
+	assert.NoError(t, err)
+	_, err = minter.VerifyToken(newToken)
+	assert.NoError(t, err)
+
+	// A minter that no longer knows the old key rejects its tokens
+	retired, err := NewJWTMinterWithKeys(users, []SigningKey{{ID: "2024-02", Secret: []byte("new-secret")}}, "2024-02")
+	require.NoError(t, err)
+	_, err = retired.VerifyToken(oldToken)
+	assert.Error(t, err)
+	_, err = retired.VerifyToken(newToken)
+	assert.NoError(t, err)
+
+	// Duplicate key IDs are rejected
+	assert.Error(t, minter.RotateKey(SigningKey{ID: "2024-01", Secret: []byte("other")}))
+}
+
+func TestNewJWTMinterWithKeysUnknownActiveKey(t *testing.T) {
+	_, err := NewJWTMinterWithKeys(map[string]User{}, []SigningKey{{ID: "a", Secret: []byte("secret")}}, "b")
+	assert.Error(t, err)
+}
+
+func TestJWTMinterLoginLockout(t *testing.T) {
+	minter, err := NewJWTMinter(map[string]User{"admin": {Username: "admin", Password: "admin123"}})
+	require.NoError(t, err)
+	minter.SetLoginLockout(3, time.Minute)
+	now := time.Now()
+	minter.lockout.now = func() time.Time { return now }
+
+	attempt := func(password, remoteAddr string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(LoginRequest{Username: "admin", Password: password})
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		minter.LoginHandler(w, req)
+		return w
+	}
+
+	// A successful login clears earlier failures
+	assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusOK, attempt("admin123", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+
+	// The third failure within the window locks the pair out, even with the right password
+	assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+	w := attempt("admin123", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// Other client IPs are not affected
+	assert.Equal(t, http.StatusOK, attempt("admin123", "10.0.0.2:1234").Code)
+
+	// The lockout ends when the window has elapsed
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, http.StatusTooManyRequests, attempt("admin123", "10.0.0.1:1234").Code)
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, attempt("admin123", "10.0.0.1:1234").Code)
+
+	// Failures spread over more than the window do not lock the pair out
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, attempt("wrong", "10.0.0.1:1234").Code)
+		now = now.Add(31 * time.Second)
+	}
+	assert.Equal(t, http.StatusOK, attempt("admin123", "10.0.0.1:1234").Code)
+}
+
+func TestLoginLockoutSweepExpired(t *testing.T) {
+	lockout := newLoginLockout(3, time.Minute)
+	lockout.retention = 10 * time.Minute
+	now := time.Now()
+	lockout.now = func() time.Time { return now }
+
+	lockout.fail("old|10.0.0.1")
+	for i := 0; i < 3; i++ {
+		lockout.fail("locked|10.0.0.2")
+	}
+	now = now.Add(9 * time.Minute)
+	lockout.fail("recent|10.0.0.3")
+
+	// The ended lockout expires; failures within the retention remain
+	assert.Equal(t, 1, lockout.sweepExpired())
+	assert.NotContains(t, lockout.entries, "locked|10.0.0.2")
+	assert.Len(t, lockout.entries, 2)
+
+	// The old failure expires once past the retention; the recent one remains
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 1, lockout.sweepExpired())
+	assert.Contains(t, lockout.entries, "recent|10.0.0.3")
+	assert.Len(t, lockout.entries, 1)
+
+	// An active lockout is kept even past the retention
+	for i := 0; i < 3; i++ {
+		lockout.fail("locked|10.0.0.2")
+	}
+	lockout.entries["locked|10.0.0.2"].lockedUntil = now.Add(time.Hour)
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, 1, lockout.sweepExpired())
+	assert.Contains(t, lockout.entries, "locked|10.0.0.2")
+}
+
+func TestJWTMinterLoginLockoutSweep(t *testing.T) {
+	minter, err := NewJWTMinter(map[string]User{"admin": {Username: "admin", Password: "admin123"}})
+	require.NoError(t, err)
+
+	// Without a lockout the sweep is a no-op
+	minter.StartLoginLockoutSweep(time.Millisecond, time.Hour)
+	minter.StopLoginLockoutSweep()
+
+	minter.SetLoginLockout(3, time.Minute)
+	minter.StartLoginLockoutSweep(time.Millisecond, time.Second)
+	defer minter.StopLoginLockoutSweep()
+	// Retention shorter than the window is raised to it
+	minter.lockout.mu.Lock()
+	assert.Equal(t, time.Minute, minter.lockout.retention)
+	minter.lockout.entries["stale"] = &loginFailures{times: []time.Time{time.Now().Add(-2 * time.Minute)}}
+	minter.lockout.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		minter.lockout.mu.Lock()
+		defer minter.lockout.mu.Unlock()
+		return len(minter.lockout.entries) == 0
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxTrackedLogins is the number of username/IP pairs above which stale entries
+// are swept before tracking another one
+const maxTrackedLogins = 10000
+
+// loginLockout tracks failed logins per username and client IP and locks a pair
+// out for window once it reaches maxFailures failures within window. Keying on
+// the pair keeps an attacker elsewhere from locking the real user out.
+type loginLockout struct {
+	mu          sync.Mutex
+	maxFailures int
+	window      time.Duration
+	// retention is how long a pair without activity is kept before sweeps remove it;
+	// never shorter than window so a sweep cannot cut a lockout short
+	retention time.Duration
+	now       func() time.Time
+	entries   map[string]*loginFailures
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// loginFailures holds the recent failures of a username/IP pair
+type loginFailures struct {
+	times       []time.Time
+	lockedUntil time.Time
+}
+
+func newLoginLockout(maxFailures int, window time.Duration) *loginLockout {
+	return &loginLockout{
+		maxFailures: maxFailures,
+		window:      window,
+		retention:   window,
+		now:         time.Now,
+		entries:     make(map[string]*loginFailures),
+	}
+}
+
+// lockedFor returns how long the pair stays locked out, or zero if it is not
+func (l *loginLockout) lockedFor(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[key]
+	if !ok {
+		return 0
+	}
+	return max(e.lockedUntil.Sub(l.now()), 0)
+}
+
+// fail records a failed login and locks the pair out once it has too many recent failures
+func (l *loginLockout) fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	e, ok := l.entries[key]
+	if !ok {
+		if len(l.entries) >= maxTrackedLogins {
+			l.sweep(now)
+		}
+		e = &loginFailures{}
+		l.entries[key] = e
+	}
+
+	// Keep only the failures within the window
+	recent := e.times[:0]
+	for _, t := range e.times {
+		if now.Sub(t) < l.window {
+			recent = append(recent, t)
+		}
+	}
+	e.times = append(recent, now)
+
+	if len(e.times) >= l.maxFailures {
+		e.lockedUntil = now.Add(l.window)
+		e.times = nil
+	}
+}
+
+// succeed clears the failures of the pair after a successful login
+func (l *loginLockout) succeed(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, key)
+}
+
+// sweep removes the pairs that are neither locked nor have failures within the
+// retention; callers must hold mu. It returns the number of pairs removed.
+func (l *loginLockout) sweep(now time.Time) int {
+	removed := 0
+	for key, e := range l.entries {
+		stale := now.After(e.lockedUntil)
+		for _, t := range e.times {
+			if now.Sub(t) < l.retention {
+				stale = false
+				break
+			}
+		}
+		if stale {
+			delete(l.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// sweepExpired removes the expired pairs and returns how many were removed
+func (l *loginLockout) sweepExpired() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sweep(l.now())
+}
+
+// start sweeps expired pairs every interval until stop is called
+func (l *loginLockout) start(interval time.Duration) {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.sweepExpired()
+			}
+		}
+	}()
+}
+
+// stopSweep stops the background sweep started by start
+func (l *loginLockout) stopSweep() {
+	if l.stop == nil {
+		return
+	}
+	close(l.stop)
+	<-l.done
+	l.stop = nil
+}
+
+// remoteIP returns the host of the request's remote address
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUserExists is returned when creating a user whose username is taken
+var ErrUserExists = errors.New("user already exists")
+
+// UserStore persists users created at runtime
+type UserStore interface {
+	// List returns all stored users
+	List(ctx context.Context) ([]User, error)
+	// Create stores a new user, returning ErrUserExists if the username is taken
+	Create(ctx context.Context, user User) error
+}
+
+// HashPassword returns the bcrypt hash of a password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPasswordHash reports whether password matches the bcrypt hash
+func CheckPasswordHash(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// CreateUserRequest represents a request to create a user
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateUserResponse represents a created user
+type CreateUserResponse struct {
+	Username string `json:"username"`
+}
+
+// Roles reported by WhoAmIHandler
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// WhoAmIResponse describes the identity a token authenticates
+type WhoAmIResponse struct {
+	Subject   string    `json:"subject"`
+	Role      string    `json:"role"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UsersHandler exposes user administration endpoints
+type UsersHandler struct {
+	minter *JWTMinter
+	store  UserStore
+	admins map[string]struct{}
+}
+
+// NewUsersHandler creates a UsersHandler; only the given admins may create users
+func NewUsersHandler(minter *JWTMinter, store UserStore, admins []string) *UsersHandler {
+	adminSet := make(map[string]struct{}, len(admins))
+	for _, a := range admins {
+		adminSet[a] = struct{}{}
+	}
+	return &UsersHandler{
+		minter: minter,
+		store:  store,
+		admins: adminSet,
+	}
+}
+
+// CreateUserHandler handles POST requests to create a user with a hashed password
+func (h *UsersHandler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+	token, err := h.minter.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+	subject, _ := token.Subject()
+	if _, ok := h.admins[subject]; !ok {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		http.Error(w, "Username and password are required", http.StatusBadRequest)
+		return
+	}
+	if h.minter.HasUser(req.Username) {
+		http.Error(w, "User already exists", http.StatusConflict)
+		return
+	}
+
+	hash, err := HashPassword(req.Password)
+	if err != nil {
+		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+		return
+	}
+	user := User{Username: req.Username, PasswordHash: hash}
+	if err := h.store.Create(r.Context(), user); err != nil {
+		if errors.Is(err, ErrUserExists) {
+			http.Error(w, "User already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		return
+	}
+	h.minter.AddUser(user)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateUserResponse{Username: user.Username})
+}
+
+// WhoAmIHandler handles GET requests for the identity of the request's token,
+// so clients can show the current user and whether they are an admin
+func (h *UsersHandler) WhoAmIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+	token, err := h.minter.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	resp := WhoAmIResponse{Role: RoleUser}
+	resp.Subject, _ = token.Subject()
+	if _, ok := h.admins[resp.Subject]; ok {
+		resp.Role = RoleAdmin
+	}
+	resp.IssuedAt, _ = token.IssuedAt()
+	resp.ExpiresAt, _ = token.Expiration()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Mount mounts the user administration routes to the given mux
+func (h *UsersHandler) Mount(mux *http.ServeMux) {
+	mux.HandleFunc("POST /users", h.CreateUserHandler)
+	mux.HandleFunc("GET /auth/whoami", h.WhoAmIHandler)
+}
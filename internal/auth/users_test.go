@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserStore is an in-memory UserStore for testing
+type memoryUserStore struct {
+	users map[string]User
+}
+
+func (s *memoryUserStore) List(ctx context.Context) ([]User, error) {
+	users := make([]User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (s *memoryUserStore) Create(ctx context.Context, user User) error {
+	if _, ok := s.users[user.Username]; ok {
+		return ErrUserExists
+	}
+	s.users[user.Username] = user
+	return nil
+}
+
+func TestUsersHandler(t *testing.T) {
+	minter, err := NewJWTMinter(map[string]User{
+		"admin": {Username: "admin", Password: "admin123"},
+		"user":  {Username: "user", Password: "user123"},
+	})
+	require.NoError(t, err)
+	store := &memoryUserStore{users: make(map[string]User)}
+
+	mux := http.NewServeMux()
+	minter.Mount(mux)
+	NewUsersHandler(minter, store, []string{"admin"}).Mount(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	post := func(path, token string, body any) *http.Response {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewReader(b))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+	login := func(username, password string) (string, int) {
+		resp := post("/auth/login", "", LoginRequest{Username: username, Password: password})
+		defer resp.Body.Close()
+		var loginResp LoginResponse
+		json.NewDecoder(resp.Body).Decode(&loginResp)
+		return loginResp.Token, resp.StatusCode
+	}
+
+	adminToken, status := login("admin", "admin123")
+	require.Equal(t, http.StatusOK, status)
+	userToken, status := login("user", "user123")
+	require.Equal(t, http.StatusOK, status)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		resp := post("/users", "", CreateUserRequest{Username: "carol", Password: "carol123"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("non-admin", func(t *testing.T) {
+		resp := post("/users", userToken, CreateUserRequest{Username: "carol", Password: "carol123"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("admin creates user with hashed password", func(t *testing.T) {
+		resp := post("/users", adminToken, CreateUserRequest{Username: "carol", Password: "carol123"})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		stored := store.users["carol"]
+		assert.Empty(t, stored.Password)
+		assert.NotEqual(t, "carol123", stored.PasswordHash)
+		assert.True(t, CheckPasswordHash(stored.PasswordHash, "carol123"))
+
+		// The new user can log in right away
+		_, status := login("carol", "carol123")
+		assert.Equal(t, http.StatusOK, status)
+		_, status = login("carol", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("duplicate user", func(t *testing.T) {
+		resp := post("/users", adminToken, CreateUserRequest{Username: "user", Password: "other"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		resp := post("/users", adminToken, CreateUserRequest{Username: "dave"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestWhoAmIHandler(t *testing.T) {
+	minter, err := NewJWTMinter(map[string]User{
+		"admin": {Username: "admin", Password: "admin123"},
+		"user":  {Username: "user", Password: "user123"},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	minter.Mount(mux)
+	NewUsersHandler(minter, &memoryUserStore{users: make(map[string]User)}, []string{"admin"}).Mount(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	login := func(username, password string) string {
+		b, err := json.Marshal(LoginRequest{Username: username, Password: password})
+		require.NoError(t, err)
+		resp, err := http.Post(server.URL+"/auth/login", "application/json", bytes.NewReader(b))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var loginResp LoginResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&loginResp))
+		return loginResp.Token
+	}
+	whoami := func(authorization string) (*http.Response, WhoAmIResponse) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/auth/whoami", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body WhoAmIResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		}
+		return resp, body
+	}
+
+	before := time.Now().Add(-time.Second)
+	resp, body := whoami("Bearer " + login("user", "user123"))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "user", body.Subject)
+	assert.Equal(t, RoleUser, body.Role)
+	assert.True(t, body.IssuedAt.After(before))
+	assert.True(t, body.ExpiresAt.After(body.IssuedAt))
+
+	_, body = whoami("Bearer " + login("admin", "admin123"))
+	assert.Equal(t, "admin", body.Subject)
+	assert.Equal(t, RoleAdmin, body.Role)
+
+	resp, _ = whoami("")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = whoami("Bearer not-a-token")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
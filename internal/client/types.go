@@ -1,11 +1,15 @@
 package client
 
+import "time"
+
 // Split represents a document split
 type Split struct {
 	SplitID   string     `json:"split_id"`
 	ClientID  string     `json:"client_id"`
 	Status    string     `json:"status"`
 	Documents []Document `json:"documents"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 // Document represents a document in a split
@@ -59,19 +63,32 @@ type MovePagesResponse struct {
 
 // MetricsResponse represents server metrics
 type MetricsResponse struct {
-	UptimeSeconds     float64     `json:"uptime_seconds"`
-	RequestsTotal     int64       `json:"requests_total"`
-	ErrorsTotal       int64       `json:"errors_total"`
-	LastError         interface{} `json:"last_error"`
-	AvgDurationMs     float64     `json:"avg_duration_ms"`
-	TotalResponseMB   float64     `json:"total_response_mb"`
-	ActiveConnections int32       `json:"active_connections"`
-	RateLimitHits     int64       `json:"rate_limit_hits"`
+	UptimeSeconds     float64                     `json:"uptime_seconds"`
+	RequestsTotal     int64                       `json:"requests_total"`
+	ErrorsTotal       int64                       `json:"errors_total"`
+	LastError         interface{}                 `json:"last_error"`
+	AvgDurationMs     float64                     `json:"avg_duration_ms"`
+	TotalResponseMB   float64                     `json:"total_response_mb"`
+	ActiveConnections int32                       `json:"active_connections"`
+	RateLimitHits     int64                       `json:"rate_limit_hits"`
+	DomainErrorsTotal map[string]int64            `json:"domain_errors_total"`
+	LatencySeconds    map[string]LatencyHistogram `json:"latency_seconds"`
+	Counters          map[string]uint64           `json:"counters"`
+	RendersInFlight   int64                       `json:"renders_in_flight"`
+	RendersWaiting    int64                       `json:"renders_waiting"`
+}
+
+// LatencyHistogram represents cumulative latency buckets for one operation
+type LatencyHistogram struct {
+	Buckets    map[string]uint64 `json:"buckets"`
+	Count      uint64            `json:"count"`
+	SumSeconds float64           `json:"sum_seconds"`
 }
 
 // PageResponse represents a page response
 type PageResponse struct {
 	ID         string `json:"id"`
 	PageNumber string `json:"page_number"`
+	Number     int    `json:"number"`
 	URL        string `json:"url"`
 }
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for the application
@@ -14,21 +18,147 @@ type Config struct {
 	Host            string `envconfig:"HOST" default:"localhost"`
 	ShutdownTimeout int    `envconfig:"SHUTDOWN_TIMEOUT" default:"10"` // in seconds
 
-	// Database configuration
-	DatabasePath string `envconfig:"DB_PATH" default:"accounting.db"`
+	// Database configuration: the primary takes all writes; read-only requests use the
+	// read replica when DB_REPLICA_PATH is set and the primary otherwise
+	DatabasePath        string `envconfig:"DB_PATH" default:"accounting.db"`
+	ReplicaDatabasePath string `envconfig:"DB_REPLICA_PATH"`
+	// Connection pool of the databases: open connections of the primary's write path
+	// (0 is unlimited; SQLite allows one writer at a time, so more only contend for its
+	// lock), idle connections kept, and how long a connection is reused (0 reuses it
+	// forever). Read-only requests use a pool of their own, on the read replica or the
+	// primary, which is not capped, as reads do not contend.
+	DBMaxOpenConns    int `envconfig:"DB_MAX_OPEN_CONNS" default:"1"`
+	DBMaxIdleConns    int `envconfig:"DB_MAX_IDLE_CONNS" default:"1"`
+	DBConnMaxLifetime int `envconfig:"DB_CONN_MAX_LIFETIME" default:"0"` // in seconds
+
+	// Transactions open longer than this are rolled back as abandoned (0 disables the sweep)
+	TxMaxAge int `envconfig:"TX_MAX_AGE" default:"60"` // in seconds
+
+	// Debug aid: after saving a split, reload its page counts and log a warning if they
+	// differ from the saved aggregate
+	VerifySplitSaves bool `envconfig:"VERIFY_SPLIT_SAVES" default:"false"`
+
+	// Directory holding the page content referenced by page URLs
+	BlobRoot string `envconfig:"BLOB_ROOT" default:"pages"`
+
+	// Base URL relative page URLs (e.g. page_1.png) are resolved against in API
+	// responses; URLs with a scheme are left as they are. Empty leaves URLs as stored.
+	PageURLBase string `envconfig:"PAGE_URL_BASE"`
+
+	// Backend rendering documents for download: placeholder (no dependencies, for tests and
+	// development), pdfcpu or imagemagick (which need the pdfcpu or magick command on PATH).
+	// Split previews can only merge the placeholder's uncompressed PDFs.
+	Renderer string `envconfig:"RENDERER" default:"placeholder"`
+
+	// Number of rendered documents kept in memory (0 disables the cache)
+	RenderCacheSize int `envconfig:"RENDER_CACHE_SIZE" default:"128"`
+
+	// Number of loaded splits kept in memory (0 disables the cache) and how long
+	// each is kept (0 keeps it until the split changes or it is evicted)
+	SplitCacheSize int `envconfig:"SPLIT_CACHE_SIZE" default:"256"`
+	SplitCacheTTL  int `envconfig:"SPLIT_CACHE_TTL" default:"60"` // in seconds
+
+	// Renders running at once (0 disables the limit) and renders allowed to wait for
+	// a slot; requests beyond the queue, or whose deadline passes while waiting, get 503
+	MaxConcurrentRenders int `envconfig:"MAX_CONCURRENT_RENDERS" default:"4"`
+	RenderQueueSize      int `envconfig:"RENDER_QUEUE_SIZE" default:"32"`
+
+	// Finalize webhook; events are only queued in the outbox, and delivered, when a URL is set
+	FinalizeWebhookURL string `envconfig:"FINALIZE_WEBHOOK_URL"`
+	OutboxPollInterval int    `envconfig:"OUTBOX_POLL_INTERVAL" default:"5"` // in seconds
+	OutboxMaxAttempts  int    `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+
+	// How often orphaned documents and pages are found and repaired in the background
+	// (0 disables the check; POST /admin/consistency-check runs it on demand)
+	ConsistencyCheckInterval int `envconfig:"CONSISTENCY_CHECK_INTERVAL" default:"0"` // in seconds
+
+	// Unassigned pages above which split responses carry a cleanup warning (0 disables it)
+	MaxUnassignedPages int `envconfig:"MAX_UNASSIGNED_PAGES" default:"0"`
+
+	// Pages allowed in a split at ingestion, passed to domain.NewSplit via
+	// domain.WithMaxPages (0 disables the cap)
+	MaxPagesPerSplit int `envconfig:"MAX_PAGES_PER_SPLIT" default:"0"`
+
+	// Page IDs allowed in a single move or create-document request (0 disables the cap)
+	MaxPageBatch int `envconfig:"MAX_PAGE_BATCH" default:"1000"`
+
+	// Template naming documents created without a name; {classification} and {n} (the
+	// next index for that classification in the split) are substituted. Empty requires a name.
+	DocumentNameTemplate string `envconfig:"DOCUMENT_NAME_TEMPLATE" default:"{classification} ({n})"`
+
+	// How long a split lock lasts before it expires
+	SplitLockTTL int `envconfig:"SPLIT_LOCK_TTL" default:"300"` // in seconds
+
+	// Refuse to finalize splits until every document is marked reviewed
+	RequireReviewBeforeFinalize bool `envconfig:"REQUIRE_REVIEW_BEFORE_FINALIZE" default:"false"`
+
+	// Refuse to finalize splits with documents left unclassified: without a classification
+	// or with the default "unclassified"
+	RequireClassificationOnFinalize bool `envconfig:"REQUIRE_CLASSIFICATION_ON_FINALIZE" default:"false"`
+
+	// How document filenames with path components or unsafe characters are handled on
+	// create, update and download: strip or replace (with "_") the offending characters,
+	// or reject the request (downloads of already stored names fall back to replace).
+	// Empty leaves filenames as given.
+	FilenamePolicy string `envconfig:"FILENAME_POLICY" default:"replace"`
+
+	// Classifications documents may be reclassified to (comma separated; empty allows any)
+	AllowedClassifications []string `envconfig:"ALLOWED_CLASSIFICATIONS"`
+
+	// Reject POST/PATCH/DELETE bodies that are not application/json with 415
+	RequireJSONContentType bool `envconfig:"REQUIRE_JSON_CONTENT_TYPE" default:"true"`
+
+	// Reject requests with query parameters their route does not read with 400
+	StrictQueryParams bool `envconfig:"STRICT_QUERY_PARAMS" default:"false"`
+
+	// Never compress responses, e.g. when a proxy in front of the app compresses them
+	DisableCompression bool `envconfig:"DISABLE_COMPRESSION" default:"false"`
+	// Response encodings offered to clients (comma separated br, gzip), in order of
+	// preference when the client accepts several equally
+	CompressionEncodings []string `envconfig:"COMPRESSION_ENCODINGS" default:"br,gzip"`
+
+	// Requests taking longer than this are logged as slow and counted in
+	// slow_requests_total on /metrics (0 disables the check)
+	SlowRequestThreshold int `envconfig:"SLOW_REQUEST_THRESHOLD" default:"1000"` // in milliseconds
 
 	// Rate limiting
 	RequestsPerSecond int `envconfig:"REQUESTS_PER_SECOND" default:"100"`
 	BurstSize         int `envconfig:"BURST_SIZE" default:"200"`
 
-	// Users configuration
-	Users []User `envconfig:"USERS" required:"true"`
+	// Failed logins from a username and client IP within LOGIN_FAILURE_WINDOW after which
+	// that pair is locked out for the window (0 disables the lockout)
+	LoginMaxFailures   int `envconfig:"LOGIN_MAX_FAILURES" default:"5"`
+	LoginFailureWindow int `envconfig:"LOGIN_FAILURE_WINDOW" default:"900"` // in seconds
+	// How often expired failed-login records and lockouts are swept (0 disables the sweep)
+	// and how long a username/IP pair without activity is kept (at least the failure window)
+	LoginLockoutSweepInterval int `envconfig:"LOGIN_LOCKOUT_SWEEP_INTERVAL" default:"60"` // in seconds
+	LoginLockoutRetention     int `envconfig:"LOGIN_LOCKOUT_RETENTION" default:"3600"`    // in seconds
+
+	// Browser origins allowed to call the API cross-origin (comma separated, * for any);
+	// CORS is off when empty
+	CORSAllowedOrigins []string `envconfig:"CORS_ALLOWED_ORIGINS"`
+	// How long browsers may cache a CORS preflight answer (0 makes them send one each time)
+	CORSMaxAge int `envconfig:"CORS_MAX_AGE" default:"600"` // in seconds
+
+	// Trusted proxies (comma separated CIDRs) allowed to set X-Forwarded-For
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+
+	// JWT signing keys as kid:secret pairs; new tokens are signed with JWT_ACTIVE_KID and
+	// the other keys are still accepted for verification. A random key is used when unset.
+	JWTKeys        []JWTKey `envconfig:"JWT_KEYS"`
+	JWTActiveKeyID string   `envconfig:"JWT_ACTIVE_KID"`
+
+	// Users configuration. USERS_FILE takes precedence over USERS when set.
+	Users      []User   `envconfig:"USERS"`
+	UsersFile  string   `envconfig:"USERS_FILE"`
+	AdminUsers []string `envconfig:"ADMIN_USERS" default:"admin"`
 }
 
 // User represents a user in the system
 type User struct {
-	Username string
-	Password string
+	Username     string `json:"username" yaml:"username"`
+	Password     string `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordHash string `json:"password_hash,omitempty" yaml:"password_hash,omitempty"` // bcrypt hash, used instead of Password
 }
 
 // Decode implements envconfig.Decoder for User
@@ -42,6 +172,23 @@ func (u *User) Decode(value string) error {
 	return nil
 }
 
+// JWTKey is a named HMAC secret for signing or verifying JWTs
+type JWTKey struct {
+	ID     string
+	Secret string
+}
+
+// Decode implements envconfig.Decoder for JWTKey
+func (k *JWTKey) Decode(value string) error {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid JWT key format, expected kid:secret")
+	}
+	k.ID = parts[0]
+	k.Secret = parts[1]
+	return nil
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	var cfg Config
@@ -49,9 +196,104 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("env config error: %w", err)
 	}
+
+	if cfg.UsersFile != "" {
+		users, err := loadUsersFile(cfg.UsersFile)
+		if err != nil {
+			return nil, fmt.Errorf("users file error: %w", err)
+		}
+		cfg.Users = users
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
+// Validate checks settings that envconfig cannot check on its own
+func (c *Config) Validate() error {
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("env config error: SHUTDOWN_TIMEOUT must be positive, got %d", c.ShutdownTimeout)
+	}
+	if c.DBMaxOpenConns < 0 {
+		return fmt.Errorf("env config error: DB_MAX_OPEN_CONNS must not be negative, got %d", c.DBMaxOpenConns)
+	}
+	if c.DBMaxIdleConns < 0 {
+		return fmt.Errorf("env config error: DB_MAX_IDLE_CONNS must not be negative, got %d", c.DBMaxIdleConns)
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		return fmt.Errorf("env config error: DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS (%d), got %d", c.DBMaxOpenConns, c.DBMaxIdleConns)
+	}
+	if c.DBConnMaxLifetime < 0 {
+		return fmt.Errorf("env config error: DB_CONN_MAX_LIFETIME must not be negative, got %d", c.DBConnMaxLifetime)
+	}
+	if c.RequestsPerSecond <= 0 {
+		return fmt.Errorf("env config error: REQUESTS_PER_SECOND must be positive, got %d", c.RequestsPerSecond)
+	}
+	if c.BurstSize <= 0 {
+		return fmt.Errorf("env config error: BURST_SIZE must be positive, got %d", c.BurstSize)
+	}
+	if len(c.JWTKeys) > 0 && c.JWTActiveKeyID == "" {
+		return fmt.Errorf("env config error: JWT_ACTIVE_KID is required when JWT_KEYS is set")
+	}
+	switch c.FilenamePolicy {
+	case "", "strip", "replace", "reject":
+	default:
+		return fmt.Errorf("env config error: FILENAME_POLICY must be strip, replace or reject, got %q", c.FilenamePolicy)
+	}
+	for _, encoding := range c.CompressionEncodings {
+		switch encoding {
+		case "br", "gzip":
+		default:
+			return fmt.Errorf("env config error: COMPRESSION_ENCODINGS must list br or gzip, got %q", encoding)
+		}
+	}
+	if c.OutboxPollInterval <= 0 {
+		return fmt.Errorf("env config error: OUTBOX_POLL_INTERVAL must be positive, got %d", c.OutboxPollInterval)
+	}
+	if c.ConsistencyCheckInterval < 0 {
+		return fmt.Errorf("env config error: CONSISTENCY_CHECK_INTERVAL must not be negative, got %d", c.ConsistencyCheckInterval)
+	}
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("env config error: CORS_MAX_AGE must not be negative, got %d", c.CORSMaxAge)
+	}
+	switch c.Renderer {
+	case "", "placeholder", "pdfcpu", "imagemagick":
+	default:
+		return fmt.Errorf("env config error: RENDERER must be placeholder, pdfcpu or imagemagick, got %q", c.Renderer)
+	}
+	if len(c.Users) == 0 {
+		return fmt.Errorf("env config error: required key USERS missing value (set APP_USERS or APP_USERS_FILE)")
+	}
+	return nil
+}
+
+// loadUsersFile reads users from a JSON or YAML file, chosen by file extension
+func loadUsersFile(path string) ([]User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &users)
+	default:
+		err = json.Unmarshal(data, &users)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid users file %s: %w", path, err)
+	}
+
+	for _, u := range users {
+		if u.Username == "" || (u.Password == "" && u.PasswordHash == "") {
+			return nil, fmt.Errorf("invalid users file %s: every user needs a username and a password or password_hash", path)
+		}
+	}
+	return users, nil
+}
+
 // GetUsersMap converts the users slice to a map for easier lookup
 func (c *Config) GetUsersMap() map[string]User {
 	users := make(map[string]User)
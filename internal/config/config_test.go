@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,8 +24,22 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "localhost", cfg.Host)
 	assert.Equal(t, 10, cfg.ShutdownTimeout)
 	assert.Equal(t, "accounting.db", cfg.DatabasePath)
+	assert.Empty(t, cfg.ReplicaDatabasePath)
+	assert.Equal(t, 1, cfg.DBMaxOpenConns)
+	assert.Equal(t, 1, cfg.DBMaxIdleConns)
+	assert.Zero(t, cfg.DBConnMaxLifetime)
+	assert.False(t, cfg.RequireReviewBeforeFinalize)
+	assert.False(t, cfg.RequireClassificationOnFinalize)
+	assert.False(t, cfg.StrictQueryParams)
+	assert.Equal(t, "pages", cfg.BlobRoot)
+	assert.Equal(t, 128, cfg.RenderCacheSize)
+	assert.Equal(t, "placeholder", cfg.Renderer)
 	assert.Equal(t, 100, cfg.RequestsPerSecond)
 	assert.Equal(t, 200, cfg.BurstSize)
+	assert.Equal(t, []string{"admin"}, cfg.AdminUsers)
+	assert.Empty(t, cfg.CORSAllowedOrigins)
+	assert.Equal(t, 600, cfg.CORSMaxAge)
+	assert.Zero(t, cfg.ConsistencyCheckInterval)
 
 	// Verify users
 	require.Len(t, cfg.Users, 2)
@@ -89,3 +104,216 @@ func TestLoadConfigWithoutRequiredUsers(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "required key USERS missing value")
 }
+
+func TestLoadConfigRejectsNonPositiveShutdownTimeout(t *testing.T) {
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_SHUTDOWN_TIMEOUT", "0")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_SHUTDOWN_TIMEOUT")
+	}()
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "SHUTDOWN_TIMEOUT must be positive")
+}
+
+func TestLoadConfigRejectsUnknownRenderer(t *testing.T) {
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_RENDERER", "gofpdf")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_RENDERER")
+	}()
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `RENDERER must be placeholder, pdfcpu or imagemagick, got "gofpdf"`)
+}
+
+func TestLoadConfigRejectsUnknownCompressionEncoding(t *testing.T) {
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_COMPRESSION_ENCODINGS", "br,deflate")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_COMPRESSION_ENCODINGS")
+	}()
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `COMPRESSION_ENCODINGS must list br or gzip, got "deflate"`)
+}
+
+func TestLoadConfigRejectsNegativeCORSMaxAge(t *testing.T) {
+	os.Setenv("APP_USERS", "admin:admin123")
+	os.Setenv("APP_CORS_MAX_AGE", "-1")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_CORS_MAX_AGE")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CORS_MAX_AGE must not be negative, got -1")
+}
+
+func TestLoadConfigValidatesDBPool(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{env: map[string]string{"APP_DB_MAX_OPEN_CONNS": "-1"}, want: "DB_MAX_OPEN_CONNS must not be negative, got -1"},
+		{env: map[string]string{"APP_DB_MAX_IDLE_CONNS": "-1"}, want: "DB_MAX_IDLE_CONNS must not be negative, got -1"},
+		{env: map[string]string{"APP_DB_MAX_OPEN_CONNS": "2", "APP_DB_MAX_IDLE_CONNS": "3"}, want: "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS (2), got 3"},
+		{env: map[string]string{"APP_DB_CONN_MAX_LIFETIME": "-30"}, want: "DB_CONN_MAX_LIFETIME must not be negative, got -30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Setenv("APP_USERS", "admin:admin123")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := Load()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	// Any number of idle connections is allowed when open connections are unlimited
+	t.Setenv("APP_USERS", "admin:admin123")
+	t.Setenv("APP_DB_MAX_OPEN_CONNS", "0")
+	t.Setenv("APP_DB_MAX_IDLE_CONNS", "4")
+	t.Setenv("APP_DB_CONN_MAX_LIFETIME", "300")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 300, cfg.DBConnMaxLifetime)
+}
+
+func TestLoadConfigRejectsNegativeConsistencyCheckInterval(t *testing.T) {
+	os.Setenv("APP_USERS", "admin:admin123")
+	os.Setenv("APP_CONSISTENCY_CHECK_INTERVAL", "-5")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_CONSISTENCY_CHECK_INTERVAL")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CONSISTENCY_CHECK_INTERVAL must not be negative, got -5")
+}
+
+func TestLoadConfigRejectsNonPositiveOutboxPollInterval(t *testing.T) {
+	for _, value := range []string{"0", "-1"} {
+		t.Run(value, func(t *testing.T) {
+			os.Setenv("APP_USERS", "test:test123")
+			os.Setenv("APP_OUTBOX_POLL_INTERVAL", value)
+			defer func() {
+				os.Unsetenv("APP_USERS")
+				os.Unsetenv("APP_OUTBOX_POLL_INTERVAL")
+			}()
+
+			_, err := Load()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "OUTBOX_POLL_INTERVAL must be positive, got "+value)
+		})
+	}
+}
+
+func TestLoadConfigRejectsNonPositiveRateLimits(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "APP_REQUESTS_PER_SECOND", want: "REQUESTS_PER_SECOND must be positive"},
+		{key: "APP_BURST_SIZE", want: "BURST_SIZE must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			os.Setenv("APP_USERS", "test:test123")
+			os.Setenv(tt.key, "0")
+			defer func() {
+				os.Unsetenv("APP_USERS")
+				os.Unsetenv(tt.key)
+			}()
+
+			_, err := Load()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoadConfigWithTrustedProxies(t *testing.T) {
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_TRUSTED_PROXIES", "10.0.0.0/8,192.168.1.10")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_TRUSTED_PROXIES")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, cfg.TrustedProxies)
+}
+
+func TestLoadConfigWithUsersFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "users.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`[
+		{"username": "alice", "password": "alice123"},
+		{"username": "bob", "password_hash": "$2a$10$abcdefghijklmnopqrstuv"}
+	]`), 0o600))
+	yamlPath := filepath.Join(dir, "users.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("- username: carol\n  password: carol123\n"), 0o600))
+
+	// File users take precedence over env users
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_USERS_FILE", jsonPath)
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_USERS_FILE")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.Users, 2)
+	assert.Equal(t, "alice", cfg.Users[0].Username)
+	assert.Equal(t, "alice123", cfg.Users[0].Password)
+	assert.Equal(t, "bob", cfg.Users[1].Username)
+	assert.NotEmpty(t, cfg.Users[1].PasswordHash)
+
+	// YAML files are supported as well
+	os.Setenv("APP_USERS_FILE", yamlPath)
+	cfg, err = Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.Users, 1)
+	assert.Equal(t, "carol", cfg.Users[0].Username)
+
+	// Missing file is an error
+	os.Setenv("APP_USERS_FILE", filepath.Join(dir, "missing.json"))
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoadConfigWithJWTKeys(t *testing.T) {
+	os.Setenv("APP_USERS", "test:test123")
+	os.Setenv("APP_JWT_KEYS", "2024-01:old:secret,2024-02:new-secret")
+	defer func() {
+		os.Unsetenv("APP_USERS")
+		os.Unsetenv("APP_JWT_KEYS")
+		os.Unsetenv("APP_JWT_ACTIVE_KID")
+	}()
+
+	// The active key must be named
+	_, err := Load()
+	assert.Error(t, err)
+
+	os.Setenv("APP_JWT_ACTIVE_KID", "2024-02")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []JWTKey{{ID: "2024-01", Secret: "old:secret"}, {ID: "2024-02", Secret: "new-secret"}}, cfg.JWTKeys)
+	assert.Equal(t, "2024-02", cfg.JWTActiveKeyID)
+}
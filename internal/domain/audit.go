@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// AuditActionForceDeleteSplit records an admin deleting a split regardless of its status
+const AuditActionForceDeleteSplit = "split.force_delete"
+
+// AuditActionImpersonateClient records an admin reading data on behalf of a client
+const AuditActionImpersonateClient = "client.impersonate"
+
+// AuditActionRepairOrphans records an admin repairing orphaned documents and pages
+const AuditActionRepairOrphans = "consistency.repair"
+
+// AuditEntry records a privileged action for later review
+type AuditEntry struct {
+	ID         string
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID string
+	CreatedAt  time.Time
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // SplitStatus represents whether a split is still editable or has been finalized.
 type SplitStatus string
@@ -10,5 +13,61 @@ const (
 	SplitStatusFinalized SplitStatus = "finalized"
 )
 
+// Valid returns a validation error unless the status is draft or finalized. It is
+// checked on ingestion rather than in UnmarshalJSON, so responses carrying a
+// stored status always decode.
+func (s SplitStatus) Valid() error {
+	switch s {
+	case SplitStatusDraft, SplitStatusFinalized:
+		return nil
+	case "":
+		return NewValidationError("split status is required", nil)
+	default:
+		return NewValidationError(fmt.Sprintf("unknown split status %q: must be %s or %s", string(s), SplitStatusDraft, SplitStatusFinalized), nil)
+	}
+}
+
 // ErrNotFound is returned when a requested resource is not found
 var ErrNotFound = errors.New("not found")
+
+// ErrSplitFinalized matches the conflicts of operations refused because the split is finalized
+var ErrSplitFinalized = errors.New("split finalized")
+
+// ClientStats holds aggregate counts over all splits of one client
+type ClientStats struct {
+	TotalSplits     int
+	DraftSplits     int
+	FinalizedSplits int
+	TotalDocuments  int
+	TotalPages      int
+}
+
+// ClientPage references a page of a client's document together with the
+// document and split it belongs to
+type ClientPage struct {
+	PageID     string
+	PageNumber int
+	URL        string
+	DocumentID string
+	SplitID    string
+	SplitName  string
+}
+
+// Orphans are rows left behind when deletions miss them, since the schema does
+// not cascade deletes
+type Orphans struct {
+	DocumentIDs   []string // documents whose split no longer exists
+	PageIDs       []string // pages whose split no longer exists
+	UnlinkedPages []*Page  // pages of existing splits assigned to documents that no longer exist
+}
+
+// Count returns the number of orphaned rows
+func (o *Orphans) Count() int {
+	return len(o.DocumentIDs) + len(o.PageIDs) + len(o.UnlinkedPages)
+}
+
+// ClientSummary holds a client ID and the number of splits it owns
+type ClientSummary struct {
+	ClientID   string
+	SplitCount int
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"fmt"
 	"slices"
+	"strings"
+	"time"
 )
 
 // Document represents one contiguous chunk of pages within a Split.
@@ -15,7 +17,12 @@ type Document struct {
 	ShortDescription string  // human‐friendly summary
 	Pages            []*Page // the actual page entities
 	StartPage        string
-	EndPage          string // lowest and highest page numbers in Pages
+	EndPage          string     // lowest and highest page numbers in Pages
+	SortOrder        int        // explicit position within the split (0 = fall back to page order)
+	Reviewed         bool       // checked and approved by a reviewer
+	ReviewedBy       string     // who marked the document reviewed
+	ReviewedAt       *time.Time // when the document was marked reviewed
+	LastModifiedBy   string     // who last changed the document
 }
 
 func NewDocument(
@@ -45,21 +52,76 @@ func NewDocument(
 	return d, nil
 }
 
+// ClassificationChange records a document moving from one classification to another
+type ClassificationChange struct {
+	ID                string
+	DocumentID        string
+	OldClassification string
+	NewClassification string
+	ChangedAt         time.Time
+}
+
 // DocumentMetadata represents optional fields that can be updated on a document
 type DocumentMetadata struct {
 	Name             *string // optional new name for the document
 	Classification   *string // optional new classification
 	ShortDescription *string // optional new description
+	Filename         *string // optional new file name
 }
 
-// AddPages adds pages to the document
+// AddPages adds pages to the document. In a document whose pages were explicitly
+// ordered, the added pages follow the existing ones in page number order.
 func (d *Document) AddPages(pages []*Page) error {
+	maxOrder := 0
+	if d.HasPageOrder() {
+		for _, p := range d.Pages {
+			maxOrder = max(maxOrder, p.SortOrder)
+		}
+	}
 	for _, newPage := range pages {
 		if assignErr := newPage.AssignToDocument(d.ID); assignErr != nil {
 			return NewValidationError("failed to assign page to document", assignErr)
 		}
 		d.Pages = append(d.Pages, newPage)
 	}
+	if maxOrder > 0 {
+		added := slices.Clone(pages)
+		slices.SortStableFunc(added, func(a, b *Page) int { return a.PageNumber - b.PageNumber })
+		for i, p := range added {
+			p.SortOrder = maxOrder + i + 1
+		}
+	}
+	d.updatePageNumbers()
+	return nil
+}
+
+// HasPageOrder reports whether the document's pages were explicitly ordered, e.g. by
+// SwapPages, rather than following their page numbers
+func (d *Document) HasPageOrder() bool {
+	return slices.ContainsFunc(d.Pages, func(p *Page) bool { return p.SortOrder != 0 })
+}
+
+// SwapPages swaps the positions of two pages of the document. The first swap fixes
+// the current order of all pages, so the other pages keep their positions.
+func (d *Document) SwapPages(pageAID, pageBID string) error {
+	if pageAID == pageBID {
+		return NewValidationError("cannot swap a page with itself", nil)
+	}
+	a := slices.IndexFunc(d.Pages, func(p *Page) bool { return p.ID == pageAID })
+	if a < 0 {
+		return NewValidationError(fmt.Sprintf("page %s is not part of document %s", pageAID, d.ID), nil)
+	}
+	b := slices.IndexFunc(d.Pages, func(p *Page) bool { return p.ID == pageBID })
+	if b < 0 {
+		return NewValidationError(fmt.Sprintf("page %s is not part of document %s", pageBID, d.ID), nil)
+	}
+
+	if !d.HasPageOrder() {
+		for i, p := range d.Pages {
+			p.SortOrder = i + 1
+		}
+	}
+	d.Pages[a].SortOrder, d.Pages[b].SortOrder = d.Pages[b].SortOrder, d.Pages[a].SortOrder
 	d.updatePageNumbers()
 	return nil
 }
@@ -83,7 +145,7 @@ func (d *Document) RemovePages(pageIDs []string) ([]*Page, error) {
 		}
 	}
 	if len(removed) == 0 {
-		return nil, NewNotFoundError("none of the specified pages found in document", nil)
+		return nil, NewNotFoundError("page", strings.Join(pageIDs, ","), "none of the specified pages found in document", nil)
 	}
 	d.Pages = remaining
 	for _, page := range removed {
@@ -105,40 +167,108 @@ func (d *Document) UpdateMetadata(metadata DocumentMetadata) error {
 	if metadata.ShortDescription != nil {
 		d.ShortDescription = *metadata.ShortDescription
 	}
+	if metadata.Filename != nil {
+		d.Filename = *metadata.Filename
+	}
 	return nil
 }
 
+// SetReviewed marks the document reviewed by reviewer at the given time, or clears
+// the review when reviewed is false
+func (d *Document) SetReviewed(reviewed bool, reviewer string, at time.Time) {
+	d.Reviewed = reviewed
+	if !reviewed {
+		d.ReviewedBy = ""
+		d.ReviewedAt = nil
+		return
+	}
+	d.ReviewedBy = reviewer
+	d.ReviewedAt = &at
+}
+
 func (d *Document) Valid() error {
+	if errs := d.ValidateAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll returns every validation issue of the document and its pages, in the order Valid checks them
+func (d *Document) ValidateAll() []error {
+	var errs []error
 	if d.ID == "" {
-		return NewValidationError("document ID is required", nil)
+		errs = append(errs, NewValidationError("document ID is required", nil))
 	}
 	if d.SplitID == "" {
-		return NewValidationError("split ID is required", nil)
+		errs = append(errs, NewValidationError("split ID is required", nil))
 	}
 	if d.Name == "" {
-		return NewValidationError("document name is required", nil)
+		errs = append(errs, NewValidationError("document name is required", nil))
 	}
 	if d.Classification == "" {
-		return NewValidationError("document classification is required", nil)
+		errs = append(errs, NewValidationError("document classification is required", nil))
 	}
 	if d.Filename == "" {
-		return NewValidationError("document filename is required", nil)
+		errs = append(errs, NewValidationError("document filename is required", nil))
 	}
 	if len(d.Pages) == 0 {
-		return NewValidationError("document must have at least one page", nil)
+		errs = append(errs, NewValidationError("document must have at least one page", nil))
 	}
 	for _, page := range d.Pages {
-		if err := page.Valid(); err != nil {
-			return NewValidationError("invalid page in document", err)
+		for _, err := range page.ValidateAll() {
+			errs = append(errs, NewValidationError("invalid page in document", err))
 		}
 	}
-	return nil
+	return errs
+}
+
+// StartPageNumber returns the lowest page number in the document, or 0 if it has no pages.
+// Unlike StartPage, which holds the page URL, it orders numerically.
+func (d *Document) StartPageNumber() int {
+	start := 0
+	for _, p := range d.Pages {
+		if start == 0 || p.PageNumber < start {
+			start = p.PageNumber
+		}
+	}
+	return start
+}
+
+// EndPageNumber returns the highest page number in the document, or 0 if it has no pages.
+func (d *Document) EndPageNumber() int {
+	end := 0
+	for _, p := range d.Pages {
+		if p.PageNumber > end {
+			end = p.PageNumber
+		}
+	}
+	return end
+}
+
+// HasContiguousPages reports whether every page number from the lowest to the highest
+// is in the document, i.e. no pages are missing in between. Documents with one page
+// or none are trivially contiguous.
+func (d *Document) HasContiguousPages() bool {
+	numbers := make([]int, len(d.Pages))
+	for i, p := range d.Pages {
+		numbers[i] = p.PageNumber
+	}
+	slices.Sort(numbers)
+	for i := 1; i < len(numbers); i++ {
+		if numbers[i]-numbers[i-1] > 1 {
+			return false
+		}
+	}
+	return true
 }
 
 func (d *Document) updatePageNumbers() {
 
-	// sort pages by PageNumber
+	// sort pages by explicit position, then by PageNumber
 	slices.SortFunc(d.Pages, func(a, b *Page) int {
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder - b.SortOrder
+		}
 		if a.PageNumber < b.PageNumber {
 			return -1
 		}
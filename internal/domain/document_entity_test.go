@@ -1,9 +1,11 @@
 package domain
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDocument_UpdateMetadata(t *testing.T) {
@@ -78,3 +80,76 @@ func TestDocument_UpdateMetadata(t *testing.T) {
 
 // ptrString is a helper to get a pointer to a string literal
 func ptrString(s string) *string { return &s }
+
+func TestDocument_StartPageNumber(t *testing.T) {
+	doc := &Document{ID: "doc1", SplitID: "split1", Name: "Doc"}
+	assert.Equal(t, 0, doc.StartPageNumber())
+	assert.Equal(t, 0, doc.EndPageNumber())
+
+	doc.Pages = []*Page{{ID: "p10", PageNumber: 10}, {ID: "p2", PageNumber: 2}, {ID: "p9", PageNumber: 9}}
+	assert.Equal(t, 2, doc.StartPageNumber())
+	assert.Equal(t, 10, doc.EndPageNumber())
+}
+
+func TestDocument_SwapPages(t *testing.T) {
+	docID := "doc1"
+	newDoc := func() *Document {
+		doc := &Document{ID: docID, SplitID: "split1", Name: "Doc"}
+		for n := 1; n <= 4; n++ {
+			doc.Pages = append(doc.Pages, &Page{ID: fmt.Sprintf("p%d", n), SplitID: "split1", DocumentID: &docID, PageNumber: n, URL: fmt.Sprintf("page_%d.png", n)})
+		}
+		return doc
+	}
+	pageIDs := func(doc *Document) []string {
+		ids := make([]string, len(doc.Pages))
+		for i, p := range doc.Pages {
+			ids[i] = p.ID
+		}
+		return ids
+	}
+
+	doc := newDoc()
+	require.NoError(t, doc.SwapPages("p2", "p4"))
+	assert.Equal(t, []string{"p1", "p4", "p3", "p2"}, pageIDs(doc))
+	assert.True(t, doc.HasPageOrder())
+	assert.Equal(t, "page_1.png", doc.StartPage)
+	assert.Equal(t, "page_2.png", doc.EndPage)
+
+	// Swapping back restores the page number order
+	require.NoError(t, doc.SwapPages("p4", "p2"))
+	assert.Equal(t, []string{"p1", "p2", "p3", "p4"}, pageIDs(doc))
+
+	// Pages added to an ordered document follow the existing ones
+	require.NoError(t, doc.SwapPages("p1", "p3"))
+	require.NoError(t, doc.AddPages([]*Page{{ID: "p0", SplitID: "split1", PageNumber: 0, URL: "page_0.png"}}))
+	assert.Equal(t, []string{"p3", "p2", "p1", "p4", "p0"}, pageIDs(doc))
+
+	// Pages removed from the document lose their position
+	removed, err := doc.RemovePages([]string{"p2"})
+	require.NoError(t, err)
+	assert.Zero(t, removed[0].SortOrder)
+
+	var domainErr *DomainError
+	err = newDoc().SwapPages("p1", "other")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "page other is not part of document doc1")
+
+	err = newDoc().SwapPages("p1", "p1")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+}
+
+func TestDocument_HasContiguousPages(t *testing.T) {
+	doc := &Document{ID: "doc1", SplitID: "split1", Name: "Doc"}
+	assert.True(t, doc.HasContiguousPages(), "no pages")
+
+	doc.Pages = []*Page{{ID: "p4", PageNumber: 4}}
+	assert.True(t, doc.HasContiguousPages(), "single page")
+
+	doc.Pages = []*Page{{ID: "p5", PageNumber: 5}, {ID: "p3", PageNumber: 3}, {ID: "p4", PageNumber: 4}}
+	assert.True(t, doc.HasContiguousPages(), "unsorted without gap")
+
+	doc.Pages = []*Page{{ID: "p10", PageNumber: 10}, {ID: "p2", PageNumber: 2}, {ID: "p9", PageNumber: 9}}
+	assert.False(t, doc.HasContiguousPages(), "gap between 2 and 9")
+}
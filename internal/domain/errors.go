@@ -7,12 +7,17 @@ import "fmt"
 type DomainErrorKind string
 
 const (
-	DomainErrorValidation DomainErrorKind = "validation"
-	DomainErrorNotFound   DomainErrorKind = "not_found"
-	DomainErrorConflict   DomainErrorKind = "conflict"
-	DomainErrorInternal   DomainErrorKind = "internal"
+	DomainErrorValidation  DomainErrorKind = "validation"
+	DomainErrorNotFound    DomainErrorKind = "not_found"
+	DomainErrorConflict    DomainErrorKind = "conflict"
+	DomainErrorLocked      DomainErrorKind = "locked"
+	DomainErrorUnavailable DomainErrorKind = "unavailable"
+	DomainErrorInternal    DomainErrorKind = "internal"
 )
 
+// ReasonSplitFinalized is the reason of conflicts caused by the split being finalized
+const ReasonSplitFinalized = "split_finalized"
+
 // DomainError is a custom error type for domain logic
 // It allows classification and wrapping of errors
 // Implements the error interface
@@ -21,6 +26,11 @@ type DomainError struct {
 	Kind    DomainErrorKind
 	Message string
 	Cause   error
+	// Resource and ResourceID identify the missing resource for not-found errors
+	Resource   string
+	ResourceID string
+	// Reason tells apart errors of the same kind, e.g. ReasonSplitFinalized for conflicts
+	Reason string
 }
 
 func (e *DomainError) Error() string {
@@ -34,6 +44,18 @@ func (e *DomainError) Unwrap() error {
 	return e.Cause
 }
 
+// Is makes every not-found domain error match ErrNotFound and every conflict
+// caused by a finalized split match ErrSplitFinalized
+func (e *DomainError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Kind == DomainErrorNotFound
+	case ErrSplitFinalized:
+		return e.Reason == ReasonSplitFinalized
+	}
+	return false
+}
+
 // Helper constructors
 func NewDomainError(kind DomainErrorKind, message string, cause error) *DomainError {
 	return &DomainError{
@@ -47,14 +69,35 @@ func NewValidationError(message string, cause error) *DomainError {
 	return NewDomainError(DomainErrorValidation, message, cause)
 }
 
-func NewNotFoundError(message string, cause error) *DomainError {
-	return NewDomainError(DomainErrorNotFound, message, cause)
+// NewNotFoundError creates a not-found error for the given resource type (e.g. "split", "document", "page") and ID
+func NewNotFoundError(resource, id, message string, cause error) *DomainError {
+	err := NewDomainError(DomainErrorNotFound, message, cause)
+	err.Resource = resource
+	err.ResourceID = id
+	return err
 }
 
 func NewConflictError(message string, cause error) *DomainError {
 	return NewDomainError(DomainErrorConflict, message, cause)
 }
 
+// NewSplitFinalizedError creates a conflict error for an operation refused because the split is finalized
+func NewSplitFinalizedError(message string) *DomainError {
+	err := NewConflictError(message, nil)
+	err.Reason = ReasonSplitFinalized
+	return err
+}
+
+// NewLockedError creates an error for a resource locked by someone else
+func NewLockedError(message string, cause error) *DomainError {
+	return NewDomainError(DomainErrorLocked, message, cause)
+}
+
+// NewUnavailableError creates an error for a request refused because the service is at capacity
+func NewUnavailableError(message string, cause error) *DomainError {
+	return NewDomainError(DomainErrorUnavailable, message, cause)
+}
+
 func NewInternalError(message string, cause error) *DomainError {
 	return NewDomainError(DomainErrorInternal, message, cause)
 }
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FilenamePolicy decides what happens to document filenames with path components or
+// characters outside the safe set, since they end up in download headers
+type FilenamePolicy string
+
+const (
+	// FilenamePolicyStrip drops directory components and unsafe characters
+	FilenamePolicyStrip FilenamePolicy = "strip"
+	// FilenamePolicyReplace drops directory components and replaces unsafe characters with "_"
+	FilenamePolicyReplace FilenamePolicy = "replace"
+	// FilenamePolicyReject refuses unsafe filenames with a validation error
+	FilenamePolicyReject FilenamePolicy = "reject"
+)
+
+// Valid reports whether p is a known policy
+func (p FilenamePolicy) Valid() bool {
+	switch p {
+	case FilenamePolicyStrip, FilenamePolicyReplace, FilenamePolicyReject:
+		return true
+	}
+	return false
+}
+
+// SanitizeFilename applies policy to name. Directory components (everything up to the
+// last / or \) are dropped, characters other than letters, digits, spaces and ._-()+,
+// are stripped or replaced, and leading dots are removed so the result is never "..".
+// Under FilenamePolicyReject a name that would change is a validation error. An empty
+// name stays empty.
+func SanitizeFilename(name string, policy FilenamePolicy) (string, error) {
+	base := name[strings.LastIndexAny(name, `/\`)+1:]
+
+	var b strings.Builder
+	for _, r := range base {
+		switch {
+		case safeFilenameRune(r):
+			b.WriteRune(r)
+		case policy == FilenamePolicyReplace:
+			b.WriteByte('_')
+		}
+	}
+	sanitized := strings.TrimLeft(strings.TrimSpace(b.String()), ".")
+
+	if policy == FilenamePolicyReject && sanitized != name {
+		return "", NewValidationError(fmt.Sprintf("filename %q contains path separators or unsafe characters", name), nil)
+	}
+	return sanitized, nil
+}
+
+func safeFilenameRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return true
+	}
+	return strings.ContainsRune(" ._-()+,", r)
+}
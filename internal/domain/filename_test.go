@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name    string
+		strip   string
+		replace string
+	}{
+		{name: "w2_2023.pdf", strip: "w2_2023.pdf", replace: "w2_2023.pdf"},
+		{name: "Relevé (1).pdf", strip: "Relevé (1).pdf", replace: "Relevé (1).pdf"},
+		{name: "../../etc/passwd", strip: "passwd", replace: "passwd"},
+		{name: `..\..\boot.ini`, strip: "boot.ini", replace: "boot.ini"},
+		{name: "report\n.pdf", strip: "report.pdf", replace: "report_.pdf"},
+		{name: `a"b;c.pdf`, strip: "abc.pdf", replace: "a_b_c.pdf"},
+		{name: "..", strip: "", replace: ""},
+		{name: ".hidden", strip: "hidden", replace: "hidden"},
+		{name: "", strip: "", replace: ""},
+	}
+
+	for _, tt := range tests {
+		got, err := SanitizeFilename(tt.name, FilenamePolicyStrip)
+		require.NoError(t, err)
+		assert.Equal(t, tt.strip, got, "strip %q", tt.name)
+
+		got, err = SanitizeFilename(tt.name, FilenamePolicyReplace)
+		require.NoError(t, err)
+		assert.Equal(t, tt.replace, got, "replace %q", tt.name)
+
+		got, err = SanitizeFilename(tt.name, FilenamePolicyReject)
+		if tt.name == tt.strip {
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, got)
+			continue
+		}
+		var domainErr *DomainError
+		require.ErrorAs(t, err, &domainErr, "reject %q", tt.name)
+		assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+	}
+
+	_, err := SanitizeFilename("../../etc/passwd", FilenamePolicyReject)
+	assert.EqualError(t, err, `validation: filename "../../etc/passwd" contains path separators or unsafe characters`)
+}
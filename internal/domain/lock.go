@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// SplitLock is an advisory lock on a split held by one editor until it expires
+type SplitLock struct {
+	SplitID   string
+	Holder    string
+	ExpiresAt time.Time
+}
+
+// Active reports whether the lock has not expired yet
+func (l *SplitLock) Active(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
+}
+
+// BlocksHolder reports whether the lock keeps the given holder from editing the split
+func (l *SplitLock) BlocksHolder(holder string, now time.Time) bool {
+	return l.Active(now) && l.Holder != holder
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// EventSplitFinalized is the outbox event type emitted when a split is finalized
+const EventSplitFinalized = "split.finalized"
+
+// OutboxMessage is an event recorded alongside a state change, to be delivered later
+type OutboxMessage struct {
+	ID        string
+	EventType string
+	Payload   []byte
+	CreatedAt time.Time
+	Attempts  int
+}
+
+// SplitFinalizedEvent is the webhook payload for EventSplitFinalized
+type SplitFinalizedEvent struct {
+	Event       string    `json:"event"`
+	SplitID     string    `json:"split_id"`
+	ClientID    string    `json:"client_id"`
+	FinalizedAt time.Time `json:"finalized_at"`
+}
@@ -14,6 +14,25 @@ type Page struct {
 	DocumentID *string // ID of the document this page belongs to (nil if unassigned)
 	PageNumber int     // Original page number from the PDF
 	URL        string  // URL to the page content on the filesystem
+	SortOrder  int     // explicit position within the document (0 = fall back to page number)
+}
+
+// PageIDFunc generates the ID of a new page of a split
+type PageIDFunc func(splitID, url string) string
+
+// pageIDNamespace scopes the name-based UUIDs of DeterministicPageID
+var pageIDNamespace = uuid.MustParse("6f1c2a9e-4b0d-5d7e-9a43-2c8e1f0b7d15")
+
+// RandomPageID returns a random page ID; it is the default
+func RandomPageID(splitID, url string) string {
+	return uuid.New().String()
+}
+
+// DeterministicPageID derives the page ID from the split ID and page URL as a
+// UUIDv5, so ingesting the same split again yields the same page IDs while the
+// same URL in another split gets a different ID
+func DeterministicPageID(splitID, url string) string {
+	return uuid.NewSHA1(pageIDNamespace, []byte(splitID+"\x00"+url)).String()
 }
 
 func NewPage(splitID, url string) (*Page, error) {
@@ -37,17 +56,53 @@ func NewPage(splitID, url string) (*Page, error) {
 	return p, nil
 }
 
+// NewPageWithNumber creates a page whose number is known up front rather than
+// parsed from a "page_%d.png" URL, so any URL form is accepted
+func NewPageWithNumber(splitID, url string, pageNumber int) (*Page, error) {
+	if pageNumber <= 0 {
+		return nil, NewValidationError(fmt.Sprintf("page number must be positive, got %d", pageNumber), nil)
+	}
+	p := &Page{
+		ID:         uuid.New().String(),
+		SplitID:    splitID,
+		URL:        url,
+		PageNumber: pageNumber,
+	}
+	if err := p.Valid(); err != nil {
+		return nil, fmt.Errorf("invalid page: %w", err)
+	}
+	return p, nil
+}
+
+// urlPageNumber reports the page number encoded in a "page_%d.png" URL, if any
+func urlPageNumber(url string) (int, bool) {
+	var n int
+	if _, err := fmt.Sscanf(url, "page_%d.png", &n); err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
 func (p *Page) Valid() error {
+	if errs := p.ValidateAll(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll returns every validation issue of the page, in the order Valid checks them
+func (p *Page) ValidateAll() []error {
+	var errs []error
 	if p.ID == "" {
-		return NewValidationError("page id is required", nil)
+		errs = append(errs, NewValidationError("page id is required", nil))
 	}
 	if p.SplitID == "" {
-		return NewValidationError("split id is required", nil)
+		errs = append(errs, NewValidationError("split id is required", nil))
 	}
 	if p.URL == "" {
-		return NewValidationError("url is required", nil)
+		errs = append(errs, NewValidationError("url is required", nil))
 	}
-	return nil
+	return errs
 }
 
 func (p *Page) AssignToDocument(docID string) error {
@@ -60,6 +115,7 @@ func (p *Page) AssignToDocument(docID string) error {
 
 func (p *Page) Unassign() {
 	p.DocumentID = nil
+	p.SortOrder = 0
 }
 
 func (p *Page) IsAssigned() bool {
@@ -68,6 +124,6 @@ func (p *Page) IsAssigned() bool {
 
 // UnassignFromDocument unassigns the page from its document
 func (p *Page) UnassignFromDocument() error {
-	p.DocumentID = nil
+	p.Unassign()
 	return nil
 }
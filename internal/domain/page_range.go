@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PageRange is an inclusive range of page numbers, such as 3-7
+type PageRange struct {
+	Start int
+	End   int
+}
+
+func (r PageRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// ParsePageRange parses "N" or "N-M" into a page range. Page numbers start at 1 and the
+// range must be ascending.
+func ParsePageRange(s string) (PageRange, error) {
+	startStr, endStr, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		endStr = startStr
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return PageRange{}, NewValidationError(fmt.Sprintf("invalid page range %q: expected N or N-M", s), nil)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return PageRange{}, NewValidationError(fmt.Sprintf("invalid page range %q: expected N or N-M", s), nil)
+	}
+	if start < 1 {
+		return PageRange{}, NewValidationError(fmt.Sprintf("invalid page range %q: page numbers start at 1", s), nil)
+	}
+	if end < start {
+		return PageRange{}, NewValidationError(fmt.Sprintf("invalid page range %q: the range must be ascending", s), nil)
+	}
+	return PageRange{Start: start, End: end}, nil
+}
+
+// UnassignedPageIDsInRange returns the IDs of the unassigned pages numbered within r, in
+// the order the split holds them. Every page number of the range must be an unassigned
+// page of the split; the first one that is not is a validation error.
+func (s *Split) UnassignedPageIDsInRange(r PageRange) ([]string, error) {
+	var ids []string
+	numbers := make(map[int]struct{})
+	for _, page := range s.UnassignedPages {
+		if page.PageNumber >= r.Start && page.PageNumber <= r.End {
+			ids = append(ids, page.ID)
+			numbers[page.PageNumber] = struct{}{}
+		}
+	}
+	if len(numbers) == r.End-r.Start+1 {
+		return ids, nil
+	}
+	// Some number is missing; at most len(numbers)+1 are checked to find the first
+	n := r.Start
+	for ; n <= r.End; n++ {
+		if _, ok := numbers[n]; !ok {
+			break
+		}
+	}
+	return nil, NewValidationError(fmt.Sprintf("page %d of range %s is not an unassigned page of split %s", n, r, s.ID), nil)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PageRange
+		wantErr bool
+	}{
+		{input: "3-7", want: PageRange{Start: 3, End: 7}},
+		{input: " 3 - 7 ", want: PageRange{Start: 3, End: 7}},
+		{input: "4", want: PageRange{Start: 4, End: 4}},
+		{input: "7-3", wantErr: true},
+		{input: "0-2", wantErr: true},
+		{input: "-2", wantErr: true},
+		{input: "3-", wantErr: true},
+		{input: "3-5-7", wantErr: true},
+		{input: "a-b", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePageRange(tt.input)
+			if tt.wantErr {
+				var domainErr *DomainError
+				require.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplit_UnassignedPageIDsInRange(t *testing.T) {
+	docID := "doc1"
+	split := &Split{
+		ID: "split1",
+		Documents: []Document{{ID: docID, Pages: []*Page{
+			{ID: "page2", DocumentID: &docID, PageNumber: 2},
+		}}},
+		UnassignedPages: []*Page{
+			{ID: "page4", PageNumber: 4},
+			{ID: "page1", PageNumber: 1},
+			{ID: "page3", PageNumber: 3},
+		},
+	}
+
+	ids, err := split.UnassignedPageIDsInRange(PageRange{Start: 3, End: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page4", "page3"}, ids)
+
+	// Page 2 is assigned and page 5 does not exist
+	_, err = split.UnassignedPageIDsInRange(PageRange{Start: 1, End: 3})
+	assert.EqualError(t, err, "validation: page 2 of range 1-3 is not an unassigned page of split split1")
+	_, err = split.UnassignedPageIDsInRange(PageRange{Start: 3, End: 5})
+	assert.EqualError(t, err, "validation: page 5 of range 3-5 is not an unassigned page of split split1")
+}
@@ -4,6 +4,7 @@ import (
 	"accounting/internal/domain"
 	"context"
 	"io"
+	"time"
 )
 
 // SplitIngestionService handles the ingestion of new splits
@@ -12,10 +13,13 @@ type SplitIngestionService interface {
 	IngestSplit(ctx context.Context, req IngestSplitRequest) (*IngestSplitResponse, error)
 }
 
-// IngestSplitRequest represents a request to ingest a new split
+// IngestSplitRequest represents a request to ingest a new split. File is a
+// multipart/form-data body delimited by Boundary; ClientID, when set, must match
+// the client of the uploaded split.
 type IngestSplitRequest struct {
 	ClientID string
 	File     io.Reader
+	Boundary string
 }
 
 // IngestSplitResponse represents the response from ingesting a split
@@ -27,10 +31,22 @@ type IngestSplitResponse struct {
 type UnitOfWork interface {
 	// SplitRepository returns the split repository
 	SplitRepository() domain.SplitRepository
+	// OutboxRepository returns the outbox repository
+	OutboxRepository() domain.OutboxRepository
+	// AuditRepository returns the audit repository
+	AuditRepository() domain.AuditRepository
+	// LockRepository returns the split lock repository
+	LockRepository() domain.LockRepository
 	// Commit commits the transaction
 	Commit(ctx context.Context) error
 	// Rollback rolls back the transaction
 	Rollback(ctx context.Context) error
+	// Savepoint marks a point in the transaction that RollbackTo can return to
+	Savepoint(ctx context.Context, name string) error
+	// RollbackTo undoes the changes made since the named savepoint and forgets it
+	RollbackTo(ctx context.Context, name string) error
+	// Release forgets the named savepoint, keeping the changes made since it
+	Release(ctx context.Context, name string) error
 }
 
 // RenderService handles document rendering
@@ -50,3 +66,26 @@ type RenderDocumentResponse struct {
 	ContentType string
 	Data        []byte
 }
+
+// BlobStore provides access to the stored content of pages
+type BlobStore interface {
+	// Open returns a reader for the content stored at the given URL
+	Open(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// BlobWriter stores the content of pages
+type BlobWriter interface {
+	// Put stores the content read from r at the given URL, replacing any content there
+	Put(ctx context.Context, url string, r io.Reader) error
+	// Delete removes the content stored at the given URL
+	Delete(ctx context.Context, url string) error
+}
+
+// MetricsRecorder records business and operational metrics from the service layer.
+// Labels may be nil.
+type MetricsRecorder interface {
+	// IncCounter increments the named counter by one
+	IncCounter(name string, labels map[string]string)
+	// ObserveDuration records how long one run of the named operation took
+	ObserveDuration(name string, d time.Duration, labels map[string]string)
+}
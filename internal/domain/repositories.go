@@ -1,17 +1,88 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // SplitRepository handles split aggregate persistence
 type SplitRepository interface {
 	// Get retrieves a split by ID
 	Get(ctx context.Context, id string) (*Split, error)
+	// GetParts retrieves a split with only the selected children loaded. The partial
+	// split is for reading only and must not be saved.
+	GetParts(ctx context.Context, id string, parts SplitParts) (*Split, error)
+	// EachDocument calls fn with each document of a split, pages included, in display
+	// order, reading them one at a time instead of materializing the whole list.
+	// Iteration stops at the first error fn returns.
+	EachDocument(ctx context.Context, splitID string, fn func(*Document) error) error
+	// GetMany retrieves the splits with the given IDs in the order requested, omitting missing IDs
+	GetMany(ctx context.Context, ids []string) ([]*Split, error)
 	// Save persists a split aggregate
 	Save(ctx context.Context, split *Split) error
 	// Delete removes a split
 	Delete(ctx context.Context, id string) error
 	// ListByClientID retrieves all splits for a client
 	ListByClientID(ctx context.Context, clientID string) ([]*Split, error)
+	// ListClientSplits returns a client's splits without their children, newest first;
+	// if tag is not empty only the splits with that tag are returned
+	ListClientSplits(ctx context.Context, clientID, tag string, limit, offset int) ([]*Split, error)
 	// GetSplitIDByDocumentID retrieves the split ID for a given document ID
 	GetSplitIDByDocumentID(ctx context.Context, documentID string) (string, error)
+	// CountPagesByDocument returns the number of pages assigned to a document without loading them
+	CountPagesByDocument(ctx context.Context, documentID string) (int, error)
+	// GetPage retrieves a single page by ID, regardless of its document
+	GetPage(ctx context.Context, pageID string) (*Page, error)
+	// ListSplitPages returns every page of a split, assigned or not, ordered by page number
+	ListSplitPages(ctx context.Context, splitID string) ([]*Page, error)
+	// ListClients returns distinct clients with their split counts, ordered by client ID
+	ListClients(ctx context.Context, limit, offset int) ([]ClientSummary, error)
+	// ListClientPagesByClassification returns the pages of a client's documents with the
+	// given classification across all splits, ordered by split, document and page number
+	ListClientPagesByClassification(ctx context.Context, clientID, classification string, limit, offset int) ([]ClientPage, error)
+	// ReassignPage moves a single page to another document (nil unassigns it) without re-saving
+	// the aggregate; the page falls back to page number order
+	ReassignPage(ctx context.Context, pageID string, newDocID *string) error
+	// TouchSplit sets a split's updated_at and last_modified_by without re-saving the aggregate
+	TouchSplit(ctx context.Context, splitID string, updatedAt time.Time, modifiedBy string) error
+	// UpdateDocumentPageRange persists a document's start and end page and last editor without re-saving the aggregate
+	UpdateDocumentPageRange(ctx context.Context, doc *Document) error
+	// GetClientStats computes aggregate counts over all splits of a client
+	GetClientStats(ctx context.Context, clientID string) (*ClientStats, error)
+	// AddClassificationChange records a document reclassification in the history
+	AddClassificationChange(ctx context.Context, change *ClassificationChange) error
+	// FindOrphans returns the documents and pages of splits that no longer exist and
+	// the pages assigned to documents that no longer exist
+	FindOrphans(ctx context.Context) (*Orphans, error)
+	// RepairOrphans deletes the documents and pages of missing splits and unassigns the
+	// pages of missing documents, which stay in their split
+	RepairOrphans(ctx context.Context, orphans *Orphans) error
+}
+
+// SplitParts selects the children of a split a partial load includes
+type SplitParts struct {
+	Documents       bool
+	UnassignedPages bool
+}
+
+// OutboxRepository records events to be delivered after the transaction commits
+type OutboxRepository interface {
+	// Add enqueues a message as part of the current transaction
+	Add(ctx context.Context, msg *OutboxMessage) error
+}
+
+// AuditRepository records privileged actions
+type AuditRepository interface {
+	// Add records an entry as part of the current transaction
+	Add(ctx context.Context, entry *AuditEntry) error
+}
+
+// LockRepository persists advisory split locks
+type LockRepository interface {
+	// Get retrieves the lock on a split, or nil if there is none (expired locks included)
+	Get(ctx context.Context, splitID string) (*SplitLock, error)
+	// Put creates or replaces the lock on a split
+	Put(ctx context.Context, lock *SplitLock) error
+	// Delete removes the lock on a split, if any
+	Delete(ctx context.Context, splitID string) error
 }
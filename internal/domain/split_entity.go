@@ -4,45 +4,114 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Split is the aggregate root for one AI‐generated split of a PDF bundle.
 type Split struct {
 	ID              string      // unique split identifier
 	ClientID        string      // who owns this split
+	Name            string      // optional human-friendly name
 	Status          SplitStatus // draft | finalized
 	Documents       []Document  // all docs in this split
 	UnassignedPages []*Page     // pages not yet in any document
+	Tags            []string    // normalized labels for filtering, sorted
 
-	CreatedAt   time.Time  // when split was created
-	UpdatedAt   time.Time  // when split was last updated
-	FinalizedAt *time.Time // set when Status == Finalized
+	CreatedAt      time.Time  // when split was created
+	UpdatedAt      time.Time  // when split was last updated
+	LastModifiedBy string     // who last updated the split
+	FinalizedAt    *time.Time // set when Status == Finalized
 }
 
-func NewSplit(jsonRepr string) (*Split, error) {
-	var splitData struct {
-		ID        string      `json:"split_id"`
-		ClientID  string      `json:"client_id"`
-		Status    SplitStatus `json:"status"`
-		Documents []struct {
-			ID               string   `json:"id"`
-			Classification   string   `json:"classification"`
-			Filename         string   `json:"file_name"`
-			Name             string   `json:"name"`
-			ShortDescription string   `json:"short_description"`
-			PageURLs         []string `json:"page_urls"`
-		} `json:"documents"`
+// ingestionSplit is the JSON shape NewSplit consumes and ToIngestionJSON produces
+type ingestionSplit struct {
+	ID        string              `json:"split_id"`
+	ClientID  string              `json:"client_id"`
+	Name      string              `json:"name,omitempty"`
+	Status    SplitStatus         `json:"status"`
+	Documents []ingestionDocument `json:"documents"`
+}
+
+// ingestionDocument lists its pages either as page_urls, numbered by parsing the
+// "page_%d.png" pattern, or as pages with explicit numbers; not both
+type ingestionDocument struct {
+	ID               string          `json:"id"`
+	Classification   string          `json:"classification"`
+	Filename         string          `json:"file_name"`
+	Name             string          `json:"name"`
+	ShortDescription string          `json:"short_description"`
+	PageURLs         []string        `json:"page_urls,omitempty"`
+	Pages            []ingestionPage `json:"pages,omitempty"`
+}
+
+type ingestionPage struct {
+	URL        string `json:"url"`
+	PageNumber int    `json:"page_number"`
+}
+
+// SplitOption customizes NewSplit
+type SplitOption func(*splitOptions)
+
+type splitOptions struct {
+	pageID   PageIDFunc
+	maxPages int
+}
+
+// WithPageIDs makes NewSplit generate page IDs with f instead of RandomPageID,
+// e.g. DeterministicPageID for idempotent re-ingestion. Pages whose IDs collide,
+// such as a URL listed twice with deterministic IDs, are rejected.
+func WithPageIDs(f PageIDFunc) SplitOption {
+	return func(o *splitOptions) {
+		o.pageID = f
+	}
+}
+
+// WithMaxPages makes NewSplit reject splits with more than max pages in total;
+// zero means unlimited
+func WithMaxPages(max int) SplitOption {
+	return func(o *splitOptions) {
+		o.maxPages = max
 	}
+}
+
+func NewSplit(jsonRepr string, opts ...SplitOption) (*Split, error) {
+	o := splitOptions{pageID: RandomPageID}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var splitData ingestionSplit
 
 	if err := json.Unmarshal([]byte(jsonRepr), &splitData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal split JSON: %w", err)
 	}
+	if err := splitData.Status.Valid(); err != nil {
+		return nil, err
+	}
+	if err := validateSplitName(splitData.Name); err != nil {
+		return nil, err
+	}
+
+	if o.maxPages > 0 {
+		total := 0
+		for _, docData := range splitData.Documents {
+			total += len(docData.PageURLs) + len(docData.Pages)
+		}
+		if total > o.maxPages {
+			return nil, NewValidationError(fmt.Sprintf("split %s has %d pages, more than the allowed %d", splitData.ID, total, o.maxPages), nil)
+		}
+	}
 
 	// Create the split
 	split := &Split{
 		ID:              splitData.ID,
 		ClientID:        splitData.ClientID,
+		Name:            splitData.Name,
 		Status:          splitData.Status,
 		Documents:       make([]Document, 0, len(splitData.Documents)),
 		UnassignedPages: make([]*Page, 0),
@@ -51,9 +120,14 @@ func NewSplit(jsonRepr string) (*Split, error) {
 	}
 
 	// Process each document
+	pageIDs := make(map[string]struct{})
 	for _, docData := range splitData.Documents {
+		if len(docData.PageURLs) > 0 && len(docData.Pages) > 0 {
+			return nil, NewValidationError(fmt.Sprintf("document %s lists both page_urls and pages", docData.ID), nil)
+		}
+
 		// Create pages for this document
-		pages := make([]*Page, 0, len(docData.PageURLs))
+		pages := make([]*Page, 0, len(docData.PageURLs)+len(docData.Pages))
 		for _, url := range docData.PageURLs {
 			page, err := NewPage(splitData.ID, url)
 			if err != nil {
@@ -61,6 +135,20 @@ func NewSplit(jsonRepr string) (*Split, error) {
 			}
 			pages = append(pages, page)
 		}
+		for _, p := range docData.Pages {
+			page, err := NewPageWithNumber(splitData.ID, p.URL, p.PageNumber)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create page from URL %s: %w", p.URL, err)
+			}
+			pages = append(pages, page)
+		}
+		for _, page := range pages {
+			page.ID = o.pageID(splitData.ID, page.URL)
+			if _, ok := pageIDs[page.ID]; ok {
+				return nil, NewValidationError(fmt.Sprintf("page %s appears more than once in split %s", page.URL, splitData.ID), nil)
+			}
+			pageIDs[page.ID] = struct{}{}
+		}
 
 		// Create the document
 		doc, err := NewDocument(
@@ -87,6 +175,47 @@ func NewSplit(jsonRepr string) (*Split, error) {
 	return split, nil
 }
 
+// ToIngestionJSON serializes the split in the shape NewSplit consumes, so a split
+// can be exported, edited and re-ingested. Page IDs, timestamps and unassigned
+// pages are not part of the format. Pages are listed in page order as page_urls,
+// or as pages with explicit numbers when a URL does not encode its page number.
+func (s *Split) ToIngestionJSON() string {
+	data := ingestionSplit{
+		ID:        s.ID,
+		ClientID:  s.ClientID,
+		Name:      s.Name,
+		Status:    s.Status,
+		Documents: make([]ingestionDocument, 0, len(s.Documents)),
+	}
+	for _, doc := range s.Documents {
+		d := ingestionDocument{
+			ID:               doc.ID,
+			Classification:   doc.Classification,
+			Filename:         doc.Filename,
+			Name:             doc.Name,
+			ShortDescription: doc.ShortDescription,
+		}
+		explicit := false
+		for _, page := range doc.Pages {
+			if n, ok := urlPageNumber(page.URL); !ok || n != page.PageNumber {
+				explicit = true
+				break
+			}
+		}
+		for _, page := range doc.Pages {
+			if explicit {
+				d.Pages = append(d.Pages, ingestionPage{URL: page.URL, PageNumber: page.PageNumber})
+			} else {
+				d.PageURLs = append(d.PageURLs, page.URL)
+			}
+		}
+		data.Documents = append(data.Documents, d)
+	}
+	// Marshalling plain strings and slices cannot fail
+	out, _ := json.Marshal(data)
+	return string(out)
+}
+
 func (s *Split) String() string {
 	return fmt.Sprintf("Split %v (%s): %d documents, %d unassigned pages, status: %s",
 		s.ID, s.ClientID, len(s.Documents), len(s.UnassignedPages), s.Status)
@@ -107,10 +236,164 @@ func (s *Split) Valid() error {
 	return nil
 }
 
-// Finalize marks the split as finalized
+// ValidateAll returns every validation issue across the split, its documents and
+// their pages, and its unassigned pages. Unlike Valid it does not stop at the first one.
+func (s *Split) ValidateAll() []error {
+	var errs []error
+	if s.ID == "" {
+		errs = append(errs, NewValidationError("split ID is required", nil))
+	}
+	if s.ClientID == "" {
+		errs = append(errs, NewValidationError("client ID is required", nil))
+	}
+	for _, doc := range s.Documents {
+		for _, err := range doc.ValidateAll() {
+			errs = append(errs, fmt.Errorf("invalid document %v in split %v: %w", doc.ID, s.ID, err))
+		}
+	}
+	for _, page := range s.UnassignedPages {
+		for _, err := range page.ValidateAll() {
+			errs = append(errs, fmt.Errorf("invalid unassigned page %v in split %v: %w", page.ID, s.ID, err))
+		}
+	}
+	return errs
+}
+
+// FinalizeIssues returns every reason Finalize would refuse the split, or none if it can be finalized.
+// An already finalized split has no issues, since finalizing it again is a no-op.
+func (s *Split) FinalizeIssues() []error {
+	if s.Status == SplitStatusFinalized {
+		return nil
+	}
+	var errs []error
+	if s.Status != SplitStatusDraft {
+		errs = append(errs, NewConflictError(fmt.Sprintf("cannot finalize split in status %s", s.Status), nil))
+	}
+	if len(s.UnassignedPages) > 0 {
+		errs = append(errs, NewValidationError("cannot finalize split with unassigned pages", nil))
+	}
+	return append(errs, s.ValidateAll()...)
+}
+
+// FinalizeBlockers groups every reason a draft split cannot be finalized, so that a
+// client can point at all of them at once. It is an error so it can be the cause of
+// the validation error refusing the finalize.
+type FinalizeBlockers struct {
+	// UnassignedPageIDs are the pages still to be assigned to a document
+	UnassignedPageIDs []string
+	// InvalidDocuments are the documents failing validation, in split order
+	InvalidDocuments []DocumentIssues
+	// Issues are the problems not tied to one document, such as a missing client ID
+	Issues []error
+}
+
+// DocumentIssues are the validation issues of one document
+type DocumentIssues struct {
+	DocumentID string
+	Issues     []error
+}
+
+// Empty reports whether nothing blocks the finalize
+func (b *FinalizeBlockers) Empty() bool {
+	return len(b.UnassignedPageIDs) == 0 && len(b.InvalidDocuments) == 0 && len(b.Issues) == 0
+}
+
+func (b *FinalizeBlockers) Error() string {
+	var parts []string
+	if len(b.UnassignedPageIDs) > 0 {
+		parts = append(parts, fmt.Sprintf("unassigned pages: %s", strings.Join(b.UnassignedPageIDs, ", ")))
+	}
+	for _, doc := range b.InvalidDocuments {
+		for _, err := range doc.Issues {
+			parts = append(parts, fmt.Sprintf("invalid document %s: %v", doc.DocumentID, err))
+		}
+	}
+	for _, err := range b.Issues {
+		parts = append(parts, err.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FinalizeBlockers returns every reason the draft split cannot be finalized, grouped
+// by unassigned page and document, from the same checks as FinalizeIssues
+func (s *Split) FinalizeBlockers() *FinalizeBlockers {
+	b := &FinalizeBlockers{}
+	if s.ID == "" {
+		b.Issues = append(b.Issues, NewValidationError("split ID is required", nil))
+	}
+	if s.ClientID == "" {
+		b.Issues = append(b.Issues, NewValidationError("client ID is required", nil))
+	}
+	for _, doc := range s.Documents {
+		if errs := doc.ValidateAll(); len(errs) > 0 {
+			b.InvalidDocuments = append(b.InvalidDocuments, DocumentIssues{DocumentID: doc.ID, Issues: errs})
+		}
+	}
+	for _, page := range s.UnassignedPages {
+		b.UnassignedPageIDs = append(b.UnassignedPageIDs, page.ID)
+		for _, err := range page.ValidateAll() {
+			b.Issues = append(b.Issues, fmt.Errorf("invalid unassigned page %v: %w", page.ID, err))
+		}
+	}
+	return b
+}
+
+// ExceedsUnassignedPageLimit reports whether the split holds more unassigned pages than limit.
+// This is advisory only; a non-positive limit disables the check.
+func (s *Split) ExceedsUnassignedPageLimit(limit int) bool {
+	return limit > 0 && len(s.UnassignedPages) > limit
+}
+
+// Touch records that by changed the split at the given time, together with the
+// documents with docIDs
+func (s *Split) Touch(at time.Time, by string, docIDs ...string) {
+	s.UpdatedAt = at
+	s.LastModifiedBy = by
+	for i := range s.Documents {
+		if slices.Contains(docIDs, s.Documents[i].ID) {
+			s.Documents[i].LastModifiedBy = by
+		}
+	}
+}
+
+// CompletionPercent returns the share of the split's pages assigned to a document,
+// from 0 to 100. A split without pages has nothing left to assign and is 100.
+func (s *Split) CompletionPercent() float64 {
+	assigned := 0
+	for _, doc := range s.Documents {
+		assigned += len(doc.Pages)
+	}
+	return CompletionPercent(assigned, len(s.UnassignedPages))
+}
+
+// CompletionPercent returns the share of assigned pages among assigned and unassigned
+// pages, from 0 to 100, and 100 when there are no pages
+func CompletionPercent(assigned, unassigned int) float64 {
+	total := assigned + unassigned
+	if total == 0 {
+		return 100
+	}
+	return float64(assigned) * 100 / float64(total)
+}
+
+// EnsureDeletable returns a conflict error if the split may not be deleted.
+// Finalized splits can only be removed by an admin force-delete.
+func (s *Split) EnsureDeletable() error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot delete finalized split")
+	}
+	return nil
+}
+
+// Finalize marks the split as finalized. Finalizing an already finalized split is a
+// no-op that keeps the original FinalizedAt, so retried requests succeed; any status
+// other than draft is a conflict.
 func (s *Split) Finalize(finalizedAt time.Time) error {
 	if s.Status == SplitStatusFinalized {
-		return NewConflictError("split already finalized", nil)
+		return nil
+	}
+	if s.Status != SplitStatusDraft {
+		return NewConflictError(fmt.Sprintf("cannot finalize split in status %s", s.Status), nil)
 	}
 
 	if len(s.UnassignedPages) > 0 {
@@ -126,10 +409,95 @@ func (s *Split) Finalize(finalizedAt time.Time) error {
 	return nil
 }
 
+// MaxSplitNameLength is the longest split name allowed, in characters
+const MaxSplitNameLength = 200
+
+// validateSplitName rejects names longer than MaxSplitNameLength; empty names are allowed
+func validateSplitName(name string) error {
+	if n := utf8.RuneCountInString(name); n > MaxSplitNameLength {
+		return NewValidationError(fmt.Sprintf("split name is %d characters long, more than the allowed %d", n, MaxSplitNameLength), nil)
+	}
+	return nil
+}
+
+// Rename sets the split's display name; an empty name clears it
+func (s *Split) Rename(name string) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot rename finalized split")
+	}
+	if err := validateSplitName(name); err != nil {
+		return err
+	}
+	s.Name = name
+	return nil
+}
+
+// MaxSplitTags is the most tags a split may have
+const MaxSplitTags = 20
+
+// MaxTagLength is the longest tag allowed, in characters
+const MaxTagLength = 50
+
+// NormalizeTag trims and lowercases a tag, so "Urgent " and "urgent" are the same
+// tag. Empty tags, tags longer than MaxTagLength and tags with control characters are rejected.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", NewValidationError("tag must not be empty", nil)
+	}
+	if n := utf8.RuneCountInString(tag); n > MaxTagLength {
+		return "", NewValidationError(fmt.Sprintf("tag %q is %d characters long, more than the allowed %d", tag, n, MaxTagLength), nil)
+	}
+	if strings.ContainsFunc(tag, unicode.IsControl) {
+		return "", NewValidationError(fmt.Sprintf("tag %q contains control characters", tag), nil)
+	}
+	return tag, nil
+}
+
+// AddTags normalizes tags and adds those the split does not have yet, keeping
+// Tags sorted. Tags are labels rather than content, so finalized splits can be
+// tagged too. Nothing is added if a tag is invalid or the split would end up with
+// more than MaxSplitTags tags.
+func (s *Split) AddTags(tags []string) error {
+	merged := slices.Clone(s.Tags)
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	if len(merged) > MaxSplitTags {
+		return NewValidationError(fmt.Sprintf("split would have %d tags, more than the allowed %d", len(merged), MaxSplitTags), nil)
+	}
+	slices.Sort(merged)
+	s.Tags = merged
+	return nil
+}
+
+// RemoveTags normalizes tags and removes them from the split; tags the split
+// does not have are ignored
+func (s *Split) RemoveTags(tags []string) error {
+	remove := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+		remove = append(remove, tag)
+	}
+	s.Tags = slices.DeleteFunc(s.Tags, func(tag string) bool {
+		return slices.Contains(remove, tag)
+	})
+	return nil
+}
+
 // AddDocument adds a new document to the split
 func (s *Split) AddDocument(doc *Document) error {
 	if s.Status == SplitStatusFinalized {
-		return NewConflictError("cannot add document to finalized split", nil)
+		return NewSplitFinalizedError("cannot add document to finalized split")
 	}
 	if err := doc.Valid(); err != nil {
 		return NewValidationError("invalid document", err)
@@ -143,15 +511,144 @@ func (s *Split) AddDocument(doc *Document) error {
 		if page.IsAssigned() {
 			return NewConflictError("cannot add document with already assigned pages", nil)
 		}
+		if page.SplitID != s.ID {
+			return NewConflictError(fmt.Sprintf("cannot add document with page %s of split %s to split %s", page.ID, page.SplitID, s.ID), nil)
+		}
+	}
+	s.appendDocument(doc)
+	return nil
+}
+
+// appendDocument adds a document after the existing ones; once the split has a
+// custom order, new documents go to the end of it
+func (s *Split) appendDocument(doc *Document) {
+	maxOrder := 0
+	for _, existingDoc := range s.Documents {
+		maxOrder = max(maxOrder, existingDoc.SortOrder)
+	}
+	if maxOrder > 0 {
+		doc.SortOrder = maxOrder + 1
 	}
 	s.Documents = append(s.Documents, *doc)
+}
+
+// DetachDocument removes a document together with its pages from the split, to be
+// attached to another split with AttachDocument
+func (s *Split) DetachDocument(docID string) (*Document, error) {
+	if s.Status == SplitStatusFinalized {
+		return nil, NewSplitFinalizedError("cannot move document out of finalized split")
+	}
+	for i, doc := range s.Documents {
+		if doc.ID == docID {
+			s.Documents = append(s.Documents[:i], s.Documents[i+1:]...)
+			return &doc, nil
+		}
+	}
+	return nil, NewNotFoundError("document", docID, "document not found in split", nil)
+}
+
+// AttachDocument adds a document detached from another split, making the split
+// the owner of the document and its pages. When any of its page numbers is already
+// taken in the split, the pages are renumbered, in order, after the split's last page.
+func (s *Split) AttachDocument(doc *Document) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot move document into finalized split")
+	}
+	for _, existingDoc := range s.Documents {
+		if existingDoc.ID == doc.ID {
+			return NewConflictError("document with ID already exists", nil)
+		}
+	}
+	doc.SplitID = s.ID
+	for _, page := range doc.Pages {
+		page.SplitID = s.ID
+	}
+	s.renumberAttachedPages(doc.Pages)
+	// The position in the source split means nothing here
+	doc.SortOrder = 0
+	s.appendDocument(doc)
+	return nil
+}
+
+// renumberAttachedPages numbers pages coming from another split after the split's
+// last page when any of their numbers is already taken, so page numbers stay unique
+func (s *Split) renumberAttachedPages(pages []*Page) {
+	taken := make(map[int]struct{})
+	last := 0
+	for _, doc := range s.Documents {
+		for _, page := range doc.Pages {
+			taken[page.PageNumber] = struct{}{}
+			last = max(last, page.PageNumber)
+		}
+	}
+	for _, page := range s.UnassignedPages {
+		taken[page.PageNumber] = struct{}{}
+		last = max(last, page.PageNumber)
+	}
+
+	overlap := false
+	for _, page := range pages {
+		if _, ok := taken[page.PageNumber]; ok {
+			overlap = true
+			break
+		}
+	}
+	if !overlap {
+		return
+	}
+	for _, page := range pages {
+		last++
+		page.PageNumber = last
+	}
+}
+
+// ReorderDocuments arranges the split's documents in the given order.
+// orderedDocIDs must contain every document ID of the split exactly once.
+func (s *Split) ReorderDocuments(orderedDocIDs []string) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot reorder documents in finalized split")
+	}
+	if len(orderedDocIDs) != len(s.Documents) {
+		return NewValidationError("document order must list every document in the split exactly once", nil)
+	}
+
+	byID := make(map[string]Document, len(s.Documents))
+	for _, doc := range s.Documents {
+		byID[doc.ID] = doc
+	}
+
+	reordered := make([]Document, 0, len(orderedDocIDs))
+	for i, id := range orderedDocIDs {
+		doc, ok := byID[id]
+		if !ok {
+			return NewValidationError(fmt.Sprintf("document %v is not part of the split or is listed twice", id), nil)
+		}
+		delete(byID, id)
+		doc.SortOrder = i + 1
+		reordered = append(reordered, doc)
+	}
+
+	s.Documents = reordered
 	return nil
 }
 
+// SwapPages swaps the positions of two pages within a document of the split
+func (s *Split) SwapPages(docID, pageAID, pageBID string) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot swap pages in finalized split")
+	}
+	for i := range s.Documents {
+		if s.Documents[i].ID == docID {
+			return s.Documents[i].SwapPages(pageAID, pageBID)
+		}
+	}
+	return NewNotFoundError("document", docID, "document not found", nil)
+}
+
 // RemoveDocument removes a document from the split
 func (s *Split) RemoveDocument(docID string) error {
 	if s.Status == SplitStatusFinalized {
-		return NewConflictError("cannot remove document from finalized split", nil)
+		return NewSplitFinalizedError("cannot remove document from finalized split")
 	}
 	for i, doc := range s.Documents {
 		if doc.ID == docID {
@@ -175,13 +672,13 @@ func (s *Split) RemoveDocument(docID string) error {
 			return nil
 		}
 	}
-	return NewNotFoundError("document not found in split", nil)
+	return NewNotFoundError("document", docID, "document not found in split", nil)
 }
 
 // MovePages moves pages between documents
 func (s *Split) MovePages(fromDocID, toDocID string, pageIDs []string) error {
 	if s.Status == SplitStatusFinalized {
-		return NewConflictError("cannot move pages in finalized split", nil)
+		return NewSplitFinalizedError("cannot move pages in finalized split")
 	}
 
 	var fromDoc, toDoc *Document
@@ -194,10 +691,10 @@ func (s *Split) MovePages(fromDocID, toDocID string, pageIDs []string) error {
 		}
 	}
 	if fromDoc == nil {
-		return NewNotFoundError("source document not found", nil)
+		return NewNotFoundError("document", fromDocID, "source document not found", nil)
 	}
 	if toDoc == nil {
-		return NewNotFoundError("target document not found", nil)
+		return NewNotFoundError("document", toDocID, "target document not found", nil)
 	}
 
 	// Check if any page to be moved is already present in the target document
@@ -219,10 +716,53 @@ func (s *Split) MovePages(fromDocID, toDocID string, pageIDs []string) error {
 	return nil
 }
 
+// DeletePages drops pages from a document and from the split entirely, e.g. blank
+// separator pages. Unlike RemoveDocument the pages do not become unassigned.
+// Every page must belong to the document and at least one page must remain.
+func (s *Split) DeletePages(docID string, pageIDs []string) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot delete pages in finalized split")
+	}
+	if len(pageIDs) == 0 {
+		return NewValidationError("at least one page ID is required", nil)
+	}
+
+	var doc *Document
+	for i := range s.Documents {
+		if s.Documents[i].ID == docID {
+			doc = &s.Documents[i]
+			break
+		}
+	}
+	if doc == nil {
+		return NewNotFoundError("document", docID, "document not found in split", nil)
+	}
+
+	inDoc := make(map[string]struct{}, len(doc.Pages))
+	for _, p := range doc.Pages {
+		inDoc[p.ID] = struct{}{}
+	}
+	toDelete := make(map[string]struct{}, len(pageIDs))
+	for _, pid := range pageIDs {
+		if _, ok := inDoc[pid]; !ok {
+			return NewNotFoundError("page", pid, "page not found in document", nil)
+		}
+		toDelete[pid] = struct{}{}
+	}
+	if len(toDelete) == len(doc.Pages) {
+		return NewValidationError("cannot delete every page of a document; delete the document instead", nil)
+	}
+
+	if _, err := doc.RemovePages(pageIDs); err != nil {
+		return err
+	}
+	return nil
+}
+
 // UpdateDocumentMetadata updates document metadata
 func (s *Split) UpdateDocumentMetadata(docID string, meta DocumentMetadata) error {
 	if s.Status == SplitStatusFinalized {
-		return fmt.Errorf("cannot update document in finalized split %v", s.ID)
+		return NewSplitFinalizedError("cannot update document in finalized split")
 	}
 
 	// Find document
@@ -232,22 +772,75 @@ func (s *Split) UpdateDocumentMetadata(docID string, meta DocumentMetadata) erro
 		}
 	}
 
-	return fmt.Errorf("document %v not found in split %v", docID, s.ID)
+	return NewNotFoundError("document", docID, "document not found", nil)
 }
 
-func (s *Split) findDoc(fromDocID string) (*Document, error) {
-	// Find source document
-	var fromDoc *Document
+// GenerateDocumentName fills template for a new document of the given classification.
+// {classification} is replaced by the classification and {n} by the next free index
+// for that classification within the split, skipping indexes whose name is taken.
+func (s *Split) GenerateDocumentName(template, classification string) string {
+	taken := make(map[string]struct{}, len(s.Documents))
+	n := 1
+	for _, doc := range s.Documents {
+		taken[doc.Name] = struct{}{}
+		if doc.Classification == classification {
+			n++
+		}
+	}
+	for {
+		name := strings.NewReplacer("{classification}", classification, "{n}", strconv.Itoa(n)).Replace(template)
+		if _, ok := taken[name]; !ok {
+			return name
+		}
+		n++
+	}
+}
+
+// ReclassifyDocument sets a document's classification and returns the previous one
+func (s *Split) ReclassifyDocument(docID, classification string) (string, error) {
+	if s.Status == SplitStatusFinalized {
+		return "", NewSplitFinalizedError("cannot reclassify document in finalized split")
+	}
+	if classification == "" {
+		return "", NewValidationError("document classification is required", nil)
+	}
+
 	for i := range s.Documents {
-		if s.Documents[i].ID == fromDocID {
-			fromDoc = &s.Documents[i]
-			break
+		if s.Documents[i].ID == docID {
+			old := s.Documents[i].Classification
+			s.Documents[i].Classification = classification
+			return old, nil
 		}
 	}
-	if fromDoc == nil {
-		return nil, fmt.Errorf("document %v not found in split %v", fromDocID, s.ID)
+
+	return "", NewNotFoundError("document", docID, "document not found", nil)
+}
+
+// ReviewDocument marks a document reviewed by reviewer, or clears its review
+func (s *Split) ReviewDocument(docID string, reviewed bool, reviewer string, at time.Time) error {
+	if s.Status == SplitStatusFinalized {
+		return NewSplitFinalizedError("cannot review document in finalized split")
+	}
+
+	for i := range s.Documents {
+		if s.Documents[i].ID == docID {
+			s.Documents[i].SetReviewed(reviewed, reviewer, at)
+			return nil
+		}
+	}
+
+	return NewNotFoundError("document", docID, "document not found", nil)
+}
+
+// UnreviewedDocuments returns the IDs of the documents not marked reviewed, in order
+func (s *Split) UnreviewedDocuments() []string {
+	var ids []string
+	for _, doc := range s.Documents {
+		if !doc.Reviewed {
+			ids = append(ids, doc.ID)
+		}
 	}
-	return fromDoc, nil
+	return ids
 }
 
 func Map[T any, R any](slice []T, f func(T) R) []R {
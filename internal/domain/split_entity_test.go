@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -290,6 +291,23 @@ func TestSplit_AddDocument(t *testing.T) {
 	}
 }
 
+func TestSplit_AddDocumentRejectsForeignPages(t *testing.T) {
+	split := &Split{ID: "split123", Status: SplitStatusDraft}
+	own, err := NewPage("split123", "page_1.png")
+	require.NoError(t, err)
+	foreign, err := NewPage("other-split", "page_2.png")
+	require.NoError(t, err)
+	doc, err := NewDocument("doc1", "split123", "Test Document", "W-2", "test.pdf", "", []*Page{own, foreign})
+	require.NoError(t, err)
+
+	err = split.AddDocument(doc)
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+	assert.Contains(t, err.Error(), "page "+foreign.ID+" of split other-split")
+	assert.Empty(t, split.Documents)
+}
+
 func TestSplit_RemoveDocument(t *testing.T) {
 	// Helper function to create a test split
 	createTestSplit := func(status SplitStatus) *Split {
@@ -734,14 +752,29 @@ func TestSplit_Finalize(t *testing.T) {
 			},
 		},
 		{
-			name: "cannot finalize already finalized split",
+			name: "finalizing already finalized split is a no-op",
 			setup: func() *Split {
 				split := createTestSplit(SplitStatusFinalized)
-				split.FinalizedAt = &time.Time{}
+				finalizedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				split.FinalizedAt = &finalizedAt
+				return split
+			},
+			wantErr: false,
+			check: func(t *testing.T, split *Split) {
+				assert.Equal(t, SplitStatusFinalized, split.Status)
+				assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), *split.FinalizedAt)
+			},
+		},
+		{
+			name: "cannot finalize split in another status",
+			setup: func() *Split {
+				split := createTestSplit(SplitStatus("archived"))
+				pages := createTestPages(1)
+				split.Documents = append(split.Documents, *createTestDocument("doc1", pages))
 				return split
 			},
 			wantErr:     true,
-			errContains: "split already finalized",
+			errContains: "cannot finalize split in status archived",
 		},
 		{
 			name: "cannot finalize split with unassigned pages",
@@ -794,3 +827,788 @@ func TestSplit_Finalize(t *testing.T) {
 		})
 	}
 }
+
+func TestSplit_ReorderDocuments(t *testing.T) {
+	// Helper function to create a split with three documents
+	createTestSplit := func(status SplitStatus) *Split {
+		split := &Split{
+			ID:              "split123",
+			ClientID:        "client456",
+			Status:          SplitStatusDraft,
+			Documents:       make([]Document, 0),
+			UnassignedPages: make([]*Page, 0),
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		for i, id := range []string{"doc1", "doc2", "doc3"} {
+			page, err := NewPage("split123", fmt.Sprintf("page_%d.png", i+1))
+			require.NoError(t, err)
+			doc, err := NewDocument(id, "split123", "Test Document", "W-2", "test.pdf", "Test Description", []*Page{page})
+			require.NoError(t, err)
+			require.NoError(t, split.AddDocument(doc))
+		}
+		split.Status = status
+		return split
+	}
+
+	tests := []struct {
+		name        string
+		status      SplitStatus
+		order       []string
+		wantErr     bool
+		errContains string
+		wantOrder   []string
+	}{
+		{
+			name:      "reorder documents",
+			status:    SplitStatusDraft,
+			order:     []string{"doc3", "doc1", "doc2"},
+			wantOrder: []string{"doc3", "doc1", "doc2"},
+		},
+		{
+			name:        "missing document",
+			status:      SplitStatusDraft,
+			order:       []string{"doc3", "doc1"},
+			wantErr:     true,
+			errContains: "every document",
+		},
+		{
+			name:        "duplicate document",
+			status:      SplitStatusDraft,
+			order:       []string{"doc3", "doc1", "doc1"},
+			wantErr:     true,
+			errContains: "listed twice",
+		},
+		{
+			name:        "unknown document",
+			status:      SplitStatusDraft,
+			order:       []string{"doc3", "doc1", "doc4"},
+			wantErr:     true,
+			errContains: "doc4",
+		},
+		{
+			name:        "finalized split",
+			status:      SplitStatusFinalized,
+			order:       []string{"doc3", "doc1", "doc2"},
+			wantErr:     true,
+			errContains: "finalized split",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := createTestSplit(tt.status)
+			err := split.ReorderDocuments(tt.order)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Equal(t, "doc1", split.Documents[0].ID)
+				return
+			}
+
+			require.NoError(t, err)
+			for i, id := range tt.wantOrder {
+				assert.Equal(t, id, split.Documents[i].ID)
+				assert.Equal(t, i+1, split.Documents[i].SortOrder)
+			}
+		})
+	}
+
+	t.Run("new documents are appended after a custom order", func(t *testing.T) {
+		split := createTestSplit(SplitStatusDraft)
+		require.NoError(t, split.ReorderDocuments([]string{"doc3", "doc1", "doc2"}))
+
+		page, err := NewPage("split123", "page_4.png")
+		require.NoError(t, err)
+		doc, err := NewDocument("doc4", "split123", "Test Document", "W-2", "test.pdf", "Test Description", []*Page{page})
+		require.NoError(t, err)
+		require.NoError(t, split.AddDocument(doc))
+
+		assert.Equal(t, 4, split.Documents[3].SortOrder)
+	})
+}
+
+func TestSplit_ExceedsUnassignedPageLimit(t *testing.T) {
+	split := &Split{ID: "split123", ClientID: "client456", Status: SplitStatusDraft}
+	for i := 0; i < 3; i++ {
+		page, err := NewPage("split123", fmt.Sprintf("page_%d.png", i+1))
+		require.NoError(t, err)
+		split.UnassignedPages = append(split.UnassignedPages, page)
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  bool
+	}{
+		{name: "disabled", limit: 0, want: false},
+		{name: "below count", limit: 2, want: true},
+		{name: "at count", limit: 3, want: false},
+		{name: "above count", limit: 4, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, split.ExceedsUnassignedPageLimit(tt.limit))
+		})
+	}
+}
+
+func TestNewSplit_DeterministicPageIDs(t *testing.T) {
+	ingestion := func(splitID string) string {
+		return fmt.Sprintf(`{
+			"split_id": %q,
+			"client_id": "client456",
+			"status": "draft",
+			"documents": [
+				{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png", "page_2.png"]},
+				{"id": "doc2", "classification": "W-2", "file_name": "b.pdf", "name": "B", "pages": [{"url": "s3://bucket/b.png", "page_number": 3}]}
+			]
+		}`, splitID)
+	}
+	pageIDs := func(split *Split) []string {
+		var ids []string
+		for _, doc := range split.Documents {
+			for _, page := range doc.Pages {
+				ids = append(ids, page.ID)
+			}
+		}
+		return ids
+	}
+
+	first, err := NewSplit(ingestion("split1"), WithPageIDs(DeterministicPageID))
+	require.NoError(t, err)
+	again, err := NewSplit(ingestion("split1"), WithPageIDs(DeterministicPageID))
+	require.NoError(t, err)
+	other, err := NewSplit(ingestion("split2"), WithPageIDs(DeterministicPageID))
+	require.NoError(t, err)
+
+	// Identical input yields identical IDs; the same URLs in another split do not
+	assert.Equal(t, pageIDs(first), pageIDs(again))
+	assert.Len(t, pageIDs(first), 3)
+	for _, id := range pageIDs(other) {
+		assert.NotContains(t, pageIDs(first), id)
+	}
+	assert.NotEqual(t, pageIDs(first)[0], pageIDs(first)[1])
+
+	// Random IDs remain the default
+	random, err := NewSplit(ingestion("split1"))
+	require.NoError(t, err)
+	for _, id := range pageIDs(random) {
+		assert.NotContains(t, pageIDs(first), id)
+	}
+
+	// A URL listed twice would give two pages the same ID
+	_, err = NewSplit(`{
+		"split_id": "split1",
+		"client_id": "client456",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png"]},
+			{"id": "doc2", "classification": "W-2", "file_name": "b.pdf", "name": "B", "page_urls": ["page_1.png"]}
+		]
+	}`, WithPageIDs(DeterministicPageID))
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+}
+
+func TestNewSplit_Status(t *testing.T) {
+	ingestion := func(status string) string {
+		return fmt.Sprintf(`{
+			"split_id": "split1",
+			"client_id": "client456",
+			"status": %q,
+			"documents": [
+				{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png"]}
+			]
+		}`, status)
+	}
+
+	for _, status := range []SplitStatus{SplitStatusDraft, SplitStatusFinalized} {
+		split, err := NewSplit(ingestion(string(status)))
+		require.NoError(t, err)
+		assert.Equal(t, status, split.Status)
+	}
+
+	for _, status := range []string{"frozen", "Draft", ""} {
+		_, err := NewSplit(ingestion(status))
+		var domainErr *DomainError
+		require.ErrorAs(t, err, &domainErr, status)
+		assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+	}
+	_, err := NewSplit(ingestion("frozen"))
+	assert.EqualError(t, err, `validation: unknown split status "frozen": must be draft or finalized`)
+}
+
+func TestNewSplit_Name(t *testing.T) {
+	ingestion := func(name string) string {
+		return fmt.Sprintf(`{
+			"split_id": "split1",
+			"client_id": "client456",
+			"name": %q,
+			"status": "draft",
+			"documents": [
+				{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png"]}
+			]
+		}`, name)
+	}
+
+	split, err := NewSplit(ingestion("2023 returns"))
+	require.NoError(t, err)
+	assert.Equal(t, "2023 returns", split.Name)
+
+	roundTripped, err := NewSplit(split.ToIngestionJSON())
+	require.NoError(t, err)
+	assert.Equal(t, "2023 returns", roundTripped.Name)
+
+	// The name is optional
+	split, err = NewSplit(ingestion(""))
+	require.NoError(t, err)
+	assert.Empty(t, split.Name)
+
+	_, err = NewSplit(ingestion(strings.Repeat("a", MaxSplitNameLength+1)))
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+}
+
+func TestSplit_Rename(t *testing.T) {
+	split := &Split{ID: "split1", Status: SplitStatusDraft}
+
+	require.NoError(t, split.Rename("Smith household"))
+	assert.Equal(t, "Smith household", split.Name)
+
+	// The limit counts characters, not bytes
+	require.NoError(t, split.Rename(strings.Repeat("é", MaxSplitNameLength)))
+
+	err := split.Rename(strings.Repeat("a", MaxSplitNameLength+1))
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+
+	split.Status = SplitStatusFinalized
+	err = split.Rename("other")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+}
+
+func TestSplit_Tags(t *testing.T) {
+	split := &Split{ID: "split1", Status: SplitStatusFinalized}
+
+	// Tags are normalized, deduplicated and sorted; finalized splits can be tagged
+	require.NoError(t, split.AddTags([]string{" Urgent", "tax-2024", "URGENT "}))
+	assert.Equal(t, []string{"tax-2024", "urgent"}, split.Tags)
+	require.NoError(t, split.AddTags([]string{"urgent", "Review"}))
+	assert.Equal(t, []string{"review", "tax-2024", "urgent"}, split.Tags)
+
+	// Removing ignores tags the split does not have
+	require.NoError(t, split.RemoveTags([]string{"URGENT", "missing"}))
+	assert.Equal(t, []string{"review", "tax-2024"}, split.Tags)
+
+	// Invalid tags reject the whole change
+	for _, tags := range [][]string{{"ok", " "}, {strings.Repeat("a", MaxTagLength+1)}, {"tab\tbed"}} {
+		err := split.AddTags(tags)
+		var domainErr *DomainError
+		require.ErrorAs(t, err, &domainErr, "%q", tags)
+		assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+		assert.Equal(t, []string{"review", "tax-2024"}, split.Tags)
+	}
+
+	// The number of tags is capped
+	many := make([]string, MaxSplitTags-1)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag-%02d", i)
+	}
+	err := split.AddTags(many)
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+	assert.Len(t, split.Tags, 2)
+	require.NoError(t, split.AddTags(many[:MaxSplitTags-2]))
+	assert.Len(t, split.Tags, MaxSplitTags)
+}
+
+func TestSplit_FinalizedMutationsReturnErrSplitFinalized(t *testing.T) {
+	docID := "doc1"
+	newSplit := func() *Split {
+		return &Split{
+			ID:     "split1",
+			Status: SplitStatusFinalized,
+			Documents: []Document{{ID: docID, SplitID: "split1", Name: "W-2", Classification: "W-2", Filename: "w2.pdf", Pages: []*Page{
+				{ID: "page1", SplitID: "split1", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			}}},
+		}
+	}
+	name := "Renamed"
+	mutations := map[string]func(s *Split) error{
+		"rename":      func(s *Split) error { return s.Rename("other") },
+		"delete":      func(s *Split) error { return s.EnsureDeletable() },
+		"add":         func(s *Split) error { return s.AddDocument(&Document{ID: "doc2", Name: "New"}) },
+		"detach":      func(s *Split) error { _, err := s.DetachDocument(docID); return err },
+		"attach":      func(s *Split) error { return s.AttachDocument(&Document{ID: "doc2", Name: "New"}) },
+		"reorder":     func(s *Split) error { return s.ReorderDocuments([]string{docID}) },
+		"remove":      func(s *Split) error { return s.RemoveDocument(docID) },
+		"move pages":  func(s *Split) error { return s.MovePages(docID, "doc2", []string{"page1"}) },
+		"delete page": func(s *Split) error { return s.DeletePages(docID, []string{"page1"}) },
+		"update":      func(s *Split) error { return s.UpdateDocumentMetadata(docID, DocumentMetadata{Name: &name}) },
+		"reclassify":  func(s *Split) error { _, err := s.ReclassifyDocument(docID, "1099"); return err },
+		"review":      func(s *Split) error { return s.ReviewDocument(docID, true, "alice", time.Now()) },
+		"swap pages":  func(s *Split) error { return s.SwapPages(docID, "page1", "page2") },
+	}
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			err := mutate(newSplit())
+			assert.ErrorIs(t, err, ErrSplitFinalized)
+			var domainErr *DomainError
+			require.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+			assert.Equal(t, ReasonSplitFinalized, domainErr.Reason)
+		})
+	}
+
+	// Other conflicts do not match
+	split := newSplit()
+	split.Status = SplitStatusDraft
+	duplicate := split.Documents[0]
+	duplicate.Pages = []*Page{{ID: "page2", SplitID: "split1", PageNumber: 2, URL: "page_2.png"}}
+	err := split.AddDocument(&duplicate)
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+	assert.NotErrorIs(t, err, ErrSplitFinalized)
+}
+
+func TestNewSplit_MaxPages(t *testing.T) {
+	ingestion := `{
+		"split_id": "split1",
+		"client_id": "client456",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png", "page_2.png"]},
+			{"id": "doc2", "classification": "W-2", "file_name": "b.pdf", "name": "B", "pages": [{"url": "s3://bucket/b.png", "page_number": 3}]}
+		]
+	}`
+
+	_, err := NewSplit(ingestion, WithMaxPages(2))
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "split split1 has 3 pages, more than the allowed 2")
+
+	split, err := NewSplit(ingestion, WithMaxPages(3))
+	require.NoError(t, err)
+	assert.Len(t, split.Documents, 2)
+
+	// Zero means unlimited
+	_, err = NewSplit(ingestion, WithMaxPages(0))
+	require.NoError(t, err)
+}
+
+func TestNewSplit_ExplicitPageNumbers(t *testing.T) {
+	t.Run("pages with explicit numbers", func(t *testing.T) {
+		split, err := NewSplit(`{
+			"split_id": "split123",
+			"client_id": "client456",
+			"status": "draft",
+			"documents": [{
+				"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2",
+				"pages": [{"url": "s3://bucket/scan-b.png", "page_number": 7}, {"url": "s3://bucket/scan-a.png", "page_number": 3}]
+			}]
+		}`)
+		require.NoError(t, err)
+		pages := split.Documents[0].Pages
+		require.Len(t, pages, 2)
+		assert.Equal(t, 3, pages[0].PageNumber)
+		assert.Equal(t, "s3://bucket/scan-a.png", pages[0].URL)
+		assert.Equal(t, 7, pages[1].PageNumber)
+
+		// Explicit numbers survive an export round trip
+		roundTripped, err := NewSplit(split.ToIngestionJSON())
+		require.NoError(t, err)
+		assert.Equal(t, 3, roundTripped.Documents[0].Pages[0].PageNumber)
+		assert.Equal(t, 7, roundTripped.Documents[0].Pages[1].PageNumber)
+	})
+
+	t.Run("documents may use different shapes", func(t *testing.T) {
+		split, err := NewSplit(`{
+			"split_id": "split123",
+			"client_id": "client456",
+			"status": "draft",
+			"documents": [
+				{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png"]},
+				{"id": "doc2", "classification": "W-2", "file_name": "b.pdf", "name": "B", "pages": [{"url": "b.png", "page_number": 2}]}
+			]
+		}`)
+		require.NoError(t, err)
+		assert.Equal(t, 1, split.Documents[0].Pages[0].PageNumber)
+		assert.Equal(t, 2, split.Documents[1].Pages[0].PageNumber)
+	})
+
+	invalid := []struct {
+		name string
+		doc  string
+	}{
+		{name: "both shapes in one document", doc: `"page_urls": ["page_1.png"], "pages": [{"url": "x.png", "page_number": 2}]`},
+		{name: "missing page number", doc: `"pages": [{"url": "x.png"}]`},
+		{name: "negative page number", doc: `"pages": [{"url": "x.png", "page_number": -1}]`},
+		{name: "missing url", doc: `"pages": [{"page_number": 1}]`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSplit(`{"split_id": "split123", "client_id": "client456", "status": "draft", "documents": [
+				{"id": "doc1", "classification": "W-2", "file_name": "a.pdf", "name": "A", ` + tt.doc + `}
+			]}`)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSplit_ToIngestionJSONRoundTrip(t *testing.T) {
+	original, err := NewSplit(`{
+		"split_id": "split123",
+		"client_id": "client456",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "short_description": "From employer", "page_urls": ["page_1.png", "page_2.png"]},
+			{"id": "doc2", "classification": "1099", "file_name": "1099.pdf", "name": "1099", "short_description": "", "page_urls": ["page_3.png"]}
+		]
+	}`)
+	require.NoError(t, err)
+
+	roundTripped, err := NewSplit(original.ToIngestionJSON())
+	require.NoError(t, err)
+
+	assert.Equal(t, original.ID, roundTripped.ID)
+	assert.Equal(t, original.ClientID, roundTripped.ClientID)
+	assert.Equal(t, original.Status, roundTripped.Status)
+	require.Len(t, roundTripped.Documents, len(original.Documents))
+	for i, doc := range original.Documents {
+		got := roundTripped.Documents[i]
+		assert.Equal(t, doc.ID, got.ID)
+		assert.Equal(t, doc.Name, got.Name)
+		assert.Equal(t, doc.Classification, got.Classification)
+		assert.Equal(t, doc.Filename, got.Filename)
+		assert.Equal(t, doc.ShortDescription, got.ShortDescription)
+		assert.Equal(t, doc.StartPage, got.StartPage)
+		assert.Equal(t, doc.EndPage, got.EndPage)
+		require.Len(t, got.Pages, len(doc.Pages))
+		for j, page := range doc.Pages {
+			assert.Equal(t, page.URL, got.Pages[j].URL)
+			assert.Equal(t, page.PageNumber, got.Pages[j].PageNumber)
+		}
+	}
+
+	// Exporting again yields identical JSON
+	assert.JSONEq(t, original.ToIngestionJSON(), roundTripped.ToIngestionJSON())
+}
+
+func TestSplit_DeletePages(t *testing.T) {
+	newSplit := func(status SplitStatus) *Split {
+		docID := "doc1"
+		return &Split{
+			ID:       "split123",
+			ClientID: "client456",
+			Status:   status,
+			Documents: []Document{
+				{ID: docID, SplitID: "split123", Name: "Doc", Pages: []*Page{
+					{ID: "p1", SplitID: "split123", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+					{ID: "p2", SplitID: "split123", DocumentID: &docID, PageNumber: 2, URL: "page_2.png"},
+					{ID: "p3", SplitID: "split123", DocumentID: &docID, PageNumber: 3, URL: "page_3.png"},
+				}},
+			},
+		}
+	}
+
+	t.Run("drops pages without unassigning them", func(t *testing.T) {
+		split := newSplit(SplitStatusDraft)
+		require.NoError(t, split.DeletePages("doc1", []string{"p1", "p3"}))
+		require.Len(t, split.Documents[0].Pages, 1)
+		assert.Equal(t, "p2", split.Documents[0].Pages[0].ID)
+		assert.Equal(t, "page_2.png", split.Documents[0].StartPage)
+		assert.Empty(t, split.UnassignedPages)
+	})
+
+	t.Run("page not in document", func(t *testing.T) {
+		split := newSplit(SplitStatusDraft)
+		err := split.DeletePages("doc1", []string{"p1", "other"})
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Len(t, split.Documents[0].Pages, 3)
+	})
+
+	t.Run("every page", func(t *testing.T) {
+		err := newSplit(SplitStatusDraft).DeletePages("doc1", []string{"p1", "p2", "p3"})
+		assert.Error(t, err)
+	})
+
+	t.Run("finalized split", func(t *testing.T) {
+		err := newSplit(SplitStatusFinalized).DeletePages("doc1", []string{"p1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		err := newSplit(SplitStatusDraft).DeletePages("missing", []string{"p1"})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSplit_GenerateDocumentName(t *testing.T) {
+	split := &Split{ID: "split123", Documents: []Document{
+		{ID: "doc1", Name: "W-2 (1)", Classification: "W-2"},
+		{ID: "doc2", Name: "Invoice (1)", Classification: "Invoice"},
+		{ID: "doc3", Name: "W-2 (3)", Classification: "Other"},
+	}}
+
+	assert.Equal(t, "W-2 (2)", split.GenerateDocumentName("{classification} ({n})", "W-2"))
+	assert.Equal(t, "1099 (1)", split.GenerateDocumentName("{classification} ({n})", "1099"))
+	assert.Equal(t, "Invoice #2", split.GenerateDocumentName("{classification} #{n}", "Invoice"))
+
+	// Taken names are skipped
+	split.Documents[0].Classification = "W-2"
+	split.Documents = append(split.Documents, Document{ID: "doc4", Name: "W-2 (2)", Classification: "W-2"})
+	assert.Equal(t, "W-2 (4)", split.GenerateDocumentName("{classification} ({n})", "W-2"))
+}
+
+func TestSplit_ReclassifyDocument(t *testing.T) {
+	newSplit := func(status SplitStatus) *Split {
+		return &Split{
+			ID:       "split123",
+			ClientID: "client456",
+			Status:   status,
+			Documents: []Document{
+				{ID: "doc1", SplitID: "split123", Name: "Doc", Classification: "Invoice"},
+			},
+		}
+	}
+
+	t.Run("returns previous classification", func(t *testing.T) {
+		split := newSplit(SplitStatusDraft)
+		old, err := split.ReclassifyDocument("doc1", "W-2")
+		require.NoError(t, err)
+		assert.Equal(t, "Invoice", old)
+		assert.Equal(t, "W-2", split.Documents[0].Classification)
+	})
+
+	t.Run("empty classification", func(t *testing.T) {
+		_, err := newSplit(SplitStatusDraft).ReclassifyDocument("doc1", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("finalized split", func(t *testing.T) {
+		split := newSplit(SplitStatusFinalized)
+		_, err := split.ReclassifyDocument("doc1", "W-2")
+		assert.Error(t, err)
+		assert.Equal(t, "Invoice", split.Documents[0].Classification)
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		_, err := newSplit(SplitStatusDraft).ReclassifyDocument("missing", "W-2")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSplit_ReviewDocument(t *testing.T) {
+	newSplit := func(status SplitStatus) *Split {
+		return &Split{
+			ID:       "split123",
+			ClientID: "client456",
+			Status:   status,
+			Documents: []Document{
+				{ID: "doc1", SplitID: "split123", Name: "Doc", Classification: "Invoice"},
+				{ID: "doc2", SplitID: "split123", Name: "Other", Classification: "W-2"},
+			},
+		}
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("marks and clears review", func(t *testing.T) {
+		split := newSplit(SplitStatusDraft)
+		assert.Equal(t, []string{"doc1", "doc2"}, split.UnreviewedDocuments())
+
+		require.NoError(t, split.ReviewDocument("doc1", true, "alice", at))
+		assert.True(t, split.Documents[0].Reviewed)
+		assert.Equal(t, "alice", split.Documents[0].ReviewedBy)
+		assert.Equal(t, &at, split.Documents[0].ReviewedAt)
+		assert.Equal(t, []string{"doc2"}, split.UnreviewedDocuments())
+
+		require.NoError(t, split.ReviewDocument("doc1", false, "bob", at))
+		assert.False(t, split.Documents[0].Reviewed)
+		assert.Empty(t, split.Documents[0].ReviewedBy)
+		assert.Nil(t, split.Documents[0].ReviewedAt)
+	})
+
+	t.Run("finalized split", func(t *testing.T) {
+		split := newSplit(SplitStatusFinalized)
+		err := split.ReviewDocument("doc1", true, "alice", at)
+		var domainErr *DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+		assert.False(t, split.Documents[0].Reviewed)
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		err := newSplit(SplitStatusDraft).ReviewDocument("missing", true, "alice", at)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSplit_ValidateAll(t *testing.T) {
+	split := &Split{
+		ID:       "split1",
+		ClientID: "client1",
+		Status:   SplitStatusDraft,
+		Documents: []Document{
+			{ID: "doc1", SplitID: "split1", Name: "W-2", Classification: "W-2", Filename: "w2.pdf",
+				Pages: []*Page{{ID: "page1", SplitID: "split1", URL: "page_1.png"}}},
+			{ID: "doc2", SplitID: "split1", Classification: "1099", Filename: "1099.pdf",
+				Pages: []*Page{{ID: "page2", SplitID: "split1"}}},
+			{ID: "doc3", SplitID: "split1", Name: "Receipt"},
+		},
+		UnassignedPages: []*Page{{ID: "page4", URL: "page_4.png"}},
+	}
+
+	errs := split.ValidateAll()
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	assert.Equal(t, []string{
+		"invalid document doc2 in split split1: validation: document name is required",
+		"invalid document doc2 in split split1: validation: invalid page in document: validation: url is required",
+		"invalid document doc3 in split split1: validation: document classification is required",
+		"invalid document doc3 in split split1: validation: document filename is required",
+		"invalid document doc3 in split split1: validation: document must have at least one page",
+		"invalid unassigned page page4 in split split1: validation: split id is required",
+	}, messages)
+
+	// Valid still stops at the first issue
+	assert.EqualError(t, split.Valid(), "invalid document in split split1: validation: document name is required")
+
+	issues := split.FinalizeIssues()
+	require.Len(t, issues, len(errs)+1)
+	assert.Equal(t, "validation: cannot finalize split with unassigned pages", issues[0].Error())
+
+	// FinalizeBlockers groups the same issues by document and unassigned page
+	blockers := split.FinalizeBlockers()
+	assert.Equal(t, []string{"page4"}, blockers.UnassignedPageIDs)
+	var invalidDocs []string
+	for _, doc := range blockers.InvalidDocuments {
+		invalidDocs = append(invalidDocs, doc.DocumentID)
+	}
+	assert.Equal(t, []string{"doc2", "doc3"}, invalidDocs)
+	require.Len(t, blockers.Issues, 1)
+	assert.Equal(t, "invalid unassigned page page4: validation: split id is required", blockers.Issues[0].Error())
+
+	split.Documents = split.Documents[:1]
+	split.UnassignedPages = nil
+	assert.Empty(t, split.ValidateAll())
+	assert.Empty(t, split.FinalizeIssues())
+	assert.True(t, split.FinalizeBlockers().Empty())
+}
+
+func TestSplit_DetachAttachDocument(t *testing.T) {
+	docID := "doc1"
+	source := &Split{ID: "split1", Status: SplitStatusDraft, Documents: []Document{
+		{ID: "doc1", SplitID: "split1", Pages: []*Page{{ID: "page1", SplitID: "split1", DocumentID: &docID, PageNumber: 1}}, SortOrder: 2},
+		{ID: "doc2", SplitID: "split1", SortOrder: 1},
+	}}
+	target := &Split{ID: "split2", Status: SplitStatusDraft, Documents: []Document{
+		{ID: "doc3", SplitID: "split2"},
+	}}
+
+	doc, err := source.DetachDocument("doc1")
+	require.NoError(t, err)
+	require.Len(t, source.Documents, 1)
+	assert.Equal(t, "doc2", source.Documents[0].ID)
+
+	require.NoError(t, target.AttachDocument(doc))
+	require.Len(t, target.Documents, 2)
+	moved := target.Documents[1]
+	assert.Equal(t, "split2", moved.SplitID)
+	assert.Equal(t, 0, moved.SortOrder)
+	assert.Equal(t, "split2", moved.Pages[0].SplitID)
+	assert.Equal(t, "doc1", *moved.Pages[0].DocumentID)
+
+	// Documents cannot leave or enter finalized splits
+	_, err = source.DetachDocument("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assertConflict := func(err error) {
+		t.Helper()
+		var domainErr *DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, DomainErrorConflict, domainErr.Kind)
+	}
+	finalized := &Split{ID: "split3", Status: SplitStatusFinalized, Documents: []Document{{ID: "doc4"}}}
+	_, err = finalized.DetachDocument("doc4")
+	assertConflict(err)
+	assertConflict(finalized.AttachDocument(&Document{ID: "doc5"}))
+	assertConflict(target.AttachDocument(&Document{ID: "doc3"}))
+}
+
+func TestSplit_AttachDocumentRenumbersOverlappingPages(t *testing.T) {
+	docID := "doc3"
+	target := &Split{ID: "split2", Status: SplitStatusDraft,
+		Documents:       []Document{{ID: "doc1", SplitID: "split2", Pages: []*Page{{ID: "page1", PageNumber: 1}, {ID: "page2", PageNumber: 2}}}},
+		UnassignedPages: []*Page{{ID: "page3", PageNumber: 3}},
+	}
+
+	// Numbers free in the split are kept
+	require.NoError(t, target.AttachDocument(&Document{ID: "doc2", Pages: []*Page{{ID: "page4", PageNumber: 4}}}))
+	assert.Equal(t, 4, target.Documents[1].Pages[0].PageNumber)
+
+	// Overlapping numbers continue after the split's last page, in order
+	require.NoError(t, target.AttachDocument(&Document{ID: docID, Pages: []*Page{
+		{ID: "page5", DocumentID: &docID, PageNumber: 2},
+		{ID: "page6", DocumentID: &docID, PageNumber: 7},
+	}}))
+	moved := target.Documents[2]
+	assert.Equal(t, 5, moved.Pages[0].PageNumber)
+	assert.Equal(t, 6, moved.Pages[1].PageNumber)
+}
+
+func TestSplit_UpdateDocumentMetadataNotFound(t *testing.T) {
+	split := &Split{ID: "split1", Status: SplitStatusDraft}
+	name := "Renamed"
+	err := split.UpdateDocumentMetadata("missing", DocumentMetadata{Name: &name})
+	assert.ErrorIs(t, err, ErrNotFound)
+	var domainErr *DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "document", domainErr.Resource)
+	assert.Equal(t, "missing", domainErr.ResourceID)
+}
+
+func TestSplit_CompletionPercent(t *testing.T) {
+	docID := "doc1"
+	assigned := func(n int) []*Page {
+		pages := make([]*Page, n)
+		for i := range pages {
+			pages[i] = &Page{ID: fmt.Sprintf("page%d", i+1), SplitID: "split1", DocumentID: &docID, PageNumber: i + 1}
+		}
+		return pages
+	}
+	unassigned := func(n int) []*Page {
+		pages := make([]*Page, n)
+		for i := range pages {
+			pages[i] = &Page{ID: fmt.Sprintf("unassigned%d", i+1), SplitID: "split1", PageNumber: 100 + i}
+		}
+		return pages
+	}
+
+	tests := []struct {
+		name  string
+		split *Split
+		want  float64
+	}{
+		{name: "all assigned", split: &Split{Documents: []Document{{ID: docID, Pages: assigned(3)}}}, want: 100},
+		{name: "none assigned", split: &Split{Documents: []Document{{ID: docID}}, UnassignedPages: unassigned(2)}, want: 0},
+		{name: "mixed", split: &Split{Documents: []Document{{ID: docID, Pages: assigned(4)}}, UnassignedPages: unassigned(1)}, want: 80},
+		{name: "no pages", split: &Split{}, want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.split.CompletionPercent())
+		})
+	}
+}
@@ -6,6 +6,12 @@ import "context"
 type UnitOfWork interface {
 	// SplitRepository returns the split repository
 	SplitRepository() SplitRepository
+	// OutboxRepository returns the outbox repository
+	OutboxRepository() OutboxRepository
+	// AuditRepository returns the audit repository
+	AuditRepository() AuditRepository
+	// LockRepository returns the split lock repository
+	LockRepository() LockRepository
 	// Commit commits the transaction
 	Commit(ctx context.Context) error
 	// Rollback rolls back the transaction
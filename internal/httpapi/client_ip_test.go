@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.10 ", "", "::1"})
+	require.NoError(t, err)
+	assert.Len(t, prefixes, 3)
+
+	_, err = ParseTrustedProxies([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:       "no proxy",
+			remoteAddr: "203.0.113.7:51234",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:         "spoofed header from untrusted peer is ignored",
+			remoteAddr:   "203.0.113.7:51234",
+			forwardedFor: []string{"1.2.3.4"},
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy forwards client address",
+			remoteAddr:   "10.0.0.5:443",
+			forwardedFor: []string{"198.51.100.20"},
+			expectedIP:   "198.51.100.20",
+		},
+		{
+			name:         "spoofed entry prepended by client through trusted proxy",
+			remoteAddr:   "10.0.0.5:443",
+			forwardedFor: []string{"1.2.3.4, 198.51.100.20"},
+			expectedIP:   "198.51.100.20",
+		},
+		{
+			name:         "chain of trusted proxies",
+			remoteAddr:   "10.0.0.5:443",
+			forwardedFor: []string{"198.51.100.20, 10.1.1.1", "10.2.2.2"},
+			expectedIP:   "198.51.100.20",
+		},
+		{
+			name:         "malformed entry falls back to last trusted hop",
+			remoteAddr:   "10.0.0.5:443",
+			forwardedFor: []string{"garbage"},
+			expectedIP:   "10.0.0.5",
+		},
+		{
+			name:       "trusted proxy without header",
+			remoteAddr: "10.0.0.5:443",
+			expectedIP: "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/splits/123", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			assert.Equal(t, tt.expectedIP, ClientIP(req, trusted))
+		})
+	}
+}
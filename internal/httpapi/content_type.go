@@ -0,0 +1,27 @@
+package httpapi
+
+import (
+	"mime"
+	"net/http"
+)
+
+// IngestSplitPath is the path IngestSplitHandler is mounted at; its uploads are
+// multipart/form-data rather than JSON
+const IngestSplitPath = "/splits/ingest"
+
+// RequireJSONContentType rejects POST, PATCH and DELETE requests that carry a body
+// without a Content-Type of application/json (parameters such as charset are allowed).
+// Uploads to IngestSplitPath may be multipart/form-data instead.
+func RequireJSONContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == http.MethodPost || r.Method == http.MethodPatch || r.Method == http.MethodDelete) && r.ContentLength != 0 {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			upload := mediaType == "multipart/form-data" && r.URL.Path == IngestSplitPath
+			if err != nil || (mediaType != "application/json" && !upload) {
+				writeJSONError(w, http.StatusUnsupportedMediaType, "content type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
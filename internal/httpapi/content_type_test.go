@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireJSONContentType(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "json", method: http.MethodPost, contentType: "application/json", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "json with charset", method: http.MethodPatch, contentType: "application/json; charset=utf-8", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "form encoded", method: http.MethodPost, contentType: "application/x-www-form-urlencoded", body: "name=x", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "text plain patch", method: http.MethodPatch, contentType: "text/plain", body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: http.MethodPost, body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "post without body", method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "text plain delete", method: http.MethodDelete, contentType: "text/plain", body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "delete without body", method: http.MethodDelete, expectedStatus: http.StatusOK},
+		{name: "get ignores content type", method: http.MethodGet, contentType: "text/plain", expectedStatus: http.StatusOK},
+		{name: "multipart ingest upload", method: http.MethodPost, path: IngestSplitPath, contentType: "multipart/form-data; boundary=x", body: "--x--", expectedStatus: http.StatusOK},
+		{name: "multipart elsewhere", method: http.MethodPost, contentType: "multipart/form-data; boundary=x", body: "--x--", expectedStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireJSONContentType(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			path := tt.path
+			if path == "" {
+				path = "/documents"
+			}
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusUnsupportedMediaType {
+				var response map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "content type must be application/json", response["error"])
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// maxBodyBytes is the largest JSON request body the handlers accept
+const maxBodyBytes = 1 << 20 // 1MB
+
+// decodeJSON decodes a single JSON object from the request body into dst,
+// rejecting oversized bodies and unknown fields. On failure it writes the
+// error response and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil {
+		// Anything after the first object is an error
+		if dec.Decode(&struct{}{}) != io.EOF {
+			writeJSONError(w, http.StatusBadRequest, "request body must contain a single JSON object")
+			return false
+		}
+		return true
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+	var msg string
+	switch {
+	case errors.As(err, &maxBytesErr):
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit))
+		return false
+	case errors.As(err, &syntaxErr):
+		msg = fmt.Sprintf("request body contains malformed JSON (at position %d)", syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		msg = "request body contains malformed JSON"
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		msg = "request body must be a JSON object"
+	case errors.As(err, &typeErr):
+		msg = fmt.Sprintf("field %q must be %s", typeErr.Field, jsonTypeName(typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		msg = fmt.Sprintf("request body contains unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	case errors.Is(err, io.EOF):
+		msg = "request body must not be empty"
+	default:
+		msg = "invalid request body"
+	}
+	writeJSONError(w, http.StatusBadRequest, msg)
+	return false
+}
+
+// jsonTypeName describes the JSON value expected for a Go type, e.g.
+// "an array of strings" for []string
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array of " + plural(jsonTypeName(t.Elem()))
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a valid value"
+	}
+}
+
+// plural turns "a string" into "strings" and "an array of x" into "arrays of x"
+func plural(name string) string {
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "an "), "a ")
+	if head, rest, ok := strings.Cut(name, " of "); ok {
+		return head + "s of " + rest
+	}
+	return name + "s"
+}
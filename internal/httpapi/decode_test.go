@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"accounting/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "valid body",
+			body:           `{"name":"Invoice"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown field",
+			body:           `{"nmae":"Invoice"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `request body contains unknown field "nmae"`,
+		},
+		{
+			name:           "malformed json",
+			body:           `{"name":}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "request body contains malformed JSON (at position 9)",
+		},
+		{
+			name:           "wrong type",
+			body:           `{"name":42}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `field "name" must be a string`,
+		},
+		{
+			name:           "wrong array element type",
+			body:           `{"name":"Invoice","page_ids":["p1",2]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `field "page_ids.1" must be a string`,
+		},
+		{
+			name:           "scalar instead of array",
+			body:           `{"name":"Invoice","page_ids":"p1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `field "page_ids" must be an array of strings`,
+		},
+		{
+			name:           "not an object",
+			body:           `["Invoice"]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "request body must be a JSON object",
+		},
+		{
+			name:           "empty body",
+			body:           ``,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "request body must not be empty",
+		},
+		{
+			name:           "multiple objects",
+			body:           `{"name":"a"}{"name":"b"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "request body must contain a single JSON object",
+		},
+		{
+			name:           "too large",
+			body:           `{"name":"` + strings.Repeat("x", maxBodyBytes) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "request body must not be larger than 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			var dst struct {
+				Name    string   `json:"name"`
+				PageIDs []string `json:"page_ids"`
+			}
+			ok := decodeJSON(w, req, &dst)
+			if tt.expectedStatus == http.StatusOK {
+				assert.True(t, ok)
+				assert.Equal(t, "Invoice", dst.Name)
+				return
+			}
+			assert.False(t, ok)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.expectedError, response["error"])
+		})
+	}
+}
+
+func TestCreateDocumentHandlerRejectsUnknownField(t *testing.T) {
+	called := false
+	mockService := &MockSplitService{
+		createDocumentFunc: func(ctx context.Context, req services.CreateDocumentRequest) (*services.DocumentResponse, error) {
+			called = true
+			return &services.DocumentResponse{}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	body := `{"split_id":"split-1","name":"Invoice","clasification":"W-2"}`
+	req := httptest.NewRequest(http.MethodPost, "/documents", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.CreateDocumentHandler(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, called)
+	var response map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, `request body contains unknown field "clasification"`, response["error"])
+}
@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"accounting/internal/domain"
+	"accounting/internal/services"
+)
+
+// domainErrorStatus maps domain error kinds to HTTP status codes
+var domainErrorStatus = map[domain.DomainErrorKind]int{
+	domain.DomainErrorValidation:  http.StatusBadRequest,
+	domain.DomainErrorNotFound:    http.StatusNotFound,
+	domain.DomainErrorConflict:    http.StatusConflict,
+	domain.DomainErrorLocked:      http.StatusLocked,
+	domain.DomainErrorUnavailable: http.StatusServiceUnavailable,
+	domain.DomainErrorInternal:    http.StatusInternalServerError,
+}
+
+// errorKindCounter counts the domain errors translated by the handlers, by kind
+type errorKindCounter struct {
+	mu     sync.Mutex
+	counts map[domain.DomainErrorKind]int64
+}
+
+func newErrorKindCounter() *errorKindCounter {
+	return &errorKindCounter{counts: make(map[domain.DomainErrorKind]int64)}
+}
+
+func (c *errorKindCounter) increment(kind domain.DomainErrorKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[kind]++
+}
+
+func (c *errorKindCounter) snapshot() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int64, len(c.counts))
+	for kind, n := range c.counts {
+		counts[string(kind)] = n
+	}
+	return counts
+}
+
+// DomainErrorCounts returns the number of error responses per domain error kind
+func (h *SplitHandler) DomainErrorCounts() map[string]int64 {
+	return h.errorKinds.snapshot()
+}
+
+// writeServiceError translates a service error into a JSON error response
+func (h *SplitHandler) writeServiceError(w http.ResponseWriter, err error) {
+	var domainErr *domain.DomainError
+	isDomainErr := errors.As(err, &domainErr)
+
+	if errors.Is(err, domain.ErrNotFound) {
+		h.errorKinds.increment(domain.DomainErrorNotFound)
+		if isDomainErr && domainErr.Resource != "" {
+			writeNotFound(w, domainErr.Resource, domainErr.ResourceID)
+			return
+		}
+		writeJSONError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	// A refused finalize lists all its blocking issues
+	var blockers *domain.FinalizeBlockers
+	if errors.As(err, &blockers) {
+		h.writeFinalizeBlocked(w, err, blockers)
+		return
+	}
+
+	if isDomainErr {
+		status, ok := domainErrorStatus[domainErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		h.errorKinds.increment(domainErr.Kind)
+		if domainErr.Reason != "" {
+			writeJSONErrorCode(w, status, err.Error(), domainErr.Reason)
+			return
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+
+	writeJSONError(w, http.StatusInternalServerError, err.Error())
+}
+
+// writeFinalizeBlocked writes a 422 listing every issue blocking a finalize
+func (h *SplitHandler) writeFinalizeBlocked(w http.ResponseWriter, err error, blockers *domain.FinalizeBlockers) {
+	h.errorKinds.increment(domain.DomainErrorValidation)
+	resp := &services.FinalizeBlockedResponse{
+		Error:             err.Error(),
+		UnassignedPageIDs: make([]string, 0, len(blockers.UnassignedPageIDs)),
+		InvalidDocuments:  make([]*services.InvalidDocumentResponse, 0, len(blockers.InvalidDocuments)),
+		Issues:            make([]string, 0, len(blockers.Issues)),
+	}
+	resp.UnassignedPageIDs = append(resp.UnassignedPageIDs, blockers.UnassignedPageIDs...)
+	for _, doc := range blockers.InvalidDocuments {
+		invalid := &services.InvalidDocumentResponse{DocumentID: doc.DocumentID, Reasons: make([]string, len(doc.Issues))}
+		for i, issue := range doc.Issues {
+			invalid.Reasons[i] = issue.Error()
+		}
+		resp.InvalidDocuments = append(resp.InvalidDocuments, invalid)
+	}
+	for _, issue := range blockers.Issues {
+		resp.Issues = append(resp.Issues, issue.Error())
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, resp)
+}
+
+// writeJSONErrorCode writes an error response with a code clients can act on,
+// e.g. split_finalized to offer reopening the split
+func writeJSONErrorCode(w http.ResponseWriter, status int, msg, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	b, _ := json.Marshal(map[string]string{"error": msg, "code": code})
+	w.Write(b)
+}
+
+// writeNotFound writes a 404 naming the missing resource type and ID
+func writeNotFound(w http.ResponseWriter, resource, id string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	b, _ := json.Marshal(map[string]string{"error": "not found", "resource": resource, "id": id})
+	w.Write(b)
+}
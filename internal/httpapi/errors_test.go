@@ -0,0 +1,152 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"accounting/internal/domain"
+	"accounting/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedKind   string
+	}{
+		{
+			name:           "not found sentinel",
+			err:            domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   "not_found",
+		},
+		{
+			name:           "validation error",
+			err:            domain.NewValidationError("document name is required", nil),
+			expectedStatus: http.StatusBadRequest,
+			expectedKind:   "validation",
+		},
+		{
+			name:           "wrapped conflict error",
+			err:            errors.Join(errors.New("context"), domain.NewConflictError("split already finalized", nil)),
+			expectedStatus: http.StatusConflict,
+			expectedKind:   "conflict",
+		},
+		{
+			name:           "unclassified error",
+			err:            errors.New("database is locked"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+					return nil, tt.err
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodPost, "/splits/123/finalize", nil)
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.FinalizeSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.NotEmpty(t, response["error"])
+
+			counts := handler.DomainErrorCounts()
+			if tt.expectedKind == "" {
+				assert.Empty(t, counts)
+				return
+			}
+			assert.Equal(t, map[string]int64{tt.expectedKind: 1}, counts)
+		})
+	}
+}
+
+func TestWriteServiceErrorNotFoundShape(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedBody map[string]interface{}
+	}{
+		{
+			name:         "split",
+			err:          domain.NewNotFoundError("split", "split-1", "split not found", nil),
+			expectedBody: map[string]interface{}{"error": "not found", "resource": "split", "id": "split-1"},
+		},
+		{
+			name:         "document",
+			err:          domain.NewNotFoundError("document", "doc-1", "source document not found", nil),
+			expectedBody: map[string]interface{}{"error": "not found", "resource": "document", "id": "doc-1"},
+		},
+		{
+			name:         "page",
+			err:          fmt.Errorf("moving pages: %w", domain.NewNotFoundError("page", "page-1", "page not found", nil)),
+			expectedBody: map[string]interface{}{"error": "not found", "resource": "page", "id": "page-1"},
+		},
+		{
+			name:         "untyped sentinel",
+			err:          domain.ErrNotFound,
+			expectedBody: map[string]interface{}{"error": "not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+					return nil, tt.err
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodPost, "/splits/123/finalize", nil)
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.FinalizeSplitHandler(w, req)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.expectedBody, response)
+			assert.Equal(t, map[string]int64{"not_found": 1}, handler.DomainErrorCounts())
+		})
+	}
+}
+
+func TestWriteServiceErrorSplitFinalizedCode(t *testing.T) {
+	mockService := &MockSplitService{
+		renameSplitFunc: func(ctx context.Context, splitID, name string) (*services.LoadSplitResponse, error) {
+			return nil, domain.NewSplitFinalizedError("cannot rename finalized split")
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	req := httptest.NewRequest(http.MethodPatch, "/splits/123", strings.NewReader(`{"name": "2024"}`))
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.UpdateSplitHandler(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, map[string]interface{}{
+		"error": "conflict: cannot rename finalized split",
+		"code":  "split_finalized",
+	}, response)
+	assert.Equal(t, map[string]int64{"conflict": 1}, handler.DomainErrorCounts())
+}
@@ -1,20 +1,54 @@
 package httpapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
-	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
 	"accounting/internal/services"
 )
 
-// writeJSON writes a JSON response
+// Pagination bounds for GET /clients and GET /clients/{id}/pages
+const (
+	defaultClientsLimit = 100
+	maxClientsLimit     = 1000
+)
+
+// writeJSON writes a JSON response, indented if PrettyJSON marked the writer
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
+	enc := json.NewEncoder(w)
+	if _, ok := w.(*prettyJSONWriter); ok {
+		enc.SetIndent("", "  ")
+	}
+	_ = enc.Encode(v)
+}
+
+// lazyJSONWriter sends the JSON content type and a 200 status with the first
+// write, so errors raised before any output can still get their own status
+type lazyJSONWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (lw *lazyJSONWriter) Write(p []byte) (int, error) {
+	if !lw.started {
+		lw.started = true
+		lw.w.Header().Set("Content-Type", "application/json")
+		lw.w.WriteHeader(http.StatusOK)
+	}
+	return lw.w.Write(p)
 }
 
 // TokenVerifier is a local interface for verifying JWT tokens
@@ -26,25 +60,25 @@ type TokenVerifier interface {
 type SplitHandler struct {
 	splitSvc      services.SplitServiceInterface
 	tokenVerifier TokenVerifier
+	errorKinds    *errorKindCounter
+	// admins are the token subjects allowed to use admin-only operations
+	admins map[string]struct{}
+	// ingestSvc creates splits from uploads (nil disables ingestion)
+	ingestSvc ports.SplitIngestionService
 }
 
+// maxIngestBytes is the largest split upload, page files included, the handlers accept
+const maxIngestBytes = 512 << 20 // 512MB
+
 // NewSplitHandler creates a new SplitHandler
 func NewSplitHandler(splitSvc services.SplitServiceInterface, tokenVerifier TokenVerifier) *SplitHandler {
 	return &SplitHandler{
 		splitSvc:      splitSvc,
 		tokenVerifier: tokenVerifier,
+		errorKinds:    newErrorKindCounter(),
 	}
 }
 
-// Helper to extract the ID from the path (second segment)
-func getIDFromPath(r *http.Request) string {
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) >= 2 {
-		return parts[1]
-	}
-	return ""
-}
-
 // Helper to write JSON error without trailing newline
 func writeJSONError(w http.ResponseWriter, status int, msg string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -53,7 +87,45 @@ func writeJSONError(w http.ResponseWriter, status int, msg string) {
 	w.Write(b)
 }
 
-// LoadSplitHandler handles GET requests to load a split
+// SetAdmins sets the users allowed to use admin-only operations such as force-deleting splits
+func (h *SplitHandler) SetAdmins(admins []string) {
+	h.admins = make(map[string]struct{}, len(admins))
+	for _, a := range admins {
+		h.admins[a] = struct{}{}
+	}
+}
+
+// SetIngestionService sets the service creating splits from uploads to POST /splits/ingest
+func (h *SplitHandler) SetIngestionService(svc ports.SplitIngestionService) {
+	h.ingestSvc = svc
+}
+
+// subjectToken is implemented by verified tokens that carry a subject (e.g. jwt.Token)
+type subjectToken interface {
+	Subject() (string, bool)
+}
+
+// tokenSubject returns the subject of a verified token, if any
+func tokenSubject(token any) string {
+	if t, ok := token.(subjectToken); ok {
+		if subject, ok := t.Subject(); ok {
+			return subject
+		}
+	}
+	return ""
+}
+
+// isAdmin reports whether the subject is one of the configured admins
+func (h *SplitHandler) isAdmin(subject string) bool {
+	if subject == "" {
+		return false
+	}
+	_, ok := h.admins[subject]
+	return ok
+}
+
+// LoadSplitHandler handles GET requests to load a split. The include query parameter
+// (documents, unassigned, or both comma separated) limits the response to those parts.
 func (h *SplitHandler) LoadSplitHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -79,28 +151,68 @@ func (h *SplitHandler) LoadSplitHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	id := getIDFromPath(r)
+	id := r.PathValue("id")
 	if id == "" {
 		writeJSONError(w, http.StatusBadRequest, "split ID is required")
 		return
 	}
 
-	resp, err := h.splitSvc.LoadSplit(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
+	include := r.URL.Query().Get("include")
+	switch r.URL.Query().Get("stream") {
+	case "", "false":
+	case "true":
+		if include != "" {
+			writeJSONError(w, http.StatusBadRequest, "stream cannot be combined with include")
+			return
+		}
+		// Once output has started the status is sent; a later failure leaves the
+		// body truncated, which clients detect as invalid JSON
+		sw := &lazyJSONWriter{w: w}
+		if err := h.splitSvc.StreamSplit(r.Context(), id, sw); err != nil && !sw.started {
+			h.writeServiceError(w, err)
+		}
+		return
+	default:
+		writeJSONError(w, http.StatusBadRequest, "stream must be true or false")
+		return
+	}
+
+	if include == "" {
+		resp, err := h.splitSvc.LoadSplit(r.Context(), id)
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	req := services.LoadSplitRequest{ID: id}
+	for _, part := range strings.Split(include, ",") {
+		switch part {
+		case "documents":
+			req.IncludeDocuments = true
+		case "unassigned":
+			req.IncludeUnassigned = true
+		default:
+			writeJSONError(w, http.StatusBadRequest, "include must be a comma-separated list of documents and unassigned")
 			return
 		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+
+	resp, err := h.splitSvc.LoadSplitParts(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
-// UpdateDocumentMetadataHandler handles PATCH requests to update document metadata
-func (h *SplitHandler) UpdateDocumentMetadataHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch {
+// IngestSplitHandler creates a split from a multipart/form-data upload of the split
+// JSON followed by its page files, streaming the pages to the blob store
+func (h *SplitHandler) IngestSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
@@ -118,39 +230,45 @@ func (h *SplitHandler) UpdateDocumentMetadataHandler(w http.ResponseWriter, r *h
 	}
 
 	// Verify the token
-	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
 	if err != nil {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
 
-	id := getIDFromPath(r)
-	if id == "" {
-		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+	if h.ingestSvc == nil {
+		writeJSONError(w, http.StatusNotImplemented, "split ingestion is not enabled")
 		return
 	}
 
-	var req services.UpdateDocumentMetadataRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "content type must be multipart/form-data")
 		return
 	}
 
-	resp, err := h.splitSvc.UpdateDocumentMetadata(r.Context(), id, req)
+	r.Body = http.MaxBytesReader(w, r.Body, maxIngestBytes)
+	resp, err := h.ingestSvc.IngestSplit(services.WithActor(r.Context(), tokenSubject(token)), ports.IngestSplitRequest{
+		ClientID: r.URL.Query().Get("client_id"),
+		File:     r.Body,
+		Boundary: params["boundary"],
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit))
 			return
 		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	w.Header().Set("Location", "/splits/"+url.PathEscape(resp.SplitID))
+	writeJSON(w, http.StatusCreated, &services.IngestSplitResponse{SplitID: resp.SplitID})
 }
 
-// MovePagesHandler handles POST requests to move pages between documents
-func (h *SplitHandler) MovePagesHandler(w http.ResponseWriter, r *http.Request) {
+// BatchGetSplitsHandler handles POST requests loading several splits by ID at once
+func (h *SplitHandler) BatchGetSplitsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
@@ -175,32 +293,29 @@ func (h *SplitHandler) MovePagesHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var req services.MovePagesRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+	var req services.BatchGetSplitsRequest
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if len(req.PageIDs) == 0 {
-		writeJSONError(w, http.StatusBadRequest, "page IDs are required")
+	if len(req.IDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "split IDs are required")
 		return
 	}
 
-	resp, err := h.splitSvc.MovePages(r.Context(), req)
+	resp, err := h.splitSvc.BatchGetSplits(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
-			return
-		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
-// CreateDocumentHandler handles POST requests to create a new document
-func (h *SplitHandler) CreateDocumentHandler(w http.ResponseWriter, r *http.Request) {
+// FinalizeSplitsHandler handles POST requests finalizing several splits at once.
+// Each split is finalized on its own, so the response is 200 with a result per split
+// even when some of them fail.
+func (h *SplitHandler) FinalizeSplitsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
@@ -219,39 +334,75 @@ func (h *SplitHandler) CreateDocumentHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Verify the token
-	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
 	if err != nil {
 		http.Error(w, "Invalid token", http.StatusUnauthorized)
 		return
 	}
 
-	var req services.CreateDocumentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+	var req services.FinalizeSplitsRequest
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	if len(req.PageIDs) == 0 {
-		writeJSONError(w, http.StatusBadRequest, "page IDs are required")
+	if len(req.SplitIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "split IDs are required")
 		return
 	}
 
-	resp, err := h.splitSvc.CreateDocument(r.Context(), req)
+	resp, err := h.splitSvc.FinalizeSplits(services.WithActor(r.Context(), tokenSubject(token)), req.SplitIDs)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
-			return
-		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, resp)
+	writeJSON(w, http.StatusOK, resp)
 }
 
-// DeleteDocumentHandler handles DELETE requests to remove a document
-func (h *SplitHandler) DeleteDocumentHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
+// DiffSplitsHandler handles GET requests comparing split {other} against split {id}
+func (h *SplitHandler) DiffSplitsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id, other := r.PathValue("id"), r.PathValue("other")
+	if id == "" || other == "" {
+		writeJSONError(w, http.StatusBadRequest, "both split IDs are required")
+		return
+	}
+
+	resp, err := h.splitSvc.DiffSplits(r.Context(), id, other)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ExportSplitJSONHandler handles GET requests exporting a split in the ingestion format
+func (h *SplitHandler) ExportSplitJSONHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
@@ -275,28 +426,26 @@ func (h *SplitHandler) DeleteDocumentHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	id := getIDFromPath(r)
+	id := r.PathValue("id")
 	if id == "" {
-		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
 		return
 	}
 
-	err = h.splitSvc.DeleteDocument(r.Context(), id)
+	data, err := h.splitSvc.ExportSplitJSON(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
-			return
-		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
 }
 
-// FinalizeSplitHandler handles POST requests to finalize a split
-func (h *SplitHandler) FinalizeSplitHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// ExportSplitCSVHandler handles GET requests exporting a split's documents as a CSV spreadsheet
+func (h *SplitHandler) ExportSplitCSVHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
@@ -320,27 +469,26 @@ func (h *SplitHandler) FinalizeSplitHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	id := getIDFromPath(r)
+	id := r.PathValue("id")
 	if id == "" {
 		writeJSONError(w, http.StatusBadRequest, "split ID is required")
 		return
 	}
 
-	err = h.splitSvc.FinalizeSplit(r.Context(), id)
+	data, err := h.splitSvc.ExportSplitCSV(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
-			return
-		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".csv"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
 }
 
-// DownloadDocumentHandler handles GET requests to download a document
-func (h *SplitHandler) DownloadDocumentHandler(w http.ResponseWriter, r *http.Request) {
+// PreviewSplitHandler handles GET requests for a combined preview PDF of a split's documents
+func (h *SplitHandler) PreviewSplitHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
@@ -365,23 +513,1404 @@ func (h *SplitHandler) DownloadDocumentHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	id := getIDFromPath(r)
+	id := r.PathValue("id")
 	if id == "" {
-		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
 		return
 	}
 
-	resp, err := h.splitSvc.DownloadDocument(r.Context(), id)
+	resp, err := h.splitSvc.PreviewSplit(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeJSONError(w, http.StatusNotFound, "not found")
-			return
-		}
-		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		h.writeServiceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Type", resp.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", resp.Filename))
 	w.WriteHeader(http.StatusOK)
-	w.Write(resp.Data)
+	io.Copy(w, bytes.NewReader(resp.Data))
+}
+
+// UpdateDocumentMetadataHandler handles PATCH requests to update document metadata
+func (h *SplitHandler) UpdateDocumentMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	var req services.UpdateDocumentMetadataRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.splitSvc.UpdateDocumentMetadata(services.WithActor(r.Context(), tokenSubject(token)), id, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ReclassifyDocumentHandler handles POST requests to change a document's classification
+func (h *SplitHandler) ReclassifyDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	var req services.ReclassifyDocumentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Classification == "" {
+		writeJSONError(w, http.StatusBadRequest, "classification is required")
+		return
+	}
+
+	resp, err := h.splitSvc.ReclassifyDocument(services.WithActor(r.Context(), tokenSubject(token)), id, req.Classification)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// MoveDocumentToSplitHandler handles POST requests moving a document with its pages to another split
+func (h *SplitHandler) MoveDocumentToSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	var req services.MoveDocumentToSplitRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.SplitID == "" {
+		writeJSONError(w, http.StatusBadRequest, "split_id is required")
+		return
+	}
+
+	resp, err := h.splitSvc.MoveDocumentToSplit(services.WithActor(r.Context(), tokenSubject(token)), id, req.SplitID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ReviewDocumentHandler handles POST requests marking a document reviewed by the caller
+func (h *SplitHandler) ReviewDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	h.setDocumentReviewed(w, r, http.MethodPost, true)
+}
+
+// UnreviewDocumentHandler handles DELETE requests clearing a document's review
+func (h *SplitHandler) UnreviewDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	h.setDocumentReviewed(w, r, http.MethodDelete, false)
+}
+
+func (h *SplitHandler) setDocumentReviewed(w http.ResponseWriter, r *http.Request, method string, reviewed bool) {
+	if r.Method != method {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.ReviewDocument(services.WithActor(r.Context(), tokenSubject(token)), id, reviewed)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// MovePagesHandler handles POST requests to move pages between documents
+func (h *SplitHandler) MovePagesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.MovePagesRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.PageIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "page IDs are required")
+		return
+	}
+
+	resp, err := h.splitSvc.MovePages(services.WithActor(r.Context(), tokenSubject(token)), req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateDocumentHandler handles POST requests to create a new document
+func (h *SplitHandler) CreateDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.CreateDocumentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.PageIDs) == 0 && req.PageRange == "" {
+		writeJSONError(w, http.StatusBadRequest, "page IDs are required")
+		return
+	}
+
+	resp, err := h.splitSvc.CreateDocument(services.WithActor(r.Context(), tokenSubject(token)), req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/documents/"+url.PathEscape(resp.ID))
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// DeleteDocumentHandler handles DELETE requests to remove a document
+func (h *SplitHandler) DeleteDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	err = h.splitSvc.DeleteDocument(services.WithActor(r.Context(), tokenSubject(token)), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteDocumentsHandler handles POST requests deleting several documents at once.
+// Documents that cannot be deleted are reported and skipped, so the response is 200
+// with a result per document even when some of them fail.
+func (h *SplitHandler) DeleteDocumentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.DeleteDocumentsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.DocumentIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "document IDs are required")
+		return
+	}
+
+	resp, err := h.splitSvc.DeleteDocuments(services.WithActor(r.Context(), tokenSubject(token)), req.DocumentIDs)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DeletePagesHandler handles DELETE requests dropping pages from a document
+func (h *SplitHandler) DeletePagesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	var req services.DeletePagesRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.PageIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "page_ids is required")
+		return
+	}
+
+	resp, err := h.splitSvc.DeletePages(services.WithActor(r.Context(), tokenSubject(token)), id, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DeleteSplitHandler handles DELETE requests for a split. Finalized splits are refused
+// unless an admin passes force=true, which deletes the split regardless and audits it.
+func (h *SplitHandler) DeleteSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	force := r.URL.Query().Get("force")
+	switch force {
+	case "", "false":
+		err = h.splitSvc.DeleteSplit(services.WithActor(r.Context(), tokenSubject(token)), id)
+	case "true":
+		subject := tokenSubject(token)
+		if !h.isAdmin(subject) {
+			writeJSONError(w, http.StatusForbidden, "force delete requires admin")
+			return
+		}
+		err = h.splitSvc.ForceDeleteSplit(r.Context(), id, subject)
+	default:
+		writeJSONError(w, http.StatusBadRequest, "force must be true or false")
+		return
+	}
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FinalizeSplitHandler handles POST requests to finalize a split. With dry_run=true it
+// only reports every issue that would prevent finalizing, without changing the split.
+// Finalizing an already finalized split returns 200 with the original finalization time.
+func (h *SplitHandler) FinalizeSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	switch r.URL.Query().Get("dry_run") {
+	case "", "false":
+	case "true":
+		resp, err := h.splitSvc.CheckFinalizeSplit(r.Context(), id)
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	default:
+		writeJSONError(w, http.StatusBadRequest, "dry_run must be true or false")
+		return
+	}
+
+	resp, err := h.splitSvc.FinalizeSplit(services.WithActor(r.Context(), tokenSubject(token)), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	// A retry of an earlier finalize succeeds and reports when the split was finalized
+	if resp != nil && resp.AlreadyFinalized {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LockSplitHandler handles POST requests acquiring the caller's lock on a split.
+// While the lock is held, changes by anyone else are refused with 423 Locked.
+func (h *SplitHandler) LockSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.LockSplit(services.WithActor(r.Context(), tokenSubject(token)), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// UnlockSplitHandler handles DELETE requests releasing the caller's lock on a split
+func (h *SplitHandler) UnlockSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	err = h.splitSvc.UnlockSplit(services.WithActor(r.Context(), tokenSubject(token)), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EstimatedContentLengthHeader carries the estimated size of a download in
+// answers to HEAD requests when its exact Content-Length is not known yet
+const EstimatedContentLengthHeader = "X-Estimated-Content-Length"
+
+// DownloadDocumentHandler handles GET requests to download a document and HEAD
+// requests for its download headers, which do not render it
+func (h *SplitHandler) DownloadDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	h.serveDocumentDownload(w, r, "", id)
+}
+
+// DownloadSplitDocumentHandler handles GET and HEAD requests to download a
+// document addressed by its split, which saves looking the split up
+func (h *SplitHandler) DownloadSplitDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	splitID := r.PathValue("id")
+	if splitID == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+	docID := r.PathValue("docId")
+	if docID == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	h.serveDocumentDownload(w, r, splitID, docID)
+}
+
+// serveDocumentDownload writes the download of a document, or only its headers
+// for HEAD requests. splitID is empty if the split is not known.
+func (h *SplitHandler) serveDocumentDownload(w http.ResponseWriter, r *http.Request, splitID, id string) {
+	if r.Method == http.MethodHead {
+		var info *services.DocumentDownloadInfoResponse
+		var err error
+		if splitID == "" {
+			info, err = h.splitSvc.DocumentDownloadInfo(r.Context(), id)
+		} else {
+			info, err = h.splitSvc.SplitDocumentDownloadInfo(r.Context(), splitID, id)
+		}
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		w.Header().Set("ETag", info.ETag)
+		w.Header().Set("Content-Type", info.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", info.Filename))
+		w.Header().Set("Accept-Ranges", "bytes")
+		if !info.ModifiedAt.IsZero() {
+			w.Header().Set("Last-Modified", info.ModifiedAt.UTC().Format(http.TimeFormat))
+		}
+		if info.SizeEstimated {
+			w.Header().Set(EstimatedContentLengthHeader, strconv.FormatInt(info.Size, 10))
+		} else {
+			w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var resp *services.DownloadDocumentResponse
+	var err error
+	if splitID == "" {
+		resp, err = h.splitSvc.DownloadDocument(r.Context(), id)
+	} else {
+		resp, err = h.splitSvc.DownloadSplitDocument(r.Context(), splitID, id)
+	}
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	if resp.ETag != "" {
+		w.Header().Set("ETag", resp.ETag)
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", resp.Filename))
+
+	// ServeContent answers If-None-Match, If-Modified-Since and Range requests
+	// (206 with Accept-Ranges) from the rendered bytes
+	http.ServeContent(w, r, resp.Filename, resp.ModifiedAt, bytes.NewReader(resp.Data))
+}
+
+// ReorderDocumentsHandler handles POST requests to reorder the documents of a split
+func (h *SplitHandler) ReorderDocumentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	var req services.ReorderDocumentsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if len(req.DocumentIDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "document IDs are required")
+		return
+	}
+
+	resp, err := h.splitSvc.ReorderDocuments(services.WithActor(r.Context(), tokenSubject(token)), id, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SwapPagesHandler handles POST requests to swap the positions of two pages in a document
+func (h *SplitHandler) SwapPagesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	var req services.SwapPagesRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.PageA == "" || req.PageB == "" {
+		writeJSONError(w, http.StatusBadRequest, "page_a and page_b are required")
+		return
+	}
+
+	resp, err := h.splitSvc.SwapPages(services.WithActor(r.Context(), tokenSubject(token)), id, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DocumentPageCountHandler handles GET requests for the number of pages of a document
+func (h *SplitHandler) DocumentPageCountHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "document ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.CountDocumentPages(r.Context(), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// UpdateSplitHandler handles PATCH requests to update a split; only renaming is supported
+func (h *SplitHandler) UpdateSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	var req services.UpdateSplitRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == nil {
+		writeJSONError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	resp, err := h.splitSvc.RenameSplit(services.WithActor(r.Context(), tokenSubject(token)), id, *req.Name)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// AddSplitTagsHandler handles POST requests to add tags to a split
+func (h *SplitHandler) AddSplitTagsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	h.updateSplitTags(w, r, h.splitSvc.AddSplitTags)
+}
+
+// RemoveSplitTagsHandler handles DELETE requests to remove tags from a split
+func (h *SplitHandler) RemoveSplitTagsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	h.updateSplitTags(w, r, h.splitSvc.RemoveSplitTags)
+}
+
+// updateSplitTags authenticates a request naming tags in its body and applies
+// update to the split in the path
+func (h *SplitHandler) updateSplitTags(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error)) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	var req services.SplitTagsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.Tags) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "tags is required")
+		return
+	}
+
+	resp, err := update(services.WithActor(r.Context(), tokenSubject(token)), id, req.Tags)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// PageContentHandler handles GET requests to fetch the raw content of a page
+func (h *SplitHandler) PageContentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "page ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.GetPageContent(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrBlobUnavailable) {
+			writeJSONError(w, http.StatusBadGateway, "page content unavailable")
+			return
+		}
+		h.writeServiceError(w, err)
+		return
+	}
+	defer resp.Content.Close()
+
+	w.Header().Set("Content-Type", resp.ContentType)
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, resp.Content)
+}
+
+// ClientStatsHandler handles GET requests for aggregate split statistics of a client
+func (h *SplitHandler) ClientStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "client ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.ClientStats(r.Context(), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListClientsHandler handles GET requests listing the clients that own splits.
+// Pagination uses the limit (default 100, max 1000) and offset query parameters.
+// It spans every client, so only admins may call it.
+func (h *SplitHandler) ListClientsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.isAdmin(tokenSubject(token)) {
+		writeJSONError(w, http.StatusForbidden, "listing clients requires admin")
+		return
+	}
+
+	limit, ok := queryInt(w, r, "limit", defaultClientsLimit, 1, maxClientsLimit)
+	if !ok {
+		return
+	}
+	offset, ok := queryInt(w, r, "offset", 0, 0, math.MaxInt32)
+	if !ok {
+		return
+	}
+
+	resp, err := h.splitSvc.ListClients(r.Context(), services.ListClientsRequest{Limit: limit, Offset: offset})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListClientPagesHandler handles GET requests listing a client's pages by document classification
+func (h *SplitHandler) ListClientPagesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "client ID is required")
+		return
+	}
+
+	classification := r.URL.Query().Get("classification")
+	if classification == "" {
+		writeJSONError(w, http.StatusBadRequest, "classification is required")
+		return
+	}
+
+	limit, ok := queryInt(w, r, "limit", defaultClientsLimit, 1, maxClientsLimit)
+	if !ok {
+		return
+	}
+	offset, ok := queryInt(w, r, "offset", 0, 0, math.MaxInt32)
+	if !ok {
+		return
+	}
+
+	resp, err := h.splitSvc.ListClientPages(r.Context(), services.ListClientPagesRequest{
+		ClientID:       id,
+		Classification: classification,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ConsistencyCheckHandler handles POST requests from admins to find documents and
+// pages left behind by deletions; with repair=true they are also repaired
+func (h *SplitHandler) ConsistencyCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	token, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	subject := tokenSubject(token)
+	if !h.isAdmin(subject) {
+		writeJSONError(w, http.StatusForbidden, "consistency check requires admin")
+		return
+	}
+
+	var repair bool
+	switch r.URL.Query().Get("repair") {
+	case "", "false":
+	case "true":
+		repair = true
+	default:
+		writeJSONError(w, http.StatusBadRequest, "repair must be true or false")
+		return
+	}
+
+	resp, err := h.splitSvc.CheckConsistency(services.WithActor(r.Context(), subject), repair)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListClientSplitsHandler handles GET requests for a page of a client's splits,
+// optionally only those with the tag given in the tag query parameter
+func (h *SplitHandler) ListClientSplitsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "client ID is required")
+		return
+	}
+
+	limit, ok := queryInt(w, r, "limit", defaultClientsLimit, 1, maxClientsLimit)
+	if !ok {
+		return
+	}
+	offset, ok := queryInt(w, r, "offset", 0, 0, math.MaxInt32)
+	if !ok {
+		return
+	}
+
+	resp, err := h.splitSvc.ListClientSplits(r.Context(), services.ListClientSplitsRequest{
+		ClientID: id,
+		Tag:      r.URL.Query().Get("tag"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListSplitPagesHandler handles GET requests for every page of a split, assigned or not
+func (h *SplitHandler) ListSplitPagesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		return
+	}
+
+	// Verify the token
+	_, err := h.tokenVerifier.VerifyToken(parts[1])
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		writeJSONError(w, http.StatusBadRequest, "split ID is required")
+		return
+	}
+
+	resp, err := h.splitSvc.ListAllPages(r.Context(), id)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// queryInt reads an optional integer query parameter within [min, max].
+// On invalid input it writes a 400 response and returns false.
+func queryInt(w http.ResponseWriter, r *http.Request, name string, def, min, max int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < min || v > max {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("%s must be an integer between %d and %d", name, min, max))
+		return 0, false
+	}
+	return v, true
 }
@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
 	"accounting/internal/services"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSplitService is a mock implementation of SplitServiceInterface
@@ -21,8 +27,43 @@ type MockSplitService struct {
 	movePagesFunc              func(ctx context.Context, req services.MovePagesRequest) (*services.MovePagesResponse, error)
 	createDocumentFunc         func(ctx context.Context, req services.CreateDocumentRequest) (*services.DocumentResponse, error)
 	deleteDocumentFunc         func(ctx context.Context, documentID string) error
-	finalizeSplitFunc          func(ctx context.Context, splitID string) error
+	deleteDocumentsFunc        func(ctx context.Context, documentIDs []string) (*services.DeleteDocumentsResponse, error)
+	finalizeSplitFunc          func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error)
 	downloadDocumentFunc       func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error)
+	documentDownloadInfoFunc   func(ctx context.Context, documentID string) (*services.DocumentDownloadInfoResponse, error)
+	downloadSplitDocumentFunc  func(ctx context.Context, splitID, documentID string) (*services.DownloadDocumentResponse, error)
+	splitDocDownloadInfoFunc   func(ctx context.Context, splitID, documentID string) (*services.DocumentDownloadInfoResponse, error)
+	reorderDocumentsFunc       func(ctx context.Context, splitID string, req services.ReorderDocumentsRequest) (*services.LoadSplitResponse, error)
+	getPageContentFunc         func(ctx context.Context, pageID string) (*services.PageContentResponse, error)
+	clientStatsFunc            func(ctx context.Context, clientID string) (*services.ClientStatsResponse, error)
+	listClientsFunc            func(ctx context.Context, req services.ListClientsRequest) (*services.ListClientsResponse, error)
+	deleteSplitFunc            func(ctx context.Context, splitID string) error
+	listClientPagesFunc        func(ctx context.Context, req services.ListClientPagesRequest) (*services.ListClientPagesResponse, error)
+	listAllPagesFunc           func(ctx context.Context, splitID string) (*services.ListSplitPagesResponse, error)
+	exportSplitJSONFunc        func(ctx context.Context, splitID string) ([]byte, error)
+	exportSplitCSVFunc         func(ctx context.Context, splitID string) ([]byte, error)
+	deletePagesFunc            func(ctx context.Context, documentID string, req services.DeletePagesRequest) (*services.DocumentResponse, error)
+	reclassifyDocumentFunc     func(ctx context.Context, documentID string, classification string) (*services.DocumentResponse, error)
+	moveDocumentToSplitFunc    func(ctx context.Context, documentID, targetSplitID string) (*services.DocumentResponse, error)
+	recordImpersonationFunc    func(ctx context.Context, actor, clientID string) error
+	renameSplitFunc            func(ctx context.Context, splitID, name string) (*services.LoadSplitResponse, error)
+	addSplitTagsFunc           func(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error)
+	removeSplitTagsFunc        func(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error)
+	listClientSplitsFunc       func(ctx context.Context, req services.ListClientSplitsRequest) (*services.ListClientSplitsResponse, error)
+	checkConsistencyFunc       func(ctx context.Context, repair bool) (*services.ConsistencyCheckResponse, error)
+	swapPagesFunc              func(ctx context.Context, documentID string, req services.SwapPagesRequest) (*services.DocumentResponse, error)
+	countDocumentPagesFunc     func(ctx context.Context, documentID string) (*services.PageCountResponse, error)
+	finalizeSplitsFunc         func(ctx context.Context, splitIDs []string) (*services.FinalizeSplitsResponse, error)
+	forceDeleteSplitFunc       func(ctx context.Context, splitID string, actor string) error
+	lockSplitFunc              func(ctx context.Context, splitID string) (*services.SplitLockResponse, error)
+	unlockSplitFunc            func(ctx context.Context, splitID string) error
+	previewSplitFunc           func(ctx context.Context, splitID string) (*services.PreviewSplitResponse, error)
+	reviewDocumentFunc         func(ctx context.Context, documentID string, reviewed bool) (*services.DocumentResponse, error)
+	diffSplitsFunc             func(ctx context.Context, aID, bID string) (*services.SplitDiff, error)
+	loadSplitPartsFunc         func(ctx context.Context, req services.LoadSplitRequest) (*services.LoadSplitResponse, error)
+	streamSplitFunc            func(ctx context.Context, id string, w io.Writer) error
+	batchGetSplitsFunc         func(ctx context.Context, req services.BatchGetSplitsRequest) (*services.BatchGetSplitsResponse, error)
+	checkFinalizeSplitFunc     func(ctx context.Context, splitID string) (*services.FinalizeCheckResponse, error)
 }
 
 func (m *MockSplitService) LoadSplit(ctx context.Context, id string) (*services.LoadSplitResponse, error) {
@@ -45,7 +86,11 @@ func (m *MockSplitService) DeleteDocument(ctx context.Context, documentID string
 	return m.deleteDocumentFunc(ctx, documentID)
 }
 
-func (m *MockSplitService) FinalizeSplit(ctx context.Context, splitID string) error {
+func (m *MockSplitService) DeleteDocuments(ctx context.Context, documentIDs []string) (*services.DeleteDocumentsResponse, error) {
+	return m.deleteDocumentsFunc(ctx, documentIDs)
+}
+
+func (m *MockSplitService) FinalizeSplit(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
 	return m.finalizeSplitFunc(ctx, splitID)
 }
 
@@ -53,6 +98,142 @@ func (m *MockSplitService) DownloadDocument(ctx context.Context, documentID stri
 	return m.downloadDocumentFunc(ctx, documentID)
 }
 
+func (m *MockSplitService) DownloadSplitDocument(ctx context.Context, splitID, documentID string) (*services.DownloadDocumentResponse, error) {
+	return m.downloadSplitDocumentFunc(ctx, splitID, documentID)
+}
+
+func (m *MockSplitService) SplitDocumentDownloadInfo(ctx context.Context, splitID, documentID string) (*services.DocumentDownloadInfoResponse, error) {
+	return m.splitDocDownloadInfoFunc(ctx, splitID, documentID)
+}
+
+func (m *MockSplitService) DocumentDownloadInfo(ctx context.Context, documentID string) (*services.DocumentDownloadInfoResponse, error) {
+	return m.documentDownloadInfoFunc(ctx, documentID)
+}
+
+func (m *MockSplitService) ReorderDocuments(ctx context.Context, splitID string, req services.ReorderDocumentsRequest) (*services.LoadSplitResponse, error) {
+	return m.reorderDocumentsFunc(ctx, splitID, req)
+}
+
+func (m *MockSplitService) SwapPages(ctx context.Context, documentID string, req services.SwapPagesRequest) (*services.DocumentResponse, error) {
+	return m.swapPagesFunc(ctx, documentID, req)
+}
+
+func (m *MockSplitService) CountDocumentPages(ctx context.Context, documentID string) (*services.PageCountResponse, error) {
+	return m.countDocumentPagesFunc(ctx, documentID)
+}
+
+func (m *MockSplitService) GetPageContent(ctx context.Context, pageID string) (*services.PageContentResponse, error) {
+	return m.getPageContentFunc(ctx, pageID)
+}
+
+func (m *MockSplitService) ClientStats(ctx context.Context, clientID string) (*services.ClientStatsResponse, error) {
+	return m.clientStatsFunc(ctx, clientID)
+}
+
+func (m *MockSplitService) ListClients(ctx context.Context, req services.ListClientsRequest) (*services.ListClientsResponse, error) {
+	return m.listClientsFunc(ctx, req)
+}
+
+func (m *MockSplitService) ReclassifyDocument(ctx context.Context, documentID string, classification string) (*services.DocumentResponse, error) {
+	return m.reclassifyDocumentFunc(ctx, documentID, classification)
+}
+
+func (m *MockSplitService) MoveDocumentToSplit(ctx context.Context, documentID, targetSplitID string) (*services.DocumentResponse, error) {
+	return m.moveDocumentToSplitFunc(ctx, documentID, targetSplitID)
+}
+
+func (m *MockSplitService) RecordImpersonation(ctx context.Context, actor, clientID string) error {
+	return m.recordImpersonationFunc(ctx, actor, clientID)
+}
+
+func (m *MockSplitService) RenameSplit(ctx context.Context, splitID, name string) (*services.LoadSplitResponse, error) {
+	return m.renameSplitFunc(ctx, splitID, name)
+}
+
+func (m *MockSplitService) AddSplitTags(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error) {
+	return m.addSplitTagsFunc(ctx, splitID, tags)
+}
+
+func (m *MockSplitService) RemoveSplitTags(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error) {
+	return m.removeSplitTagsFunc(ctx, splitID, tags)
+}
+
+func (m *MockSplitService) ListClientSplits(ctx context.Context, req services.ListClientSplitsRequest) (*services.ListClientSplitsResponse, error) {
+	return m.listClientSplitsFunc(ctx, req)
+}
+
+func (m *MockSplitService) CheckConsistency(ctx context.Context, repair bool) (*services.ConsistencyCheckResponse, error) {
+	return m.checkConsistencyFunc(ctx, repair)
+}
+
+func (m *MockSplitService) FinalizeSplits(ctx context.Context, splitIDs []string) (*services.FinalizeSplitsResponse, error) {
+	return m.finalizeSplitsFunc(ctx, splitIDs)
+}
+
+func (m *MockSplitService) DeletePages(ctx context.Context, documentID string, req services.DeletePagesRequest) (*services.DocumentResponse, error) {
+	return m.deletePagesFunc(ctx, documentID, req)
+}
+
+func (m *MockSplitService) ExportSplitJSON(ctx context.Context, splitID string) ([]byte, error) {
+	return m.exportSplitJSONFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) ExportSplitCSV(ctx context.Context, splitID string) ([]byte, error) {
+	return m.exportSplitCSVFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) ListClientPages(ctx context.Context, req services.ListClientPagesRequest) (*services.ListClientPagesResponse, error) {
+	return m.listClientPagesFunc(ctx, req)
+}
+
+func (m *MockSplitService) ListAllPages(ctx context.Context, splitID string) (*services.ListSplitPagesResponse, error) {
+	return m.listAllPagesFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) DeleteSplit(ctx context.Context, splitID string) error {
+	return m.deleteSplitFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) ForceDeleteSplit(ctx context.Context, splitID string, actor string) error {
+	return m.forceDeleteSplitFunc(ctx, splitID, actor)
+}
+
+func (m *MockSplitService) LockSplit(ctx context.Context, splitID string) (*services.SplitLockResponse, error) {
+	return m.lockSplitFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) UnlockSplit(ctx context.Context, splitID string) error {
+	return m.unlockSplitFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) PreviewSplit(ctx context.Context, splitID string) (*services.PreviewSplitResponse, error) {
+	return m.previewSplitFunc(ctx, splitID)
+}
+
+func (m *MockSplitService) LoadSplitParts(ctx context.Context, req services.LoadSplitRequest) (*services.LoadSplitResponse, error) {
+	return m.loadSplitPartsFunc(ctx, req)
+}
+
+func (m *MockSplitService) StreamSplit(ctx context.Context, id string, w io.Writer) error {
+	return m.streamSplitFunc(ctx, id, w)
+}
+
+func (m *MockSplitService) BatchGetSplits(ctx context.Context, req services.BatchGetSplitsRequest) (*services.BatchGetSplitsResponse, error) {
+	return m.batchGetSplitsFunc(ctx, req)
+}
+
+func (m *MockSplitService) ReviewDocument(ctx context.Context, documentID string, reviewed bool) (*services.DocumentResponse, error) {
+	return m.reviewDocumentFunc(ctx, documentID, reviewed)
+}
+
+func (m *MockSplitService) DiffSplits(ctx context.Context, aID, bID string) (*services.SplitDiff, error) {
+	return m.diffSplitsFunc(ctx, aID, bID)
+}
+
+func (m *MockSplitService) CheckFinalizeSplit(ctx context.Context, splitID string) (*services.FinalizeCheckResponse, error) {
+	return m.checkFinalizeSplitFunc(ctx, splitID)
+}
+
 // mockVerifier is a mock implementation of TokenVerifier
 type mockVerifier struct{}
 
@@ -66,6 +247,7 @@ func TestLoadSplitHandler(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		id             string
 		mockResponse   *services.LoadSplitResponse
 		mockError      error
 		expectedStatus int
@@ -74,7 +256,8 @@ func TestLoadSplitHandler(t *testing.T) {
 		{
 			name:           "success",
 			method:         http.MethodGet,
-			path:           "/splits/123/load",
+			path:           "/splits/123",
+			id:             "123",
 			mockResponse:   &services.LoadSplitResponse{ID: "123"},
 			expectedStatus: http.StatusOK,
 			expectedBody:   &services.LoadSplitResponse{ID: "123"},
@@ -82,7 +265,8 @@ func TestLoadSplitHandler(t *testing.T) {
 		{
 			name:           "not found",
 			method:         http.MethodGet,
-			path:           "/splits/nonexistent/load",
+			path:           "/splits/nonexistent",
+			id:             "nonexistent",
 			mockError:      domain.ErrNotFound,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   map[string]interface{}{"error": "not found"},
@@ -90,14 +274,16 @@ func TestLoadSplitHandler(t *testing.T) {
 		{
 			name:           "empty id",
 			method:         http.MethodGet,
-			path:           "/splits//load",
+			path:           "/splits/",
+			id:             "",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]interface{}{"error": "split ID is required"},
 		},
 		{
 			name:           "method not allowed",
 			method:         http.MethodPost,
-			path:           "/splits/123/load",
+			path:           "/splits/123",
+			id:             "123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -115,6 +301,7 @@ func TestLoadSplitHandler(t *testing.T) {
 			}
 			handler := NewSplitHandler(mockService, &mockVerifier{})
 			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
 			req.Header.Set("Authorization", "Bearer valid-token")
 			w := httptest.NewRecorder()
 			handler.LoadSplitHandler(w, req)
@@ -139,6 +326,7 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		id             string
 		body           interface{}
 		mockResponse   *services.DocumentResponse
 		mockError      error
@@ -148,7 +336,8 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 		{
 			name:           "success",
 			method:         http.MethodPatch,
-			path:           "/documents/123/metadata",
+			path:           "/documents/123",
+			id:             "123",
 			body:           map[string]interface{}{"name": "Updated Document"},
 			mockResponse:   &services.DocumentResponse{ID: "123", Name: "Updated Document"},
 			expectedStatus: http.StatusOK,
@@ -157,7 +346,8 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 		{
 			name:           "not found",
 			method:         http.MethodPatch,
-			path:           "/documents/non-existent/metadata",
+			path:           "/documents/non-existent",
+			id:             "non-existent",
 			body:           map[string]interface{}{"name": "Updated Document"},
 			mockError:      domain.ErrNotFound,
 			expectedStatus: http.StatusNotFound,
@@ -166,7 +356,8 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 		{
 			name:           "empty id",
 			method:         http.MethodPatch,
-			path:           "/documents//metadata",
+			path:           "/documents/",
+			id:             "",
 			body:           map[string]interface{}{"name": "Updated Document"},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]interface{}{"error": "document ID is required"},
@@ -174,7 +365,8 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 		{
 			name:           "method not allowed",
 			method:         http.MethodGet,
-			path:           "/documents/123/metadata",
+			path:           "/documents/123",
+			id:             "123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -193,6 +385,7 @@ func TestUpdateDocumentMetadataHandler(t *testing.T) {
 			handler := NewSplitHandler(mockService, &mockVerifier{})
 			body, _ := json.Marshal(tt.body)
 			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBuffer(body))
+			req.SetPathValue("id", tt.id)
 			req.Header.Set("Authorization", "Bearer valid-token")
 			w := httptest.NewRecorder()
 			handler.UpdateDocumentMetadataHandler(w, req)
@@ -226,7 +419,7 @@ func TestMovePagesHandler(t *testing.T) {
 		{
 			name:   "success",
 			method: http.MethodPost,
-			path:   "/documents/123/pages/move",
+			path:   "/pages/move",
 			body: services.MovePagesRequest{
 				PageIDs:        []string{"1", "2"},
 				FromDocumentID: "123",
@@ -245,7 +438,7 @@ func TestMovePagesHandler(t *testing.T) {
 		{
 			name:   "not found",
 			method: http.MethodPost,
-			path:   "/documents/123/pages/move",
+			path:   "/pages/move",
 			body: services.MovePagesRequest{
 				PageIDs:        []string{"1", "2"},
 				FromDocumentID: "123",
@@ -258,7 +451,7 @@ func TestMovePagesHandler(t *testing.T) {
 		{
 			name:   "empty page ids",
 			method: http.MethodPost,
-			path:   "/documents/123/pages/move",
+			path:   "/pages/move",
 			body: services.MovePagesRequest{
 				PageIDs:        []string{},
 				FromDocumentID: "123",
@@ -270,7 +463,7 @@ func TestMovePagesHandler(t *testing.T) {
 		{
 			name:           "method not allowed",
 			method:         http.MethodGet,
-			path:           "/documents/123/pages/move",
+			path:           "/pages/move",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -322,7 +515,7 @@ func TestCreateDocumentHandler(t *testing.T) {
 		{
 			name:   "success",
 			method: http.MethodPost,
-			path:   "/splits/123/documents",
+			path:   "/documents",
 			body: services.CreateDocumentRequest{
 				Name:    "New Document",
 				PageIDs: []string{"1", "2"},
@@ -337,10 +530,28 @@ func TestCreateDocumentHandler(t *testing.T) {
 				Name: "New Document",
 			},
 		},
+		{
+			name:   "page range",
+			method: http.MethodPost,
+			path:   "/documents",
+			body: services.CreateDocumentRequest{
+				Name:      "New Document",
+				PageRange: "3-7",
+			},
+			mockResponse: &services.DocumentResponse{
+				ID:   "124",
+				Name: "New Document",
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: &services.DocumentResponse{
+				ID:   "124",
+				Name: "New Document",
+			},
+		},
 		{
 			name:   "empty page ids",
 			method: http.MethodPost,
-			path:   "/splits/123/documents",
+			path:   "/documents",
 			body: services.CreateDocumentRequest{
 				Name:    "New Document",
 				PageIDs: []string{},
@@ -351,7 +562,7 @@ func TestCreateDocumentHandler(t *testing.T) {
 		{
 			name:           "method not allowed",
 			method:         http.MethodGet,
-			path:           "/splits/123/documents",
+			path:           "/documents",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -379,6 +590,7 @@ func TestCreateDocumentHandler(t *testing.T) {
 				err := json.NewDecoder(w.Body).Decode(&response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedBody, &response)
+				assert.Equal(t, "/documents/"+response.ID, w.Header().Get("Location"))
 			} else {
 				var response map[string]interface{}
 				err := json.NewDecoder(w.Body).Decode(&response)
@@ -394,6 +606,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		id             string
 		mockError      error
 		expectedStatus int
 		expectedBody   interface{}
@@ -402,12 +615,14 @@ func TestDeleteDocumentHandler(t *testing.T) {
 			name:           "success",
 			method:         http.MethodDelete,
 			path:           "/documents/123",
+			id:             "123",
 			expectedStatus: http.StatusNoContent,
 		},
 		{
 			name:           "not found",
 			method:         http.MethodDelete,
 			path:           "/documents/non-existent",
+			id:             "non-existent",
 			mockError:      domain.ErrNotFound,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   map[string]interface{}{"error": "not found"},
@@ -416,6 +631,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 			name:           "empty id",
 			method:         http.MethodDelete,
 			path:           "/documents/",
+			id:             "",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]interface{}{"error": "document ID is required"},
 		},
@@ -423,6 +639,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 			name:           "method not allowed",
 			method:         http.MethodGet,
 			path:           "/documents/123",
+			id:             "123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -437,6 +654,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 			}
 			handler := NewSplitHandler(mockService, &mockVerifier{})
 			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
 			req.Header.Set("Authorization", "Bearer valid-token")
 			w := httptest.NewRecorder()
 			handler.DeleteDocumentHandler(w, req)
@@ -456,6 +674,7 @@ func TestFinalizeSplitHandler(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		id             string
 		mockError      error
 		expectedStatus int
 		expectedBody   interface{}
@@ -464,20 +683,45 @@ func TestFinalizeSplitHandler(t *testing.T) {
 			name:           "success",
 			method:         http.MethodPost,
 			path:           "/splits/123/finalize",
+			id:             "123",
 			expectedStatus: http.StatusNoContent,
 		},
 		{
 			name:           "not found",
 			method:         http.MethodPost,
 			path:           "/splits/non-existent/finalize",
+			id:             "non-existent",
 			mockError:      domain.ErrNotFound,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   map[string]interface{}{"error": "not found"},
 		},
+		{
+			name:   "blocked",
+			method: http.MethodPost,
+			path:   "/splits/123/finalize",
+			id:     "123",
+			mockError: domain.NewValidationError("cannot finalize split", &domain.FinalizeBlockers{
+				UnassignedPageIDs: []string{"page3"},
+				InvalidDocuments: []domain.DocumentIssues{
+					{DocumentID: "doc1", Issues: []error{domain.NewValidationError("document name is required", nil)}},
+				},
+				Issues: []error{domain.NewValidationError("client ID is required", nil)},
+			}),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error":               "validation: cannot finalize split: unassigned pages: page3; invalid document doc1: validation: document name is required; validation: client ID is required",
+				"unassigned_page_ids": []interface{}{"page3"},
+				"invalid_documents": []interface{}{
+					map[string]interface{}{"document_id": "doc1", "reasons": []interface{}{"validation: document name is required"}},
+				},
+				"issues": []interface{}{"validation: client ID is required"},
+			},
+		},
 		{
 			name:           "empty id",
 			method:         http.MethodPost,
 			path:           "/splits//finalize",
+			id:             "",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]interface{}{"error": "split ID is required"},
 		},
@@ -485,6 +729,7 @@ func TestFinalizeSplitHandler(t *testing.T) {
 			name:           "method not allowed",
 			method:         http.MethodGet,
 			path:           "/splits/123/finalize",
+			id:             "123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -493,12 +738,13 @@ func TestFinalizeSplitHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockSplitService{
-				finalizeSplitFunc: func(ctx context.Context, splitID string) error {
-					return tt.mockError
+				finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+					return nil, tt.mockError
 				},
 			}
 			handler := NewSplitHandler(mockService, &mockVerifier{})
 			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
 			req.Header.Set("Authorization", "Bearer valid-token")
 			w := httptest.NewRecorder()
 			handler.FinalizeSplitHandler(w, req)
@@ -518,6 +764,8 @@ func TestDownloadDocumentHandler(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		id             string
+		header         map[string]string
 		mockResponse   *services.DownloadDocumentResponse
 		mockError      error
 		expectedStatus int
@@ -526,32 +774,57 @@ func TestDownloadDocumentHandler(t *testing.T) {
 		{
 			name:   "success",
 			method: http.MethodGet,
-			path:   "/documents/123",
+			path:   "/documents/123/download",
+			id:     "123",
 			mockResponse: &services.DownloadDocumentResponse{
 				Data: []byte("PDF content"),
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   []byte("PDF content"),
 		},
+		{
+			name:   "not modified",
+			method: http.MethodGet,
+			path:   "/documents/123/download",
+			id:     "123",
+			header: map[string]string{"If-None-Match": `"abc"`},
+			mockResponse: &services.DownloadDocumentResponse{
+				Data: []byte("PDF content"),
+				ETag: `"abc"`,
+			},
+			expectedStatus: http.StatusNotModified,
+		},
 		{
 			name:           "not found",
 			method:         http.MethodGet,
-			path:           "/documents/non-existent",
+			path:           "/documents/non-existent/download",
+			id:             "non-existent",
 			mockError:      domain.ErrNotFound,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   map[string]interface{}{"error": "not found"},
 		},
+		{
+			name:           "too many renders",
+			method:         http.MethodGet,
+			path:           "/documents/123/download",
+			id:             "123",
+			mockError:      domain.NewUnavailableError("too many renders in progress, retry later", nil),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   map[string]interface{}{"error": "unavailable: too many renders in progress, retry later"},
+		},
 		{
 			name:           "empty id",
 			method:         http.MethodGet,
-			path:           "/documents/",
+			path:           "/documents//download",
+			id:             "",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]interface{}{"error": "document ID is required"},
 		},
 		{
 			name:           "method not allowed",
 			method:         http.MethodPost,
-			path:           "/documents/123",
+			path:           "/documents/123/download",
+			id:             "123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody:   map[string]interface{}{"error": "method not allowed"},
 		},
@@ -569,12 +842,19 @@ func TestDownloadDocumentHandler(t *testing.T) {
 			}
 			handler := NewSplitHandler(mockService, &mockVerifier{})
 			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
 			req.Header.Set("Authorization", "Bearer valid-token")
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
 			w := httptest.NewRecorder()
 			handler.DownloadDocumentHandler(w, req)
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedStatus == http.StatusOK {
 				assert.Equal(t, tt.expectedBody, w.Body.Bytes())
+			} else if tt.expectedStatus == http.StatusNotModified {
+				assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+				assert.Empty(t, w.Body.Bytes())
 			} else {
 				var response map[string]interface{}
 				err := json.NewDecoder(w.Body).Decode(&response)
@@ -585,60 +865,1730 @@ func TestDownloadDocumentHandler(t *testing.T) {
 	}
 }
 
-// compareMaps compares two maps recursively
-func compareMaps(a, b map[string]interface{}) bool {
-	if len(a) != len(b) {
-		return false
+func TestReorderDocumentsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		id             string
+		body           interface{}
+		mockResponse   *services.LoadSplitResponse
+		mockError      error
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:   "success",
+			method: http.MethodPost,
+			path:   "/splits/123/documents/reorder",
+			id:     "123",
+			body:   services.ReorderDocumentsRequest{DocumentIDs: []string{"doc2", "doc1"}},
+			mockResponse: &services.LoadSplitResponse{
+				ID: "123",
+				Documents: []*services.DocumentResponse{
+					{ID: "doc2", SortOrder: 1},
+					{ID: "doc1", SortOrder: 2},
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: &services.LoadSplitResponse{
+				ID: "123",
+				Documents: []*services.DocumentResponse{
+					{ID: "doc2", SortOrder: 1},
+					{ID: "doc1", SortOrder: 2},
+				},
+			},
+		},
+		{
+			name:           "not found",
+			method:         http.MethodPost,
+			path:           "/splits/non-existent/documents/reorder",
+			id:             "non-existent",
+			body:           services.ReorderDocumentsRequest{DocumentIDs: []string{"doc1"}},
+			mockError:      domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]interface{}{"error": "not found"},
+		},
+		{
+			name:           "empty document ids",
+			method:         http.MethodPost,
+			path:           "/splits/123/documents/reorder",
+			id:             "123",
+			body:           services.ReorderDocumentsRequest{DocumentIDs: []string{}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "document IDs are required"},
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodGet,
+			path:           "/splits/123/documents/reorder",
+			id:             "123",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   map[string]interface{}{"error": "method not allowed"},
+		},
 	}
-	for k, v := range a {
-		bv, exists := b[k]
-		if !exists {
-			return false
-		}
-		switch av := v.(type) {
-		case map[string]interface{}:
-			bvMap, ok := bv.(map[string]interface{})
-			if !ok {
-				return false
-			}
-			if !compareMaps(av, bvMap) {
-				return false
-			}
-		case []interface{}:
-			bvSlice, ok := bv.([]interface{})
-			if !ok {
-				return false
-			}
-			if !compareSlices(av, bvSlice) {
-				return false
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				reorderDocumentsFunc: func(ctx context.Context, splitID string, req services.ReorderDocumentsRequest) (*services.LoadSplitResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return tt.mockResponse, nil
+				},
 			}
-		default:
-			if v != bv {
-				return false
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBuffer(body))
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ReorderDocumentsHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response services.LoadSplitResponse
+				err := json.NewDecoder(w.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, &response)
+			} else {
+				var response map[string]interface{}
+				err := json.NewDecoder(w.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, response)
 			}
-		}
+		})
 	}
-	return true
 }
 
-// compareSlices compares two slices recursively
-func compareSlices(a, b []interface{}) bool {
-	if len(a) != len(b) {
-		return false
+func TestUpdateSplitHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		id             string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "success",
+			method:         http.MethodPatch,
+			id:             "123",
+			body:           `{"name": "Smith household"}`,
+			expectedStatus: http.StatusOK,
+			expectedName:   "Smith household",
+		},
+		{
+			name:           "missing name",
+			method:         http.MethodPatch,
+			id:             "123",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "name is required",
+		},
+		{
+			name:           "name too long",
+			method:         http.MethodPatch,
+			id:             "123",
+			body:           `{"name": "x"}`,
+			mockError:      domain.NewValidationError("split name must be at most 200 characters", nil),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "validation: split name must be at most 200 characters",
+		},
+		{
+			name:           "not found",
+			method:         http.MethodPatch,
+			id:             "non-existent",
+			body:           `{"name": "x"}`,
+			mockError:      domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "not found",
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodPut,
+			id:             "123",
+			body:           `{"name": "x"}`,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedError:  "method not allowed",
+		},
 	}
-	for i := range a {
-		switch av := a[i].(type) {
-		case map[string]interface{}:
-			bv, ok := b[i].(map[string]interface{})
-			if !ok {
-				return false
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				renameSplitFunc: func(ctx context.Context, splitID, name string) (*services.LoadSplitResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.LoadSplitResponse{ID: splitID, Name: name}, nil
+				},
 			}
-			if !compareMaps(av, bv) {
-				return false
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(tt.method, "/splits/"+tt.id, strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.UpdateSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.id, response["id"])
+				assert.Equal(t, tt.expectedName, response["name"])
+			} else {
+				assert.Equal(t, tt.expectedError, response["error"])
 			}
-		case []interface{}:
-			bv, ok := b[i].([]interface{})
-			if !ok {
+		})
+	}
+}
+
+func TestPageContentHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		id             string
+		mockResponse   *services.PageContentResponse
+		mockError      error
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:   "success",
+			method: http.MethodGet,
+			path:   "/pages/123/content",
+			id:     "123",
+			mockResponse: &services.PageContentResponse{
+				ContentType: "image/png",
+				Content:     io.NopCloser(strings.NewReader("png data")),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []byte("png data"),
+		},
+		{
+			name:           "not found",
+			method:         http.MethodGet,
+			path:           "/pages/non-existent/content",
+			id:             "non-existent",
+			mockError:      domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]interface{}{"error": "not found"},
+		},
+		{
+			name:           "blob unavailable",
+			method:         http.MethodGet,
+			path:           "/pages/123/content",
+			id:             "123",
+			mockError:      fmt.Errorf("%w: connection refused", services.ErrBlobUnavailable),
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   map[string]interface{}{"error": "page content unavailable"},
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodPost,
+			path:           "/pages/123/content",
+			id:             "123",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   map[string]interface{}{"error": "method not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				getPageContentFunc: func(ctx context.Context, pageID string) (*services.PageContentResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return tt.mockResponse, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.PageContentHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedBody, w.Body.Bytes())
+			} else {
+				var response map[string]interface{}
+				err := json.NewDecoder(w.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestClientStatsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		id             string
+		mockError      error
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "success",
+			method:         http.MethodGet,
+			id:             "client1",
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"client_id":        "client1",
+				"total_splits":     float64(2),
+				"draft_splits":     float64(1),
+				"finalized_splits": float64(1),
+				"total_documents":  float64(3),
+				"total_pages":      float64(7),
+			},
+		},
+		{
+			name:           "service error",
+			method:         http.MethodGet,
+			id:             "client1",
+			mockError:      fmt.Errorf("database unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]interface{}{"error": "database unavailable"},
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodPost,
+			id:             "client1",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   map[string]interface{}{"error": "method not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				clientStatsFunc: func(ctx context.Context, clientID string) (*services.ClientStatsResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.ClientStatsResponse{
+						ClientID:        clientID,
+						TotalSplits:     2,
+						DraftSplits:     1,
+						FinalizedSplits: 1,
+						TotalDocuments:  3,
+						TotalPages:      7,
+					}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(tt.method, "/clients/"+tt.id+"/stats", nil)
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ClientStatsHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestListClientsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		subject        string
+		query          string
+		clients        []*services.ClientSummaryResponse
+		expectedReq    services.ListClientsRequest
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "default page",
+			subject:        "admin",
+			clients:        []*services.ClientSummaryResponse{{ClientID: "client1", SplitCount: 2}},
+			expectedReq:    services.ListClientsRequest{Limit: 100, Offset: 0},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"clients": []interface{}{map[string]interface{}{"client_id": "client1", "split_count": float64(2)}},
+				"limit":   float64(100),
+				"offset":  float64(0),
+			},
+		},
+		{
+			name:           "empty list",
+			subject:        "admin",
+			query:          "?limit=10&offset=20",
+			clients:        []*services.ClientSummaryResponse{},
+			expectedReq:    services.ListClientsRequest{Limit: 10, Offset: 20},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"clients": []interface{}{},
+				"limit":   float64(10),
+				"offset":  float64(20),
+			},
+		},
+		{
+			name:           "limit too large",
+			subject:        "admin",
+			query:          "?limit=5000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "limit must be an integer between 1 and 1000"},
+		},
+		{
+			name:           "invalid offset",
+			subject:        "admin",
+			query:          "?offset=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "offset must be an integer between 0 and 2147483647"},
+		},
+		{
+			name:           "non-admin",
+			subject:        "alice",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]interface{}{"error": "listing clients requires admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				listClientsFunc: func(ctx context.Context, req services.ListClientsRequest) (*services.ListClientsResponse, error) {
+					assert.Equal(t, tt.expectedReq, req)
+					return &services.ListClientsResponse{Clients: tt.clients, Limit: req.Limit, Offset: req.Offset}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &subjectVerifier{subject: tt.subject})
+			handler.SetAdmins([]string{"admin"})
+			req := httptest.NewRequest(http.MethodGet, "/clients"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ListClientsHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestReclassifyDocumentHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockResponse   *services.DocumentResponse
+		mockError      error
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "successful reclassify",
+			body:           `{"classification":"W-2"}`,
+			mockResponse:   &services.DocumentResponse{ID: "123", Classification: "W-2"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing classification",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "classification is required"},
+		},
+		{
+			name:           "classification not allowed",
+			body:           `{"classification":"Other"}`,
+			mockError:      domain.NewValidationError(`classification "Other" is not allowed`, nil),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				reclassifyDocumentFunc: func(ctx context.Context, documentID string, classification string) (*services.DocumentResponse, error) {
+					assert.Equal(t, "123", documentID)
+					return tt.mockResponse, tt.mockError
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodPost, "/documents/123/reclassify", strings.NewReader(tt.body))
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ReclassifyDocumentHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				var response map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.expectedBody, response)
+			}
+			if tt.mockResponse != nil {
+				var response services.DocumentResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "W-2", response.Classification)
+			}
+		})
+	}
+}
+
+func TestDeletePagesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "successful delete",
+			body:           `{"page_ids":["p1","p2"]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing page IDs",
+			body:           `{"page_ids":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "page_ids is required"},
+		},
+		{
+			name:           "finalized split",
+			body:           `{"page_ids":["p1"]}`,
+			mockError:      domain.NewConflictError("cannot delete pages in finalized split", nil),
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				deletePagesFunc: func(ctx context.Context, documentID string, req services.DeletePagesRequest) (*services.DocumentResponse, error) {
+					assert.Equal(t, "123", documentID)
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.DocumentResponse{ID: documentID}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodDelete, "/documents/123/pages", strings.NewReader(tt.body))
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.DeletePagesHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				var response map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestExportSplitJSONHandler(t *testing.T) {
+	body := `{"split_id":"123","client_id":"c1","status":"draft","documents":[]}`
+	mockService := &MockSplitService{
+		exportSplitJSONFunc: func(ctx context.Context, splitID string) ([]byte, error) {
+			if splitID != "123" {
+				return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+			}
+			return []byte(body), nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodGet, "/splits/123/export.json", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.ExportSplitJSONHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, body, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/splits/456/export.json", nil)
+	req.SetPathValue("id", "456")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	handler.ExportSplitJSONHandler(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExportSplitCSVHandler(t *testing.T) {
+	body := "name,classification,page_range,page_count\r\nTotal,,,0\r\n"
+	mockService := &MockSplitService{
+		exportSplitCSVFunc: func(ctx context.Context, splitID string) ([]byte, error) {
+			if splitID != "123" {
+				return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+			}
+			return []byte(body), nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodGet, "/splits/123/export.csv", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.ExportSplitCSVHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="123.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, body, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/splits/456/export.csv", nil)
+	req.SetPathValue("id", "456")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	handler.ExportSplitCSVHandler(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListClientPagesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedReq    *services.ListClientPagesRequest
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "defaults",
+			query:          "?classification=1099",
+			expectedStatus: http.StatusOK,
+			expectedReq:    &services.ListClientPagesRequest{ClientID: "c1", Classification: "1099", Limit: defaultClientsLimit},
+		},
+		{
+			name:           "explicit page",
+			query:          "?classification=W-2&limit=10&offset=20",
+			expectedStatus: http.StatusOK,
+			expectedReq:    &services.ListClientPagesRequest{ClientID: "c1", Classification: "W-2", Limit: 10, Offset: 20},
+		},
+		{
+			name:           "missing classification",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "classification is required"},
+		},
+		{
+			name:           "limit too large",
+			query:          "?classification=1099&limit=5000",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *services.ListClientPagesRequest
+			mockService := &MockSplitService{
+				listClientPagesFunc: func(ctx context.Context, req services.ListClientPagesRequest) (*services.ListClientPagesResponse, error) {
+					got = &req
+					return &services.ListClientPagesResponse{ClientID: req.ClientID, Pages: []*services.ClientPageResponse{}}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodGet, "/clients/c1/pages"+tt.query, nil)
+			req.SetPathValue("id", "c1")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ListClientPagesHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedReq, got)
+			if tt.expectedBody != nil {
+				var response map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestListSplitPagesHandler(t *testing.T) {
+	docID := "doc1"
+	mockService := &MockSplitService{
+		listAllPagesFunc: func(ctx context.Context, splitID string) (*services.ListSplitPagesResponse, error) {
+			if splitID != "s1" {
+				return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+			}
+			return &services.ListSplitPagesResponse{
+				SplitID: splitID,
+				Pages: []*services.SplitPageResponse{
+					{ID: "p1", PageNumber: "1", URL: "page_1.png", DocumentID: &docID},
+					{ID: "p2", PageNumber: "2", URL: "page_2.png"},
+				},
+				AssignedCount:   1,
+				UnassignedCount: 1,
+			}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /splits/{id}/pages", handler.ListSplitPagesHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/splits/s1/pages", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	pages := response["pages"].([]interface{})
+	require.Len(t, pages, 2)
+	assert.Equal(t, "doc1", pages[0].(map[string]interface{})["document_id"])
+	assert.Nil(t, pages[1].(map[string]interface{})["document_id"])
+	assert.Contains(t, pages[1].(map[string]interface{}), "document_id")
+
+	req = httptest.NewRequest(http.MethodGet, "/splits/missing/pages", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// subjectVerifier is a TokenVerifier returning a token with the given subject
+type subjectVerifier struct {
+	subject string
+}
+
+type subjectTokenStub struct {
+	subject string
+}
+
+func (t subjectTokenStub) Subject() (string, bool) {
+	return t.subject, t.subject != ""
+}
+
+func (v *subjectVerifier) VerifyToken(token string) (any, error) {
+	return subjectTokenStub{subject: v.subject}, nil
+}
+
+func TestDeleteSplitHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		subject        string
+		mockError      error
+		expectedStatus int
+		expectedCall   string
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "delete draft split",
+			subject:        "user",
+			expectedStatus: http.StatusNoContent,
+			expectedCall:   "delete",
+		},
+		{
+			name:           "finalized split refused",
+			subject:        "admin",
+			mockError:      domain.NewConflictError("cannot delete finalized split", nil),
+			expectedStatus: http.StatusConflict,
+			expectedCall:   "delete",
+			expectedBody:   map[string]interface{}{"error": "conflict: cannot delete finalized split"},
+		},
+		{
+			name:           "force delete as admin",
+			query:          "?force=true",
+			subject:        "admin",
+			expectedStatus: http.StatusNoContent,
+			expectedCall:   "force:admin",
+		},
+		{
+			name:           "force delete as non-admin",
+			query:          "?force=true",
+			subject:        "user",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]interface{}{"error": "force delete requires admin"},
+		},
+		{
+			name:           "invalid force value",
+			query:          "?force=yes",
+			subject:        "admin",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "force must be true or false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var call string
+			mockService := &MockSplitService{
+				deleteSplitFunc: func(ctx context.Context, splitID string) error {
+					call = "delete"
+					return tt.mockError
+				},
+				forceDeleteSplitFunc: func(ctx context.Context, splitID string, actor string) error {
+					call = "force:" + actor
+					return tt.mockError
+				},
+			}
+			handler := NewSplitHandler(mockService, &subjectVerifier{subject: tt.subject})
+			handler.SetAdmins([]string{"admin"})
+			req := httptest.NewRequest(http.MethodDelete, "/splits/123"+tt.query, nil)
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.DeleteSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCall, call)
+			if tt.expectedBody != nil {
+				var response map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestDocumentPageCountHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "success", expectedStatus: http.StatusOK, expectedBody: `{"count":3}`},
+		{
+			name:           "unknown document",
+			mockError:      domain.NewNotFoundError("document", "doc1", "document doc1 not found", nil),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				countDocumentPagesFunc: func(ctx context.Context, documentID string) (*services.PageCountResponse, error) {
+					assert.Equal(t, "doc1", documentID)
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.PageCountResponse{Count: 3}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodGet, "/documents/doc1/pages/count", nil)
+			req.SetPathValue("id", "doc1")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.DocumentPageCountHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSwapPagesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", body: `{"page_a": "page1", "page_b": "page2"}`, expectedStatus: http.StatusOK},
+		{name: "missing page", body: `{"page_a": "page1"}`, expectedStatus: http.StatusBadRequest},
+		{name: "page not in document", body: `{"page_a": "page1", "page_b": "other"}`, mockError: domain.NewValidationError("page other is not part of document doc1", nil), expectedStatus: http.StatusBadRequest},
+		{name: "finalized split", body: `{"page_a": "page1", "page_b": "page2"}`, mockError: domain.NewSplitFinalizedError("cannot swap pages in finalized split"), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got services.SwapPagesRequest
+			mockService := &MockSplitService{
+				swapPagesFunc: func(ctx context.Context, documentID string, req services.SwapPagesRequest) (*services.DocumentResponse, error) {
+					assert.Equal(t, "doc1", documentID)
+					got = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.DocumentResponse{ID: documentID, Pages: []*services.PageResponse{{ID: "page2"}, {ID: "page1"}}}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &mockVerifier{})
+			req := httptest.NewRequest(http.MethodPost, "/documents/doc1/pages/swap", strings.NewReader(tt.body))
+			req.SetPathValue("id", "doc1")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.SwapPagesHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, services.SwapPagesRequest{PageA: "page1", PageB: "page2"}, got)
+				var resp services.DocumentResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "page2", resp.Pages[0].ID)
+			}
+		})
+	}
+}
+
+// ingestFunc implements ports.SplitIngestionService for testing
+type ingestFunc func(ctx context.Context, req ports.IngestSplitRequest) (*ports.IngestSplitResponse, error)
+
+func (f ingestFunc) IngestSplit(ctx context.Context, req ports.IngestSplitRequest) (*ports.IngestSplitResponse, error) {
+	return f(ctx, req)
+}
+
+func TestIngestSplitHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		mockError      error
+		noService      bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "success", contentType: "multipart/form-data; boundary=xyz", expectedStatus: http.StatusCreated, expectedBody: `{"split_id":"split-1"}`},
+		{name: "not multipart", contentType: "application/json", expectedStatus: http.StatusUnsupportedMediaType, expectedBody: `{"error":"content type must be multipart/form-data"}`},
+		{name: "missing boundary", contentType: "multipart/form-data", expectedStatus: http.StatusUnsupportedMediaType, expectedBody: `{"error":"content type must be multipart/form-data"}`},
+		{
+			name:           "malformed upload",
+			contentType:    "multipart/form-data; boundary=xyz",
+			mockError:      domain.NewValidationError("malformed upload: unexpected EOF", nil),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"validation: malformed upload: unexpected EOF"}`,
+		},
+		{
+			name:           "upload too large",
+			contentType:    "multipart/form-data; boundary=xyz",
+			mockError:      domain.NewValidationError("malformed upload", &http.MaxBytesError{Limit: maxIngestBytes}),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{name: "ingestion disabled", contentType: "multipart/form-data; boundary=xyz", noService: true, expectedStatus: http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ports.IngestSplitRequest
+			handler := NewSplitHandler(&MockSplitService{}, &mockVerifier{})
+			if !tt.noService {
+				handler.SetIngestionService(ingestFunc(func(ctx context.Context, req ports.IngestSplitRequest) (*ports.IngestSplitResponse, error) {
+					got = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &ports.IngestSplitResponse{SplitID: "split-1"}, nil
+				}))
+			}
+			req := httptest.NewRequest(http.MethodPost, "/splits/ingest?client_id=client-1", strings.NewReader("--xyz--"))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			handler.IngestSplitHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusCreated {
+				assert.Equal(t, "client-1", got.ClientID)
+				assert.Equal(t, "xyz", got.Boundary)
+				data, err := io.ReadAll(got.File)
+				require.NoError(t, err)
+				assert.Equal(t, "--xyz--", string(data))
+				assert.Equal(t, "/splits/split-1", w.Header().Get("Location"))
+			} else {
+				assert.Empty(t, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestConsistencyCheckHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		subject        string
+		expectedStatus int
+		expectedCall   string
+	}{
+		{name: "report only", subject: "admin", expectedStatus: http.StatusOK, expectedCall: "check:admin"},
+		{name: "repair", query: "?repair=true", subject: "admin", expectedStatus: http.StatusOK, expectedCall: "repair:admin"},
+		{name: "non-admin", query: "?repair=true", subject: "user", expectedStatus: http.StatusForbidden},
+		{name: "invalid repair value", query: "?repair=yes", subject: "admin", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var call string
+			mockService := &MockSplitService{
+				checkConsistencyFunc: func(ctx context.Context, repair bool) (*services.ConsistencyCheckResponse, error) {
+					call = "check:" + services.ActorFromContext(ctx)
+					if repair {
+						call = "repair:" + services.ActorFromContext(ctx)
+					}
+					return &services.ConsistencyCheckResponse{OrphanedPageIDs: []string{"page-1"}, IssuesFound: 1}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &subjectVerifier{subject: tt.subject})
+			handler.SetAdmins([]string{"admin"})
+			req := httptest.NewRequest(http.MethodPost, "/admin/consistency-check"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.ConsistencyCheckHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCall, call)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"issues_found":1`)
+			}
+		})
+	}
+}
+
+func TestDownloadDocumentHandlerRange(t *testing.T) {
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockService := &MockSplitService{
+		downloadDocumentFunc: func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error) {
+			return &services.DownloadDocumentResponse{Data: data, Filename: "w2.pdf", ETag: `"abc"`, ModifiedAt: modified}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	req := httptest.NewRequest(http.MethodGet, "/documents/123/download", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	req.Header.Set("Range", "bytes=0-99")
+	w := httptest.NewRecorder()
+	handler.DownloadDocumentHandler(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, data[:100], w.Body.Bytes())
+	assert.Equal(t, "bytes 0-99/300", w.Header().Get("Content-Range"))
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, modified.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+}
+
+func TestPreviewSplitHandler(t *testing.T) {
+	mockService := &MockSplitService{
+		previewSplitFunc: func(ctx context.Context, splitID string) (*services.PreviewSplitResponse, error) {
+			if splitID != "123" {
+				return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+			}
+			return &services.PreviewSplitResponse{Filename: "split-123-preview.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4")}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodGet, "/splits/123/preview", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.PreviewSplitHandler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="split-123-preview.pdf"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "%PDF-1.4", w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/splits/456/preview", nil)
+	req.SetPathValue("id", "456")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	handler.PreviewSplitHandler(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoadSplitHandlerInclude(t *testing.T) {
+	var gotReq *services.LoadSplitRequest
+	mockService := &MockSplitService{
+		loadSplitFunc: func(ctx context.Context, id string) (*services.LoadSplitResponse, error) {
+			gotReq = nil
+			return &services.LoadSplitResponse{ID: id}, nil
+		},
+		loadSplitPartsFunc: func(ctx context.Context, req services.LoadSplitRequest) (*services.LoadSplitResponse, error) {
+			gotReq = &req
+			return &services.LoadSplitResponse{ID: req.ID}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	tests := []struct {
+		include        string
+		expectedStatus int
+		expectedReq    *services.LoadSplitRequest
+	}{
+		{include: "", expectedStatus: http.StatusOK},
+		{include: "documents", expectedStatus: http.StatusOK, expectedReq: &services.LoadSplitRequest{ID: "123", IncludeDocuments: true}},
+		{include: "unassigned", expectedStatus: http.StatusOK, expectedReq: &services.LoadSplitRequest{ID: "123", IncludeUnassigned: true}},
+		{include: "documents,unassigned", expectedStatus: http.StatusOK, expectedReq: &services.LoadSplitRequest{ID: "123", IncludeDocuments: true, IncludeUnassigned: true}},
+		{include: "pages", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run("include="+tt.include, func(t *testing.T) {
+			gotReq = nil
+			req := httptest.NewRequest(http.MethodGet, "/splits/123?include="+tt.include, nil)
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.LoadSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedReq, gotReq)
+		})
+	}
+}
+
+func TestMoveDocumentToSplitHandler(t *testing.T) {
+	var gotDoc, gotSplit string
+	mockService := &MockSplitService{
+		moveDocumentToSplitFunc: func(ctx context.Context, documentID, targetSplitID string) (*services.DocumentResponse, error) {
+			gotDoc, gotSplit = documentID, targetSplitID
+			if targetSplitID == "finalized" {
+				return nil, domain.NewConflictError("cannot move document into finalized split", nil)
+			}
+			return &services.DocumentResponse{ID: documentID, SplitID: targetSplitID}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "moved", body: `{"split_id":"split2"}`, expectedStatus: http.StatusOK},
+		{name: "finalized target", body: `{"split_id":"finalized"}`, expectedStatus: http.StatusConflict},
+		{name: "missing split ID", body: `{}`, expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDoc, gotSplit = "", ""
+			req := httptest.NewRequest(http.MethodPost, "/documents/doc1/move-to-split", strings.NewReader(tt.body))
+			req.SetPathValue("id", "doc1")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.MoveDocumentToSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusBadRequest {
+				assert.Equal(t, "doc1", gotDoc)
+				assert.NotEmpty(t, gotSplit)
+			}
+		})
+	}
+}
+
+func TestLoadSplitHandlerStream(t *testing.T) {
+	mockService := &MockSplitService{
+		streamSplitFunc: func(ctx context.Context, id string, w io.Writer) error {
+			if id == "missing" {
+				return domain.NewNotFoundError("split", id, "split not found", nil)
+			}
+			_, err := io.WriteString(w, `{"documents":[],"id":"`+id+`"}`)
+			return err
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	tests := []struct {
+		name           string
+		id             string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "streamed", id: "123", query: "stream=true", expectedStatus: http.StatusOK, expectedBody: `{"documents":[],"id":"123"}`},
+		{name: "not found", id: "missing", query: "stream=true", expectedStatus: http.StatusNotFound},
+		{name: "with include", id: "123", query: "stream=true&include=documents", expectedStatus: http.StatusBadRequest},
+		{name: "invalid value", id: "123", query: "stream=yes", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/splits/"+tt.id+"?"+tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.LoadSplitHandler(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBatchGetSplitsHandler(t *testing.T) {
+	var gotIDs []string
+	mockService := &MockSplitService{
+		batchGetSplitsFunc: func(ctx context.Context, req services.BatchGetSplitsRequest) (*services.BatchGetSplitsResponse, error) {
+			gotIDs = req.IDs
+			return &services.BatchGetSplitsResponse{
+				Splits:  []*services.LoadSplitResponse{{ID: "split1"}},
+				Missing: []string{"missing"},
+			}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodPost, "/splits/batch-get", strings.NewReader(`{"ids":["split1","missing"]}`))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.BatchGetSplitsHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"split1", "missing"}, gotIDs)
+	var resp services.BatchGetSplitsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Splits, 1)
+	assert.Equal(t, "split1", resp.Splits[0].ID)
+	assert.Equal(t, []string{"missing"}, resp.Missing)
+
+	// An empty ID list is rejected before reaching the service
+	req = httptest.NewRequest(http.MethodPost, "/splits/batch-get", strings.NewReader(`{"ids":[]}`))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	handler.BatchGetSplitsHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFinalizeSplitsHandler(t *testing.T) {
+	var gotIDs []string
+	var gotActor string
+	mockService := &MockSplitService{
+		finalizeSplitsFunc: func(ctx context.Context, splitIDs []string) (*services.FinalizeSplitsResponse, error) {
+			gotIDs = splitIDs
+			gotActor = services.ActorFromContext(ctx)
+			return &services.FinalizeSplitsResponse{
+				Results: []*services.FinalizeSplitResult{
+					{SplitID: "split1", Outcome: services.FinalizeOutcomeFinalized},
+					{SplitID: "split2", Outcome: services.FinalizeOutcomeFailed, Error: "validation: cannot finalize split: unassigned pages: page3"},
+				},
+				Finalized: 1,
+				Failed:    1,
+			}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/splits/finalize-batch", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		handler.FinalizeSplitsHandler(w, req)
+		return w
+	}
+
+	w := post(`{"split_ids":["split1","split2"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"split1", "split2"}, gotIDs)
+	assert.Equal(t, "alice", gotActor)
+	var resp services.FinalizeSplitsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, services.FinalizeOutcomeFailed, resp.Results[1].Outcome)
+	assert.Equal(t, 1, resp.Failed)
+
+	// An empty ID list is rejected before reaching the service
+	w = post(`{"split_ids":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteDocumentsHandler(t *testing.T) {
+	var gotIDs []string
+	var gotActor string
+	mockService := &MockSplitService{
+		deleteDocumentsFunc: func(ctx context.Context, documentIDs []string) (*services.DeleteDocumentsResponse, error) {
+			gotIDs = documentIDs
+			gotActor = services.ActorFromContext(ctx)
+			return &services.DeleteDocumentsResponse{
+				Results: []*services.DeleteDocumentResult{
+					{DocumentID: "doc1", Outcome: services.DeleteOutcomeDeleted},
+					{DocumentID: "doc2", Outcome: services.DeleteOutcomeFailed, Error: "not_found: document not found"},
+				},
+				Deleted: 1,
+				Failed:  1,
+			}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/documents/batch-delete", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		handler.DeleteDocumentsHandler(w, req)
+		return w
+	}
+
+	w := post(`{"document_ids":["doc1","doc2"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"doc1", "doc2"}, gotIDs)
+	assert.Equal(t, "alice", gotActor)
+	var resp services.DeleteDocumentsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, services.DeleteOutcomeFailed, resp.Results[1].Outcome)
+	assert.Equal(t, 1, resp.Deleted)
+
+	// An empty ID list is rejected before reaching the service
+	w = post(`{"document_ids":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDiffSplitsHandler(t *testing.T) {
+	mockService := &MockSplitService{
+		diffSplitsFunc: func(ctx context.Context, aID, bID string) (*services.SplitDiff, error) {
+			if bID == "missing" {
+				return nil, domain.NewNotFoundError("split", bID, "split not found", nil)
+			}
+			return &services.SplitDiff{SplitA: aID, SplitB: bID, PagesAdded: []string{"page_3.png"}}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	get := func(a, b string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/splits/"+a+"/diff/"+b, nil)
+		req.SetPathValue("id", a)
+		req.SetPathValue("other", b)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		handler.DiffSplitsHandler(w, req)
+		return w
+	}
+
+	w := get("ai", "corrected")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var diff services.SplitDiff
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&diff))
+	assert.Equal(t, "ai", diff.SplitA)
+	assert.Equal(t, "corrected", diff.SplitB)
+	assert.Equal(t, []string{"page_3.png"}, diff.PagesAdded)
+
+	assert.Equal(t, http.StatusNotFound, get("ai", "missing").Code)
+	assert.Equal(t, http.StatusBadRequest, get("ai", "").Code)
+}
+
+func TestReviewDocumentHandlers(t *testing.T) {
+	var gotActor string
+	mockService := &MockSplitService{
+		reviewDocumentFunc: func(ctx context.Context, documentID string, reviewed bool) (*services.DocumentResponse, error) {
+			gotActor = services.ActorFromContext(ctx)
+			resp := &services.DocumentResponse{ID: documentID, Reviewed: reviewed}
+			if reviewed {
+				resp.ReviewedBy = gotActor
+			}
+			return resp, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+
+	serve := func(method string, h http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/documents/doc1/review", nil)
+		req.SetPathValue("id", "doc1")
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, handler.ReviewDocumentHandler)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", gotActor)
+	var doc services.DocumentResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&doc))
+	assert.True(t, doc.Reviewed)
+	assert.Equal(t, "alice", doc.ReviewedBy)
+
+	w = serve(http.MethodDelete, handler.UnreviewDocumentHandler)
+	assert.Equal(t, http.StatusOK, w.Code)
+	doc = services.DocumentResponse{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&doc))
+	assert.False(t, doc.Reviewed)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, handler.ReviewDocumentHandler).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, handler.UnreviewDocumentHandler).Code)
+}
+
+func TestFinalizeSplitHandlerRetry(t *testing.T) {
+	finalizedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockService := &MockSplitService{
+		finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+			return &services.FinalizeSplitResponse{SplitID: splitID, FinalizedAt: &finalizedAt, AlreadyFinalized: true}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodPost, "/splits/123/finalize", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.FinalizeSplitHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp services.FinalizeSplitResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "123", resp.SplitID)
+	assert.True(t, resp.AlreadyFinalized)
+	require.NotNil(t, resp.FinalizedAt)
+	assert.True(t, finalizedAt.Equal(*resp.FinalizedAt))
+}
+
+func TestFinalizeSplitHandlerDryRun(t *testing.T) {
+	finalized := false
+	mockService := &MockSplitService{
+		finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+			finalized = true
+			return &services.FinalizeSplitResponse{SplitID: splitID}, nil
+		},
+		checkFinalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeCheckResponse, error) {
+			return &services.FinalizeCheckResponse{SplitID: splitID, Issues: []string{"first", "second"}}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+
+	req := httptest.NewRequest(http.MethodPost, "/splits/123/finalize?dry_run=true", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.FinalizeSplitHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, finalized)
+	var response map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, map[string]interface{}{
+		"split_id": "123",
+		"ready":    false,
+		"issues":   []interface{}{"first", "second"},
+	}, response)
+
+	req = httptest.NewRequest(http.MethodPost, "/splits/123/finalize?dry_run=maybe", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	handler.FinalizeSplitHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, finalized)
+}
+
+func TestLockSplitHandler(t *testing.T) {
+	expires := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "lock acquired",
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"split_id":   "123",
+				"holder":     "alice",
+				"expires_at": "2025-01-02T03:04:05Z",
+			},
+		},
+		{
+			name:           "held by someone else",
+			mockError:      domain.NewLockedError("split is locked by bob", nil),
+			expectedStatus: http.StatusLocked,
+			expectedBody:   map[string]interface{}{"error": "locked: split is locked by bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSplitService{
+				lockSplitFunc: func(ctx context.Context, splitID string) (*services.SplitLockResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &services.SplitLockResponse{SplitID: splitID, Holder: services.ActorFromContext(ctx), ExpiresAt: expires}, nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+			req := httptest.NewRequest(http.MethodPost, "/splits/123/lock", nil)
+			req.SetPathValue("id", "123")
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			handler.LockSplitHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
+
+func TestUnlockSplitHandler(t *testing.T) {
+	var gotActor string
+	mockService := &MockSplitService{
+		unlockSplitFunc: func(ctx context.Context, splitID string) error {
+			gotActor = services.ActorFromContext(ctx)
+			return nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+	req := httptest.NewRequest(http.MethodDelete, "/splits/123/lock", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.UnlockSplitHandler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "alice", gotActor)
+}
+
+func TestMutatingHandlersPassTokenSubjectAsActor(t *testing.T) {
+	var gotActor string
+	mockService := &MockSplitService{
+		finalizeSplitFunc: func(ctx context.Context, splitID string) (*services.FinalizeSplitResponse, error) {
+			gotActor = services.ActorFromContext(ctx)
+			return nil, domain.NewLockedError("split is locked by bob", nil)
+		},
+	}
+	handler := NewSplitHandler(mockService, &subjectVerifier{subject: "alice"})
+	req := httptest.NewRequest(http.MethodPost, "/splits/123/finalize", nil)
+	req.SetPathValue("id", "123")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	handler.FinalizeSplitHandler(w, req)
+
+	assert.Equal(t, http.StatusLocked, w.Code)
+	assert.Equal(t, "alice", gotActor)
+}
+
+func TestDownloadDocumentHandler_Head(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, estimated := range []bool{false, true} {
+		mockService := &MockSplitService{
+			documentDownloadInfoFunc: func(ctx context.Context, documentID string) (*services.DocumentDownloadInfoResponse, error) {
+				return &services.DocumentDownloadInfoResponse{
+					DocumentID:    documentID,
+					Filename:      "w2.pdf",
+					ContentType:   "application/pdf",
+					PageCount:     2,
+					Size:          2048,
+					SizeEstimated: estimated,
+					ETag:          `"abc"`,
+					ModifiedAt:    modified,
+				}, nil
+			},
+			downloadDocumentFunc: func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error) {
+				t.Fatal("HEAD must not render the document")
+				return nil, nil
+			},
+		}
+		handler := NewSplitHandler(mockService, &mockVerifier{})
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /documents/{id}/download", handler.DownloadDocumentHandler)
+
+		req := httptest.NewRequest(http.MethodHead, "/documents/doc-42/download", nil)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="w2.pdf"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+		assert.Equal(t, "Fri, 01 Mar 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+		if estimated {
+			assert.Empty(t, w.Header().Get("Content-Length"))
+			assert.Equal(t, "2048", w.Header().Get(EstimatedContentLengthHeader))
+		} else {
+			assert.Equal(t, "2048", w.Header().Get("Content-Length"))
+			assert.Empty(t, w.Header().Get(EstimatedContentLengthHeader))
+		}
+	}
+}
+
+func TestDownloadSplitDocumentHandler(t *testing.T) {
+	var gotSplitID, gotDocID string
+	mockService := &MockSplitService{
+		downloadSplitDocumentFunc: func(ctx context.Context, splitID, documentID string) (*services.DownloadDocumentResponse, error) {
+			gotSplitID, gotDocID = splitID, documentID
+			if documentID != "doc-42" {
+				return nil, domain.NewNotFoundError("document", documentID, "document not found", nil)
+			}
+			return &services.DownloadDocumentResponse{Data: []byte("PDF content"), Filename: "w2.pdf", ETag: `"abc"`}, nil
+		},
+		downloadDocumentFunc: func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error) {
+			t.Fatal("the nested route must not look the split up")
+			return nil, nil
+		},
+		splitDocDownloadInfoFunc: func(ctx context.Context, splitID, documentID string) (*services.DocumentDownloadInfoResponse, error) {
+			return &services.DocumentDownloadInfoResponse{DocumentID: documentID, Filename: "w2.pdf", ContentType: "application/pdf", Size: 11, ETag: `"abc"`}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /splits/{id}/documents/{docId}/download", handler.DownloadSplitDocumentHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/splits/split-7/documents/doc-42/download", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "split-7", gotSplitID)
+	assert.Equal(t, "doc-42", gotDocID)
+	assert.Equal(t, "PDF content", w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="w2.pdf"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+
+	// HEAD answers from the download info
+	req = httptest.NewRequest(http.MethodHead, "/splits/split-7/documents/doc-42/download", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "11", w.Header().Get("Content-Length"))
+
+	// A document outside the split is not found
+	req = httptest.NewRequest(http.MethodGet, "/splits/split-7/documents/doc-other/download", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Unauthenticated requests are rejected
+	req = httptest.NewRequest(http.MethodGet, "/splits/split-7/documents/doc-42/download", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSplitTagsHandlers(t *testing.T) {
+	var added, removed []string
+	var listed services.ListClientSplitsRequest
+	mockService := &MockSplitService{
+		addSplitTagsFunc: func(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error) {
+			added = tags
+			return &services.LoadSplitResponse{ID: splitID, Tags: []string{"tax-2024", "urgent"}}, nil
+		},
+		removeSplitTagsFunc: func(ctx context.Context, splitID string, tags []string) (*services.LoadSplitResponse, error) {
+			removed = tags
+			return &services.LoadSplitResponse{ID: splitID, Tags: []string{"tax-2024"}}, nil
+		},
+		listClientSplitsFunc: func(ctx context.Context, req services.ListClientSplitsRequest) (*services.ListClientSplitsResponse, error) {
+			listed = req
+			return &services.ListClientSplitsResponse{ClientID: req.ClientID, Tag: req.Tag, Splits: []*services.SplitSummaryResponse{
+				{ID: "split-1", Status: "draft", Tags: []string{"urgent"}},
+			}, Limit: req.Limit}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /splits/{id}/tags", handler.AddSplitTagsHandler)
+	mux.HandleFunc("DELETE /splits/{id}/tags", handler.RemoveSplitTagsHandler)
+	mux.HandleFunc("GET /clients/{id}/splits", handler.ListClientSplitsHandler)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/splits/split-1/tags", `{"tags":["Urgent","tax-2024"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"Urgent", "tax-2024"}, added)
+	assert.Contains(t, w.Body.String(), `"tags":["tax-2024","urgent"]`)
+
+	w = serve(http.MethodDelete, "/splits/split-1/tags", `{"tags":["urgent"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"urgent"}, removed)
+	assert.Contains(t, w.Body.String(), `"tags":["tax-2024"]`)
+
+	w = serve(http.MethodPost, "/splits/split-1/tags", `{"tags":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodGet, "/clients/client-1/splits?tag=urgent&limit=5", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.ListClientSplitsRequest{ClientID: "client-1", Tag: "urgent", Limit: 5}, listed)
+	assert.Contains(t, w.Body.String(), `"tag":"urgent"`)
+	assert.Contains(t, w.Body.String(), `"id":"split-1"`)
+}
+
+func TestHandlersReadIDFromRoutePattern(t *testing.T) {
+	var gotID string
+	mockService := &MockSplitService{
+		downloadDocumentFunc: func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error) {
+			gotID = documentID
+			return &services.DownloadDocumentResponse{Data: []byte("PDF content")}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /documents/{id}/download", handler.DownloadDocumentHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/documents/doc-42/download", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "doc-42", gotID)
+}
+
+// compareMaps compares two maps recursively
+func compareMaps(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		bv, exists := b[k]
+		if !exists {
+			return false
+		}
+		switch av := v.(type) {
+		case map[string]interface{}:
+			bvMap, ok := bv.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if !compareMaps(av, bvMap) {
+				return false
+			}
+		case []interface{}:
+			bvSlice, ok := bv.([]interface{})
+			if !ok {
+				return false
+			}
+			if !compareSlices(av, bvSlice) {
+				return false
+			}
+		default:
+			if v != bv {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// compareSlices compares two slices recursively
+func compareSlices(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		switch av := a[i].(type) {
+		case map[string]interface{}:
+			bv, ok := b[i].(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if !compareMaps(av, bv) {
+				return false
+			}
+		case []interface{}:
+			bv, ok := b[i].([]interface{})
+			if !ok {
 				return false
 			}
 			if !compareSlices(av, bv) {
@@ -652,4 +2602,3 @@ func compareSlices(a, b []interface{}) bool {
 	}
 	return true
 }
-
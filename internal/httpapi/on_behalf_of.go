@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"accounting/internal/services"
+	"net/http"
+	"strings"
+)
+
+// OnBehalfOfHeader names the client an admin is acting on behalf of
+const OnBehalfOfHeader = "X-On-Behalf-Of"
+
+// OnBehalfOf scopes read requests of admins that send the X-On-Behalf-Of header
+// to that client's data and records each one in the audit log as impersonation.
+// Non-admins sending the header get 403, and it is rejected on requests that
+// modify data. Requests without the header pass through unchanged.
+func (h *SplitHandler) OnBehalfOf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID := r.Header.Get(OnBehalfOfHeader)
+		if clientID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		parts := strings.Split(r.Header.Get("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+			return
+		}
+		token, err := h.tokenVerifier.VerifyToken(parts[1])
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+		subject := tokenSubject(token)
+		if !h.isAdmin(subject) {
+			writeJSONError(w, http.StatusForbidden, OnBehalfOfHeader+" requires admin")
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeJSONError(w, http.StatusBadRequest, OnBehalfOfHeader+" is only allowed on read requests")
+			return
+		}
+
+		if err := h.splitSvc.RecordImpersonation(r.Context(), subject, clientID); err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(services.WithOnBehalfOf(r.Context(), clientID)))
+	})
+}
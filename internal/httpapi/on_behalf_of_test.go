@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"accounting/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnBehalfOf(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		subject         string
+		header          string
+		expectedStatus  int
+		expectedScope   string
+		expectedAudited string
+	}{
+		{name: "no header", method: http.MethodGet, subject: "user", expectedStatus: http.StatusOK},
+		{name: "admin impersonates", method: http.MethodGet, subject: "admin", header: "client1", expectedStatus: http.StatusOK, expectedScope: "client1", expectedAudited: "admin:client1"},
+		{name: "non-admin rejected", method: http.MethodGet, subject: "user", header: "client1", expectedStatus: http.StatusForbidden},
+		{name: "write rejected", method: http.MethodPost, subject: "admin", header: "client1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var audited, scope string
+			called := false
+			mockService := &MockSplitService{
+				recordImpersonationFunc: func(ctx context.Context, actor, clientID string) error {
+					audited = actor + ":" + clientID
+					return nil
+				},
+			}
+			handler := NewSplitHandler(mockService, &subjectVerifier{subject: tt.subject})
+			handler.SetAdmins([]string{"admin"})
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				scope = services.OnBehalfOfFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(tt.method, "/splits/123", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			if tt.header != "" {
+				req.Header.Set(OnBehalfOfHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.OnBehalfOf(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+			assert.Equal(t, tt.expectedScope, scope)
+			assert.Equal(t, tt.expectedAudited, audited)
+		})
+	}
+}
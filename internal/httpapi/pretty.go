@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// PrettyHeader asks for indented JSON responses, like the pretty query parameter
+const PrettyHeader = "X-Pretty"
+
+// prettyJSONWriter marks a response whose JSON writeJSON indents
+type prettyJSONWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (pw *prettyJSONWriter) Unwrap() http.ResponseWriter {
+	return pw.ResponseWriter
+}
+
+// PrettyJSON makes writeJSON indent its output for requests with ?pretty=true
+// or an X-Pretty: true header, for debugging. Other responses are left as they are.
+func PrettyJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wantsPretty(r) {
+			w = &prettyJSONWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wantsPretty reports whether the request asks for indented JSON
+func wantsPretty(r *http.Request) bool {
+	value := r.URL.Query().Get("pretty")
+	if value == "" {
+		value = r.Header.Get(PrettyHeader)
+	}
+	pretty, _ := strconv.ParseBool(value)
+	return pretty
+}
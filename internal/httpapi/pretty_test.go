@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"accounting/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyJSON(t *testing.T) {
+	mockService := &MockSplitService{
+		downloadDocumentFunc: func(ctx context.Context, documentID string) (*services.DownloadDocumentResponse, error) {
+			return &services.DownloadDocumentResponse{Data: []byte("{\"raw\":true}"), Filename: "w2.pdf"}, nil
+		},
+	}
+	handler := NewSplitHandler(mockService, &mockVerifier{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"id": "split-1"})
+	})
+	mux.HandleFunc("GET /documents/{id}/download", handler.DownloadDocumentHandler)
+	server := PrettyJSON(mux)
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		expected string
+	}{
+		{name: "compact by default", target: "/json", expected: "{\"id\":\"split-1\"}\n"},
+		{name: "pretty query", target: "/json?pretty=true", expected: "{\n  \"id\": \"split-1\"\n}\n"},
+		{name: "pretty header", target: "/json", header: "true", expected: "{\n  \"id\": \"split-1\"\n}\n"},
+		{name: "pretty false", target: "/json?pretty=false", header: "true", expected: "{\"id\":\"split-1\"}\n"},
+		{name: "download is untouched", target: "/documents/doc-1/download?pretty=true", expected: "{\"raw\":true}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			if tt.header != "" {
+				req.Header.Set(PrettyHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, w.Body.String())
+			if strings.HasPrefix(tt.target, "/json") {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
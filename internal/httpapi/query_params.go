@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// routeQueryParams lists the query parameters each route reads, by the pattern it is
+// registered with. Routes not listed read none; pretty is accepted on every route.
+var routeQueryParams = map[string][]string{
+	"GET /splits/{id}":              {"include", "stream"},
+	"DELETE /splits/{id}":           {"force"},
+	"POST /splits/ingest":           {"client_id"},
+	"POST /splits/{id}/finalize":    {"dry_run"},
+	"GET /clients":                  {"limit", "offset"},
+	"GET /clients/{id}/pages":       {"classification", "limit", "offset"},
+	"GET /clients/{id}/splits":      {"tag", "limit", "offset"},
+	"POST /admin/consistency-check": {"repair"},
+}
+
+// RejectUnknownQueryParams rejects requests to a route of mux carrying query parameters
+// the route does not read, per routeQueryParams, with 400 listing them, so that a typo
+// such as ?limt=10 is not silently ignored. Requests matching no route pass through.
+func RejectUnknownQueryParams(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+			if pattern == "" || r.URL.RawQuery == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed := routeQueryParams[pattern]
+			var unknown []string
+			for name := range r.URL.Query() {
+				if name != "pretty" && !slices.Contains(allowed, name) {
+					unknown = append(unknown, name)
+				}
+			}
+			if len(unknown) > 0 {
+				slices.Sort(unknown)
+				writeJSONError(w, http.StatusBadRequest, "unknown query parameters: "+strings.Join(unknown, ", "))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
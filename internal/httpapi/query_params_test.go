@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectUnknownQueryParams(t *testing.T) {
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	mux.HandleFunc("GET /clients", ok)
+	mux.HandleFunc("GET /clients/{id}/stats", ok)
+	handler := RejectUnknownQueryParams(mux)(mux)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "known params", target: "/clients?limit=10&offset=20", expectedStatus: http.StatusOK},
+		{name: "no params", target: "/clients", expectedStatus: http.StatusOK},
+		{name: "pretty anywhere", target: "/clients/c1/stats?pretty=true", expectedStatus: http.StatusOK},
+		{name: "typo", target: "/clients?limt=10", expectedStatus: http.StatusBadRequest, expectedError: "unknown query parameters: limt"},
+		{name: "route reading none", target: "/clients/c1/stats?offset=1&b=2", expectedStatus: http.StatusBadRequest, expectedError: "unknown query parameters: b, offset"},
+		{name: "unknown route passes through", target: "/nowhere?x=1", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body["error"])
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRouteErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /splits/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"id": r.PathValue("id")})
+	})
+	mux.HandleFunc("DELETE /splits/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := JSONRouteErrors(mux)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   map[string]string
+		expectedAllow  string
+	}{
+		{
+			name:           "matched route",
+			method:         http.MethodGet,
+			path:           "/splits/split-1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"id": "split-1"},
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/nope",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"error": "route not found", "path": "/nope"},
+		},
+		{
+			name:           "disallowed method",
+			method:         http.MethodPost,
+			path:           "/splits/split-1",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   map[string]string{"error": "method not allowed", "path": "/splits/split-1"},
+			expectedAllow:  "DELETE, GET, HEAD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Allow"))
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedBody, body)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package blob
+
+import (
+	"accounting/internal/domain/ports"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Assert that *FileBlobStore implements ports.BlobStore and ports.BlobWriter interfaces
+var (
+	_ ports.BlobStore  = (*FileBlobStore)(nil)
+	_ ports.BlobWriter = (*FileBlobStore)(nil)
+)
+
+// FileBlobStore implements ports.BlobStore on top of a local directory
+type FileBlobStore struct {
+	root string
+}
+
+// NewFileBlobStore creates a blob store serving files below root
+func NewFileBlobStore(root string) *FileBlobStore {
+	return &FileBlobStore{root: root}
+}
+
+// path returns the file referenced by url, rejecting urls that escape the store root
+func (s *FileBlobStore) path(url string) (string, error) {
+	name := filepath.FromSlash(url)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid blob path %q", url)
+	}
+	return filepath.Join(s.root, name), nil
+}
+
+// Open returns a reader for the file referenced by url, relative to the store root
+func (s *FileBlobStore) Open(ctx context.Context, url string) (io.ReadCloser, error) {
+	name, err := s.path(url)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob: %w", err)
+	}
+	return f, nil
+}
+
+// Put writes the content read from r to the file referenced by url, creating its
+// directory as needed. The content is written to a temporary file first, so readers
+// never see a partly written blob.
+func (s *FileBlobStore) Put(ctx context.Context, url string, r io.Reader) error {
+	name, err := s.path(url)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("error creating blob directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("error storing blob: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the file referenced by url; a missing file is not an error
+func (s *FileBlobStore) Delete(ctx context.Context, url string) error {
+	name, err := s.path(url)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBlobStore_Open(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "page_1.png"), []byte("png data"), 0o644))
+
+	store := NewFileBlobStore(root)
+	ctx := context.Background()
+
+	// Test reading an existing blob
+	rc, err := store.Open(ctx, "page_1.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, []byte("png data"), data)
+
+	// Test missing blob
+	_, err = store.Open(ctx, "page_2.png")
+	assert.Error(t, err)
+
+	// Test paths escaping the root
+	_, err = store.Open(ctx, "../secret.png")
+	assert.Error(t, err)
+	_, err = store.Open(ctx, "/etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestFileBlobStore_PutDelete(t *testing.T) {
+	root := t.TempDir()
+	store := NewFileBlobStore(root)
+	ctx := context.Background()
+
+	// Test storing a blob in a new directory and replacing it
+	require.NoError(t, store.Put(ctx, "split-1/page_1.png", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "split-1/page_1.png", strings.NewReader("png data")))
+	data, err := os.ReadFile(filepath.Join(root, "split-1", "page_1.png"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png data"), data)
+	entries, err := os.ReadDir(filepath.Join(root, "split-1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are cleaned up")
+
+	// Test paths escaping the root
+	assert.Error(t, store.Put(ctx, "../secret.png", strings.NewReader("x")))
+	assert.Error(t, store.Delete(ctx, "/etc/passwd"))
+
+	// Test deleting, including a blob that is already gone
+	require.NoError(t, store.Delete(ctx, "split-1/page_1.png"))
+	_, err = store.Open(ctx, "split-1/page_1.png")
+	assert.Error(t, err)
+	assert.NoError(t, store.Delete(ctx, "split-1/page_1.png"))
+}
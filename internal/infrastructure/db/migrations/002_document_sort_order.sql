@@ -0,0 +1,2 @@
+-- Explicit document ordering within a split
+ALTER TABLE documents ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Users created at runtime through the admin endpoint
+CREATE TABLE IF NOT EXISTS users (
+    username TEXT PRIMARY KEY,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
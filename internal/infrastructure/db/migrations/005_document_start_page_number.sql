@@ -0,0 +1,8 @@
+-- Numeric first page of each document; start_page holds a URL and sorts lexicographically
+ALTER TABLE documents ADD COLUMN start_page_number INTEGER NOT NULL DEFAULT 0;
+
+UPDATE documents
+SET start_page_number = COALESCE(
+    (SELECT MIN(CAST(p.page_number AS INTEGER)) FROM pages p WHERE p.document_id = documents.id),
+    0
+);
@@ -0,0 +1,9 @@
+-- Privileged actions such as force-deleting finalized splits
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
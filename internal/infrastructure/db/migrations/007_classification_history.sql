@@ -0,0 +1,10 @@
+-- Trail of document reclassifications
+CREATE TABLE IF NOT EXISTS classification_history (
+    id TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL,
+    old_classification TEXT NOT NULL,
+    new_classification TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_classification_history_document_id ON classification_history(document_id);
@@ -0,0 +1,6 @@
+-- Advisory locks held by an editor of a split until they expire
+CREATE TABLE IF NOT EXISTS split_locks (
+    split_id TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+-- When a split was finalized, so a retried finalize can report the original time
+ALTER TABLE splits ADD COLUMN finalized_at TIMESTAMP;
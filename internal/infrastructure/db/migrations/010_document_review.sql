@@ -0,0 +1,4 @@
+-- Reviewer sign-off on documents
+ALTER TABLE documents ADD COLUMN reviewed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE documents ADD COLUMN reviewed_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN reviewed_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Optional human-friendly split name; existing splits keep an empty name
+ALTER TABLE splits ADD COLUMN name TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Listing a client's splits filters by client and sorts by creation time; the page and
+-- document lookups are indexed by the initial schema
+CREATE INDEX IF NOT EXISTS idx_splits_client_id_created_at ON splits(client_id, created_at);
@@ -0,0 +1,8 @@
+-- Labels on splits for categorization; a split has each tag at most once, and
+-- the index serves filtering a client's splits by tag
+CREATE TABLE IF NOT EXISTS split_tags (
+    split_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (split_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_split_tags_tag ON split_tags(tag, split_id);
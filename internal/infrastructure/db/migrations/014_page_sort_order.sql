@@ -0,0 +1,2 @@
+-- Explicit page ordering within a document
+ALTER TABLE pages ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
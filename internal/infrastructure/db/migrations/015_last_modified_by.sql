@@ -0,0 +1,3 @@
+-- Who last changed a split or document; existing rows have no recorded editor
+ALTER TABLE splits ADD COLUMN last_modified_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN last_modified_by TEXT NOT NULL DEFAULT '';
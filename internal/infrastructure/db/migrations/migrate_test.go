@@ -0,0 +1,111 @@
+package migrations_test
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/infrastructure/db/migrations"
+	"accounting/internal/infrastructure/db/repositories/splits"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMigrationsProvisionsEmptyDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "accounting.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, migrations.ApplyMigrations(db))
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	finalizedAt := now.Add(time.Minute)
+	reviewedAt := now.Add(30 * time.Second)
+	docID := "doc1"
+	split := &domain.Split{
+		ID:          "split1",
+		ClientID:    "client1",
+		Name:        "Smith household",
+		Status:      domain.SplitStatusFinalized,
+		Tags:        []string{"tax-2024", "urgent"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		FinalizedAt: &finalizedAt,
+		Documents: []domain.Document{{
+			ID: docID, SplitID: "split1", Name: "W-2", Classification: "W-2", Filename: "w2.pdf",
+			Reviewed: true, ReviewedBy: "alice", ReviewedAt: &reviewedAt,
+			Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			},
+		}},
+		UnassignedPages: []*domain.Page{
+			{ID: "page2", SplitID: "split1", PageNumber: 2, URL: "page_2.png"},
+		},
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, splits.NewSplitRepositorySQL(tx).Save(ctx, split))
+	require.NoError(t, tx.Commit())
+
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	loaded, err := splits.NewSplitRepositorySQL(tx).Get(ctx, "split1")
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+
+	assert.Equal(t, "Smith household", loaded.Name)
+	assert.Equal(t, []string{"tax-2024", "urgent"}, loaded.Tags)
+	assert.Equal(t, domain.SplitStatusFinalized, loaded.Status)
+	require.NotNil(t, loaded.FinalizedAt)
+	assert.True(t, finalizedAt.Equal(*loaded.FinalizedAt))
+	require.Len(t, loaded.Documents, 1)
+	doc := loaded.Documents[0]
+	assert.Equal(t, "w2.pdf", doc.Filename)
+	assert.True(t, doc.Reviewed)
+	assert.Equal(t, "alice", doc.ReviewedBy)
+	require.Len(t, doc.Pages, 1)
+	assert.Equal(t, "page_1.png", doc.Pages[0].URL)
+	require.Len(t, loaded.UnassignedPages, 1)
+	assert.Equal(t, 2, loaded.UnassignedPages[0].PageNumber)
+}
+
+func TestApplyMigrationsIndexesHotQueries(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "accounting.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, migrations.ApplyMigrations(db))
+
+	tests := []struct {
+		query string
+		index string
+	}{
+		{"SELECT id FROM pages WHERE document_id = ? ORDER BY CAST(page_number AS INTEGER)", "idx_pages_document_id"},
+		{"SELECT COUNT(*) FROM pages WHERE document_id = ?", "idx_pages_document_id"},
+		{"SELECT id FROM pages WHERE split_id = ? AND document_id IS NULL ORDER BY CAST(page_number AS INTEGER)", "idx_pages_split_id"},
+		{"SELECT id FROM documents WHERE split_id = ? ORDER BY sort_order, start_page_number, id", "idx_documents_split_id"},
+		{"SELECT id FROM splits WHERE client_id = ? ORDER BY created_at DESC", "idx_splits_client_id_created_at"},
+		{"SELECT split_id FROM split_tags WHERE tag = ?", "idx_split_tags_tag"},
+	}
+	for _, tt := range tests {
+		rows, err := db.Query("EXPLAIN QUERY PLAN "+tt.query, "x")
+		require.NoError(t, err)
+		var plan []string
+		for rows.Next() {
+			var id, parent, notUsed int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+			plan = append(plan, detail)
+		}
+		require.NoError(t, rows.Err())
+		rows.Close()
+		assert.Contains(t, strings.Join(plan, "\n"), "INDEX "+tt.index, tt.query)
+	}
+}
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Assert that *AuditRepositorySQL implements domain.AuditRepository interface
+var _ domain.AuditRepository = (*AuditRepositorySQL)(nil)
+
+// AuditRepositorySQL implements domain.AuditRepository using SQLite
+type AuditRepositorySQL struct {
+	tx *sql.Tx
+}
+
+// NewAuditRepositorySQL creates a new SQLite-based audit repository
+func NewAuditRepositorySQL(tx *sql.Tx) *AuditRepositorySQL {
+	return &AuditRepositorySQL{tx: tx}
+}
+
+// Add records an audit entry as part of the current transaction
+func (r *AuditRepositorySQL) Add(ctx context.Context, entry *domain.AuditEntry) error {
+	_, err := r.tx.ExecContext(ctx, `
+		INSERT INTO audit_log (id, actor, action, resource, resource_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, entry.ID, entry.Actor, entry.Action, entry.Resource, entry.ResourceID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error adding audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepositorySQL_Add(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE audit_log (
+			id TEXT PRIMARY KEY,
+			actor TEXT NOT NULL,
+			action TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			resource_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+	`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	repo := NewAuditRepositorySQL(tx)
+	err = repo.Add(context.Background(), &domain.AuditEntry{
+		ID:         "entry1",
+		Actor:      "admin",
+		Action:     domain.AuditActionForceDeleteSplit,
+		Resource:   "split",
+		ResourceID: "split1",
+		CreatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+
+	var actor, action, resourceID string
+	err = tx.QueryRow(`SELECT actor, action, resource_id FROM audit_log WHERE id = ?`, "entry1").Scan(&actor, &action, &resourceID)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", actor)
+	assert.Equal(t, domain.AuditActionForceDeleteSplit, action)
+	assert.Equal(t, "split1", resourceID)
+}
@@ -0,0 +1,61 @@
+package locks
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Assert that *LockRepositorySQL implements domain.LockRepository interface
+var _ domain.LockRepository = (*LockRepositorySQL)(nil)
+
+// LockRepositorySQL implements domain.LockRepository using SQLite
+type LockRepositorySQL struct {
+	tx *sql.Tx
+}
+
+// NewLockRepositorySQL creates a new SQLite-based split lock repository
+func NewLockRepositorySQL(tx *sql.Tx) *LockRepositorySQL {
+	return &LockRepositorySQL{tx: tx}
+}
+
+// Get retrieves the lock on a split, or nil if there is none
+func (r *LockRepositorySQL) Get(ctx context.Context, splitID string) (*domain.SplitLock, error) {
+	lock := &domain.SplitLock{}
+	err := r.tx.QueryRowContext(ctx, `
+		SELECT split_id, holder, expires_at
+		FROM split_locks
+		WHERE split_id = ?
+	`, splitID).Scan(&lock.SplitID, &lock.Holder, &lock.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting split lock: %w", err)
+	}
+	return lock, nil
+}
+
+// Put creates or replaces the lock on a split
+func (r *LockRepositorySQL) Put(ctx context.Context, lock *domain.SplitLock) error {
+	_, err := r.tx.ExecContext(ctx, `
+		INSERT INTO split_locks (split_id, holder, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(split_id) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+	`, lock.SplitID, lock.Holder, lock.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("error saving split lock: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the lock on a split, if any
+func (r *LockRepositorySQL) Delete(ctx context.Context, splitID string) error {
+	_, err := r.tx.ExecContext(ctx, "DELETE FROM split_locks WHERE split_id = ?", splitID)
+	if err != nil {
+		return fmt.Errorf("error deleting split lock: %w", err)
+	}
+	return nil
+}
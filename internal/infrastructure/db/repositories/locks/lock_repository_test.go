@@ -0,0 +1,54 @@
+package locks
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockRepositorySQL_PutGetDelete(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE split_locks (
+			split_id TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+	`)
+	require.NoError(t, err)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	ctx := context.Background()
+	repo := NewLockRepositorySQL(tx)
+
+	lock, err := repo.Get(ctx, "split1")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+
+	expires := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	require.NoError(t, repo.Put(ctx, &domain.SplitLock{SplitID: "split1", Holder: "alice", ExpiresAt: expires}))
+	require.NoError(t, repo.Put(ctx, &domain.SplitLock{SplitID: "split1", Holder: "bob", ExpiresAt: expires}))
+
+	lock, err = repo.Get(ctx, "split1")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, "bob", lock.Holder)
+	assert.True(t, expires.Equal(lock.ExpiresAt))
+
+	require.NoError(t, repo.Delete(ctx, "split1"))
+	lock, err = repo.Get(ctx, "split1")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+}
@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Assert that *OutboxRepositorySQL implements domain.OutboxRepository interface
+var _ domain.OutboxRepository = (*OutboxRepositorySQL)(nil)
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so the repository can enqueue
+// inside a unit of work and be polled by the dispatcher outside of one
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// OutboxRepositorySQL implements domain.OutboxRepository using SQLite
+type OutboxRepositorySQL struct {
+	db dbtx
+}
+
+// NewOutboxRepositorySQL creates a new SQLite-based outbox repository
+func NewOutboxRepositorySQL(db dbtx) *OutboxRepositorySQL {
+	return &OutboxRepositorySQL{db: db}
+}
+
+// Add enqueues a message
+func (r *OutboxRepositorySQL) Add(ctx context.Context, msg *domain.OutboxMessage) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox (id, event_type, payload, created_at, attempts)
+		VALUES (?, ?, ?, ?, 0)
+	`, msg.ID, msg.EventType, string(msg.Payload), msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error adding outbox message: %w", err)
+	}
+	return nil
+}
+
+// ListPending retrieves up to limit unsent messages that have been attempted
+// fewer than maxAttempts times, oldest first
+func (r *OutboxRepositorySQL) ListPending(ctx context.Context, limit, maxAttempts int) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_type, payload, created_at, attempts
+		FROM outbox
+		WHERE sent_at IS NULL AND attempts < ?
+		ORDER BY created_at, id
+		LIMIT ?
+	`, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []domain.OutboxMessage
+	for rows.Next() {
+		var msg domain.OutboxMessage
+		var payload string
+		if err := rows.Scan(&msg.ID, &msg.EventType, &payload, &msg.CreatedAt, &msg.Attempts); err != nil {
+			return nil, fmt.Errorf("error scanning outbox message: %w", err)
+		}
+		msg.Payload = []byte(payload)
+		msgs = append(msgs, msg)
+	}
+	return msgs, rows.Err()
+}
+
+// MarkSent records a successful delivery
+func (r *OutboxRepositorySQL) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET sent_at = ?, attempts = attempts + 1, last_error = NULL
+		WHERE id = ?
+	`, sentAt, id)
+	if err != nil {
+		return fmt.Errorf("error marking outbox message sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt
+func (r *OutboxRepositorySQL) MarkFailed(ctx context.Context, id string, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = ?
+		WHERE id = ?
+	`, reason, id)
+	if err != nil {
+		return fmt.Errorf("error marking outbox message failed: %w", err)
+	}
+	return nil
+}
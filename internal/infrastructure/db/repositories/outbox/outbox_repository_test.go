@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+		CREATE TABLE outbox (
+			id TEXT PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			sent_at TIMESTAMP,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT
+		);
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestOutboxRepositorySQL_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewOutboxRepositorySQL(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.Add(ctx, &domain.OutboxMessage{ID: "msg1", EventType: "test", Payload: []byte(`{"a":1}`), CreatedAt: now}))
+	require.NoError(t, repo.Add(ctx, &domain.OutboxMessage{ID: "msg2", EventType: "test", Payload: []byte(`{"a":2}`), CreatedAt: now.Add(time.Second)}))
+
+	pending, err := repo.ListPending(ctx, 10, 3)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "msg1", pending[0].ID)
+	assert.Equal(t, []byte(`{"a":1}`), pending[0].Payload)
+
+	// A sent message is no longer pending
+	require.NoError(t, repo.MarkSent(ctx, "msg1", now))
+	// A message that exhausted its attempts is no longer pending
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.MarkFailed(ctx, "msg2", "connection refused"))
+	}
+
+	pending, err = repo.ListPending(ctx, 10, 3)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	pending, err = repo.ListPending(ctx, 10, 4)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "msg2", pending[0].ID)
+	assert.Equal(t, 3, pending[0].Attempts)
+}
@@ -5,11 +5,17 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
 )
 
 // SplitRepositorySQL implements domain.SplitRepository using SQLite
 type SplitRepositorySQL struct {
 	tx *sql.Tx
+	// verifySaves makes Save reload the page counts it wrote and log mismatches
+	verifySaves bool
 }
 
 // NewSplitRepositorySQL creates a new SQLite-based split repository
@@ -17,15 +23,26 @@ func NewSplitRepositorySQL(tx *sql.Tx) *SplitRepositorySQL {
 	return &SplitRepositorySQL{tx: tx}
 }
 
+// SetVerifySaves makes Save check, after writing, that each document of the split
+// has as many pages in the database as in the aggregate, logging a warning when
+// not. It is a debugging aid for the page deletion diff, at the cost of a query per save.
+func (r *SplitRepositorySQL) SetVerifySaves(verify bool) {
+	r.verifySaves = verify
+}
+
 // Get retrieves a split by ID
 func (r *SplitRepositorySQL) Get(ctx context.Context, id string) (*domain.Split, error) {
+	return r.GetParts(ctx, id, domain.SplitParts{Documents: true, UnassignedPages: true})
+}
+
+// GetParts retrieves a split by ID, loading only the selected children
+func (r *SplitRepositorySQL) GetParts(ctx context.Context, id string, parts domain.SplitParts) (*domain.Split, error) {
 	// Get split
-	var split domain.Split
-	err := r.tx.QueryRowContext(ctx, `
-		SELECT id, client_id, status, created_at, updated_at
+	split, err := scanSplit(r.tx.QueryRowContext(ctx, `
+		SELECT `+splitColumns+`
 		FROM splits
 		WHERE id = ?
-	`, id).Scan(&split.ID, &split.ClientID, &split.Status, &split.CreatedAt, &split.UpdatedAt)
+	`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -34,37 +51,179 @@ func (r *SplitRepositorySQL) Get(ctx context.Context, id string) (*domain.Split,
 	}
 
 	// Get documents
-	documents, err := r.getDocuments(ctx, id)
-	if err != nil {
-		return nil, err
+	if parts.Documents {
+		documents, err := r.getDocuments(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		split.Documents = documents
 	}
-	split.Documents = documents
 
 	// Get unassigned pages
-	unassignedPages, err := r.getUnassignedPages(ctx, id)
+	if parts.UnassignedPages {
+		unassignedPages, err := r.getUnassignedPages(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		split.UnassignedPages = unassignedPages
+	}
+
+	return split, nil
+}
+
+// GetMany retrieves the splits with the given IDs in the order requested, loading
+// their documents and pages with one query each instead of per split.
+// Missing IDs are omitted and repeated IDs are returned once.
+func (r *SplitRepositorySQL) GetMany(ctx context.Context, ids []string) ([]*domain.Split, error) {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return []*domain.Split{}, nil
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(unique)), ",")
+	args := make([]any, len(unique))
+	for i, id := range unique {
+		args[i] = id
+	}
+
+	// Get splits
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT `+splitColumns+`
+		FROM splits
+		WHERE id IN (`+in+`)
+	`, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error getting splits: %w", err)
+	}
+	byID := make(map[string]*domain.Split, len(unique))
+	for rows.Next() {
+		split, err := scanSplit(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning split: %w", err)
+		}
+		byID[split.ID] = split
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting splits: %w", err)
+	}
+	if len(byID) == 0 {
+		return []*domain.Split{}, nil
 	}
-	split.UnassignedPages = unassignedPages
 
-	return &split, nil
+	// Get documents of all splits, in the same order as getDocuments
+	rows, err = r.tx.QueryContext(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE split_id IN (`+in+`)
+		ORDER BY sort_order, start_page_number, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting documents: %w", err)
+	}
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		split := byID[doc.SplitID]
+		split.Documents = append(split.Documents, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting documents: %w", err)
+	}
+
+	// Documents are only appended above, so pointers into the slices stay valid
+	docs := make(map[string]*domain.Document)
+	for _, split := range byID {
+		for i := range split.Documents {
+			docs[split.Documents[i].ID] = &split.Documents[i]
+		}
+	}
+
+	// Get the pages of those documents and the unassigned pages of the splits
+	rows, err = r.tx.QueryContext(ctx, `
+		SELECT id, split_id, document_id, page_number, url, sort_order
+		FROM pages
+		WHERE document_id IN (SELECT id FROM documents WHERE split_id IN (`+in+`))
+			OR (document_id IS NULL AND split_id IN (`+in+`))
+		ORDER BY sort_order, CAST(page_number AS INTEGER)
+	`, append(args, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pages: %w", err)
+	}
+	for rows.Next() {
+		var page domain.Page
+		var documentID sql.NullString
+		if err := rows.Scan(&page.ID, &page.SplitID, &documentID, &page.PageNumber, &page.URL, &page.SortOrder); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning page: %w", err)
+		}
+		if !documentID.Valid {
+			split := byID[page.SplitID]
+			split.UnassignedPages = append(split.UnassignedPages, &page)
+			continue
+		}
+		doc := docs[documentID.String]
+		doc.Pages = append(doc.Pages, &page)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting pages: %w", err)
+	}
+
+	splits := make([]*domain.Split, 0, len(byID))
+	for _, id := range unique {
+		if split, ok := byID[id]; ok {
+			splits = append(splits, split)
+		}
+	}
+	return splits, nil
 }
 
 // Save persists a split aggregate
 func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) error {
+	if err := checkPageOwnership(split); err != nil {
+		return err
+	}
+
 	// Save split
 	_, err := r.tx.ExecContext(ctx, `
-		INSERT INTO splits (id, client_id, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO splits (id, client_id, name, status, created_at, updated_at, last_modified_by, finalized_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			client_id = excluded.client_id,
+			name = excluded.name,
 			status = excluded.status,
-			updated_at = excluded.updated_at
-	`, split.ID, split.ClientID, split.Status, split.CreatedAt, split.UpdatedAt)
+			updated_at = excluded.updated_at,
+			last_modified_by = excluded.last_modified_by,
+			finalized_at = excluded.finalized_at
+	`, split.ID, split.ClientID, split.Name, split.Status, split.CreatedAt, split.UpdatedAt, split.LastModifiedBy, split.FinalizedAt)
 	if err != nil {
 		return fmt.Errorf("error saving split: %w", err)
 	}
 
+	// Replace tags
+	_, err = r.tx.ExecContext(ctx, "DELETE FROM split_tags WHERE split_id = ?", split.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting split tags: %w", err)
+	}
+	for _, tag := range split.Tags {
+		_, err = r.tx.ExecContext(ctx, "INSERT INTO split_tags (split_id, tag) VALUES (?, ?)", split.ID, tag)
+		if err != nil {
+			return fmt.Errorf("error saving split tag: %w", err)
+		}
+	}
+
 	// Delete documents not present in split.Documents
 	docIDs := make(map[string]struct{}, len(split.Documents))
 	for _, doc := range split.Documents {
@@ -94,6 +253,10 @@ func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) erro
 		if err != nil {
 			return fmt.Errorf("error deleting pages for document: %w", err)
 		}
+		_, err = r.tx.ExecContext(ctx, "DELETE FROM classification_history WHERE document_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("error deleting classification history for document: %w", err)
+		}
 	}
 
 	// Delete pages not present in split.Documents or split.UnassignedPages
@@ -131,8 +294,8 @@ func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) erro
 	// Save documents
 	for _, doc := range split.Documents {
 		_, err = r.tx.ExecContext(ctx, `
-			INSERT INTO documents (id, split_id, name, classification, filename, short_description, start_page, end_page)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO documents (id, split_id, name, classification, filename, short_description, start_page, end_page, start_page_number, sort_order, reviewed, reviewed_by, reviewed_at, last_modified_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(id) DO UPDATE SET
 				split_id = excluded.split_id,
 				name = excluded.name,
@@ -140,8 +303,14 @@ func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) erro
 				filename = excluded.filename,
 				short_description = excluded.short_description,
 				start_page = excluded.start_page,
-				end_page = excluded.end_page
-		`, doc.ID, doc.SplitID, doc.Name, doc.Classification, doc.Filename, doc.ShortDescription, doc.StartPage, doc.EndPage)
+				end_page = excluded.end_page,
+				start_page_number = excluded.start_page_number,
+				sort_order = excluded.sort_order,
+				reviewed = excluded.reviewed,
+				reviewed_by = excluded.reviewed_by,
+				reviewed_at = excluded.reviewed_at,
+				last_modified_by = excluded.last_modified_by
+		`, doc.ID, doc.SplitID, doc.Name, doc.Classification, doc.Filename, doc.ShortDescription, doc.StartPage, doc.EndPage, doc.StartPageNumber(), doc.SortOrder, doc.Reviewed, doc.ReviewedBy, doc.ReviewedAt, doc.LastModifiedBy)
 		if err != nil {
 			return fmt.Errorf("error saving document: %w", err)
 		}
@@ -149,14 +318,15 @@ func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) erro
 		// Save pages
 		for _, page := range doc.Pages {
 			_, err = r.tx.ExecContext(ctx, `
-				INSERT INTO pages (id, split_id, document_id, page_number, url)
-				VALUES (?, ?, ?, ?, ?)
+				INSERT INTO pages (id, split_id, document_id, page_number, url, sort_order)
+				VALUES (?, ?, ?, ?, ?, ?)
 				ON CONFLICT(id) DO UPDATE SET
 					split_id = excluded.split_id,
 					document_id = excluded.document_id,
 					page_number = excluded.page_number,
-					url = excluded.url
-			`, page.ID, page.SplitID, doc.ID, page.PageNumber, page.URL)
+					url = excluded.url,
+					sort_order = excluded.sort_order
+			`, page.ID, page.SplitID, doc.ID, page.PageNumber, page.URL, page.SortOrder)
 			if err != nil {
 				return fmt.Errorf("error saving page: %w", err)
 			}
@@ -166,19 +336,109 @@ func (r *SplitRepositorySQL) Save(ctx context.Context, split *domain.Split) erro
 	// Save unassigned pages
 	for _, page := range split.UnassignedPages {
 		_, err = r.tx.ExecContext(ctx, `
-			INSERT INTO pages (id, split_id, document_id, page_number, url)
-			VALUES (?, ?, NULL, ?, ?)
+			INSERT INTO pages (id, split_id, document_id, page_number, url, sort_order)
+			VALUES (?, ?, NULL, ?, ?, 0)
 			ON CONFLICT(id) DO UPDATE SET
 				split_id = excluded.split_id,
 				document_id = excluded.document_id,
 				page_number = excluded.page_number,
-				url = excluded.url
+				url = excluded.url,
+				sort_order = excluded.sort_order
 		`, page.ID, page.SplitID, page.PageNumber, page.URL)
 		if err != nil {
 			return fmt.Errorf("error saving unassigned page: %w", err)
 		}
 	}
 
+	if r.verifySaves {
+		mismatches, err := r.pageCountMismatches(ctx, split)
+		if err != nil {
+			return err
+		}
+		for _, m := range mismatches {
+			log.Printf("split save integrity check failed, split: %s, %s", split.ID, m)
+		}
+	}
+
+	return nil
+}
+
+// pageCountMismatches compares the page count of each document of split, and of its
+// unassigned pages, with the pages stored for the split and describes any difference
+func (r *SplitRepositorySQL) pageCountMismatches(ctx context.Context, split *domain.Split) ([]string, error) {
+	const unassigned = ""
+	want := map[string]int{unassigned: len(split.UnassignedPages)}
+	for _, doc := range split.Documents {
+		want[doc.ID] = len(doc.Pages)
+	}
+
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT COALESCE(document_id, ''), COUNT(*)
+		FROM pages
+		WHERE split_id = ?
+		GROUP BY document_id
+	`, split.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting saved pages: %w", err)
+	}
+	defer rows.Close()
+	got := make(map[string]int)
+	for rows.Next() {
+		var docID string
+		var n int
+		if err := rows.Scan(&docID, &n); err != nil {
+			return nil, fmt.Errorf("error scanning saved page count: %w", err)
+		}
+		got[docID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error counting saved pages: %w", err)
+	}
+
+	var mismatches []string
+	describe := func(docID string) string {
+		if docID == unassigned {
+			return "unassigned pages"
+		}
+		return "document: " + docID
+	}
+	for docID, n := range want {
+		if got[docID] != n {
+			mismatches = append(mismatches, fmt.Sprintf("%s, expected pages: %d, saved pages: %d", describe(docID), n, got[docID]))
+		}
+	}
+	for docID, n := range got {
+		if _, ok := want[docID]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s, expected pages: 0, saved pages: %d", describe(docID), n))
+		}
+	}
+	return mismatches, nil
+}
+
+// checkPageOwnership guards against aggregates that bypassed the domain invariants:
+// a page may appear under at most one document, or as unassigned, but not both.
+// The page upserts in Save would otherwise silently keep whichever write came last.
+func checkPageOwnership(split *domain.Split) error {
+	owner := make(map[string]string)
+	claim := func(pageID, by string) error {
+		if prev, ok := owner[pageID]; ok {
+			return domain.NewInternalError(fmt.Sprintf("page %s appears in both %s and %s of split %s", pageID, prev, by, split.ID), nil)
+		}
+		owner[pageID] = by
+		return nil
+	}
+	for _, doc := range split.Documents {
+		for _, page := range doc.Pages {
+			if err := claim(page.ID, "document "+doc.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, page := range split.UnassignedPages {
+		if err := claim(page.ID, "unassigned pages"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -190,12 +450,24 @@ func (r *SplitRepositorySQL) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("error deleting pages: %w", err)
 	}
 
+	// Delete the classification history of the documents
+	_, err = r.tx.ExecContext(ctx, "DELETE FROM classification_history WHERE document_id IN (SELECT id FROM documents WHERE split_id = ?)", id)
+	if err != nil {
+		return fmt.Errorf("error deleting classification history: %w", err)
+	}
+
 	// Delete documents
 	_, err = r.tx.ExecContext(ctx, "DELETE FROM documents WHERE split_id = ?", id)
 	if err != nil {
 		return fmt.Errorf("error deleting documents: %w", err)
 	}
 
+	// Delete tags
+	_, err = r.tx.ExecContext(ctx, "DELETE FROM split_tags WHERE split_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting split tags: %w", err)
+	}
+
 	// Delete split
 	_, err = r.tx.ExecContext(ctx, "DELETE FROM splits WHERE id = ?", id)
 	if err != nil {
@@ -208,7 +480,7 @@ func (r *SplitRepositorySQL) Delete(ctx context.Context, id string) error {
 // ListByClientID retrieves all splits for a client
 func (r *SplitRepositorySQL) ListByClientID(ctx context.Context, clientID string) ([]*domain.Split, error) {
 	rows, err := r.tx.QueryContext(ctx, `
-		SELECT id, client_id, status, created_at, updated_at
+		SELECT `+splitColumns+`
 		FROM splits
 		WHERE client_id = ?
 		ORDER BY created_at DESC
@@ -220,8 +492,7 @@ func (r *SplitRepositorySQL) ListByClientID(ctx context.Context, clientID string
 
 	var splits []*domain.Split
 	for rows.Next() {
-		var split domain.Split
-		err := rows.Scan(&split.ID, &split.ClientID, &split.Status, &split.CreatedAt, &split.UpdatedAt)
+		split, err := scanSplit(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning split: %w", err)
 		}
@@ -240,18 +511,48 @@ func (r *SplitRepositorySQL) ListByClientID(ctx context.Context, clientID string
 		}
 		split.UnassignedPages = unassignedPages
 
-		splits = append(splits, &split)
+		splits = append(splits, split)
 	}
 
 	return splits, nil
 }
 
+// ListClientSplits returns a client's splits without their children, newest
+// first; if tag is not empty only the splits with that tag are returned
+func (r *SplitRepositorySQL) ListClientSplits(ctx context.Context, clientID, tag string, limit, offset int) ([]*domain.Split, error) {
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT `+splitColumns+`
+		FROM splits
+		WHERE client_id = ?
+			AND (? = '' OR id IN (SELECT split_id FROM split_tags WHERE tag = ?))
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`, clientID, tag, tag, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing splits: %w", err)
+	}
+	defer rows.Close()
+
+	splits := []*domain.Split{}
+	for rows.Next() {
+		split, err := scanSplit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning split: %w", err)
+		}
+		splits = append(splits, split)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing splits: %w", err)
+	}
+	return splits, nil
+}
+
 // GetSplitIDByDocumentID retrieves the split ID for a given document ID
 func (r *SplitRepositorySQL) GetSplitIDByDocumentID(ctx context.Context, documentID string) (string, error) {
 	var splitID string
 	err := r.tx.QueryRowContext(ctx, "SELECT split_id FROM documents WHERE id = ?", documentID).Scan(&splitID)
 	if err == sql.ErrNoRows {
-		return "", fmt.Errorf("document %v not found", documentID)
+		return "", domain.NewNotFoundError("document", documentID, fmt.Sprintf("document %v not found", documentID), nil)
 	}
 	if err != nil {
 		return "", fmt.Errorf("error getting split ID: %w", err)
@@ -259,47 +560,389 @@ func (r *SplitRepositorySQL) GetSplitIDByDocumentID(ctx context.Context, documen
 	return splitID, nil
 }
 
-// getDocuments retrieves all documents for a split
+// CountPagesByDocument returns the number of pages assigned to a document; it is
+// zero for unknown documents
+func (r *SplitRepositorySQL) CountPagesByDocument(ctx context.Context, documentID string) (int, error) {
+	var count int
+	err := r.tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pages WHERE document_id = ?", documentID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting pages: %w", err)
+	}
+	return count, nil
+}
+
+// GetPage retrieves a single page by ID
+func (r *SplitRepositorySQL) GetPage(ctx context.Context, pageID string) (*domain.Page, error) {
+	var page domain.Page
+	var documentID sql.NullString
+	err := r.tx.QueryRowContext(ctx, `
+		SELECT id, split_id, document_id, page_number, url
+		FROM pages
+		WHERE id = ?
+	`, pageID).Scan(&page.ID, &page.SplitID, &documentID, &page.PageNumber, &page.URL)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting page: %w", err)
+	}
+	if documentID.Valid {
+		page.DocumentID = &documentID.String
+	}
+	return &page, nil
+}
+
+// ListSplitPages returns every page of a split, assigned or not, ordered by page number
+func (r *SplitRepositorySQL) ListSplitPages(ctx context.Context, splitID string) ([]*domain.Page, error) {
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT id, split_id, document_id, page_number, url
+		FROM pages
+		WHERE split_id = ?
+		ORDER BY CAST(page_number AS INTEGER), id
+	`, splitID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing split pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make([]*domain.Page, 0)
+	for rows.Next() {
+		var page domain.Page
+		var documentID sql.NullString
+		if err := rows.Scan(&page.ID, &page.SplitID, &documentID, &page.PageNumber, &page.URL); err != nil {
+			return nil, fmt.Errorf("error scanning split page: %w", err)
+		}
+		if documentID.Valid {
+			page.DocumentID = &documentID.String
+		}
+		pages = append(pages, &page)
+	}
+	return pages, rows.Err()
+}
+
+// ListClients returns distinct clients with their split counts, ordered by client ID
+func (r *SplitRepositorySQL) ListClients(ctx context.Context, limit, offset int) ([]domain.ClientSummary, error) {
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT client_id, COUNT(*)
+		FROM splits
+		GROUP BY client_id
+		ORDER BY client_id
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing clients: %w", err)
+	}
+	defer rows.Close()
+
+	clients := make([]domain.ClientSummary, 0)
+	for rows.Next() {
+		var c domain.ClientSummary
+		if err := rows.Scan(&c.ClientID, &c.SplitCount); err != nil {
+			return nil, fmt.Errorf("error scanning client: %w", err)
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+// ListClientPagesByClassification returns the pages of a client's documents with the
+// given classification across all splits, ordered by split, document and page number
+func (r *SplitRepositorySQL) ListClientPagesByClassification(ctx context.Context, clientID, classification string, limit, offset int) ([]domain.ClientPage, error) {
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT p.id, p.page_number, p.url, d.id, s.id, s.name
+		FROM pages p
+		JOIN documents d ON d.id = p.document_id
+		JOIN splits s ON s.id = d.split_id
+		WHERE s.client_id = ? AND d.classification = ?
+		ORDER BY s.id, d.sort_order, d.start_page_number, d.id, p.sort_order, CAST(p.page_number AS INTEGER)
+		LIMIT ? OFFSET ?
+	`, clientID, classification, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing client pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make([]domain.ClientPage, 0)
+	for rows.Next() {
+		var p domain.ClientPage
+		if err := rows.Scan(&p.PageID, &p.PageNumber, &p.URL, &p.DocumentID, &p.SplitID, &p.SplitName); err != nil {
+			return nil, fmt.Errorf("error scanning client page: %w", err)
+		}
+		pages = append(pages, p)
+	}
+	return pages, rows.Err()
+}
+
+// ReassignPage moves a single page to another document, or unassigns it when newDocID is nil.
+// The page loses any explicit position and falls back to page number order.
+func (r *SplitRepositorySQL) ReassignPage(ctx context.Context, pageID string, newDocID *string) error {
+	res, err := r.tx.ExecContext(ctx, "UPDATE pages SET document_id = ?, sort_order = 0 WHERE id = ?", newDocID, pageID)
+	if err != nil {
+		return fmt.Errorf("error reassigning page: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reassigning page: %w", err)
+	}
+	if n == 0 {
+		return domain.NewNotFoundError("page", pageID, "page not found", nil)
+	}
+	return nil
+}
+
+// TouchSplit sets a split's updated_at and last_modified_by
+func (r *SplitRepositorySQL) TouchSplit(ctx context.Context, splitID string, updatedAt time.Time, modifiedBy string) error {
+	_, err := r.tx.ExecContext(ctx, "UPDATE splits SET updated_at = ?, last_modified_by = ? WHERE id = ?", updatedAt, modifiedBy, splitID)
+	if err != nil {
+		return fmt.Errorf("error touching split: %w", err)
+	}
+	return nil
+}
+
+// AddClassificationChange records a document reclassification in the history
+func (r *SplitRepositorySQL) AddClassificationChange(ctx context.Context, change *domain.ClassificationChange) error {
+	_, err := r.tx.ExecContext(ctx, `
+		INSERT INTO classification_history (id, document_id, old_classification, new_classification, changed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, change.ID, change.DocumentID, change.OldClassification, change.NewClassification, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("error adding classification change: %w", err)
+	}
+	return nil
+}
+
+// UpdateDocumentPageRange persists the start and end page of a document and who
+// changed it last
+func (r *SplitRepositorySQL) UpdateDocumentPageRange(ctx context.Context, doc *domain.Document) error {
+	_, err := r.tx.ExecContext(ctx, `
+		UPDATE documents
+		SET start_page = ?, end_page = ?, start_page_number = ?, last_modified_by = ?
+		WHERE id = ?
+	`, doc.StartPage, doc.EndPage, doc.StartPageNumber(), doc.LastModifiedBy, doc.ID)
+	if err != nil {
+		return fmt.Errorf("error updating document page range: %w", err)
+	}
+	return nil
+}
+
+// GetClientStats computes aggregate counts over all splits of a client in a single query
+func (r *SplitRepositorySQL) GetClientStats(ctx context.Context, clientID string) (*domain.ClientStats, error) {
+	var stats domain.ClientStats
+	err := r.tx.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN s.status = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN s.status = ? THEN 1 ELSE 0 END), 0),
+			(SELECT COUNT(*) FROM documents d JOIN splits ds ON ds.id = d.split_id WHERE ds.client_id = ?),
+			(SELECT COUNT(*) FROM pages p JOIN splits ps ON ps.id = p.split_id WHERE ps.client_id = ?)
+		FROM splits s
+		WHERE s.client_id = ?
+	`, domain.SplitStatusDraft, domain.SplitStatusFinalized, clientID, clientID, clientID).Scan(
+		&stats.TotalSplits, &stats.DraftSplits, &stats.FinalizedSplits, &stats.TotalDocuments, &stats.TotalPages)
+	if err != nil {
+		return nil, fmt.Errorf("error getting client stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// tagSeparator joins a split's tags in splitColumns; NormalizeTag keeps control
+// characters out of tags
+const tagSeparator = "\x1f"
+
+// FindOrphans returns the documents and pages of splits that no longer exist and
+// the pages of existing splits assigned to documents that no longer exist
+func (r *SplitRepositorySQL) FindOrphans(ctx context.Context) (*domain.Orphans, error) {
+	orphans := &domain.Orphans{}
+	var err error
+	orphans.DocumentIDs, err = r.queryIDs(ctx, `
+		SELECT id FROM documents
+		WHERE split_id NOT IN (SELECT id FROM splits)
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error finding orphaned documents: %w", err)
+	}
+	orphans.PageIDs, err = r.queryIDs(ctx, `
+		SELECT id FROM pages
+		WHERE split_id NOT IN (SELECT id FROM splits)
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error finding orphaned pages: %w", err)
+	}
+
+	rows, err := r.tx.QueryContext(ctx, `
+		SELECT id, split_id, document_id, page_number, url
+		FROM pages
+		WHERE document_id IS NOT NULL
+			AND document_id NOT IN (SELECT id FROM documents)
+			AND split_id IN (SELECT id FROM splits)
+		ORDER BY split_id, CAST(page_number AS INTEGER), id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error finding unlinked pages: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var page domain.Page
+		var documentID string
+		if err := rows.Scan(&page.ID, &page.SplitID, &documentID, &page.PageNumber, &page.URL); err != nil {
+			return nil, fmt.Errorf("error scanning page: %w", err)
+		}
+		page.DocumentID = &documentID
+		orphans.UnlinkedPages = append(orphans.UnlinkedPages, &page)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error finding unlinked pages: %w", err)
+	}
+	return orphans, nil
+}
+
+// RepairOrphans deletes the documents and pages of missing splits and unassigns
+// the unlinked pages, which become unassigned pages of their split. The
+// classification history of the deleted and missing documents goes with them.
+func (r *SplitRepositorySQL) RepairOrphans(ctx context.Context, orphans *domain.Orphans) error {
+	for _, id := range orphans.PageIDs {
+		if _, err := r.tx.ExecContext(ctx, "DELETE FROM pages WHERE id = ?", id); err != nil {
+			return fmt.Errorf("error deleting orphaned page: %w", err)
+		}
+	}
+	for _, id := range orphans.DocumentIDs {
+		if _, err := r.tx.ExecContext(ctx, "DELETE FROM documents WHERE id = ?", id); err != nil {
+			return fmt.Errorf("error deleting orphaned document: %w", err)
+		}
+		if _, err := r.tx.ExecContext(ctx, "DELETE FROM classification_history WHERE document_id = ?", id); err != nil {
+			return fmt.Errorf("error deleting classification history of orphaned document: %w", err)
+		}
+	}
+	for _, page := range orphans.UnlinkedPages {
+		if page.DocumentID != nil {
+			if _, err := r.tx.ExecContext(ctx, "DELETE FROM classification_history WHERE document_id = ?", *page.DocumentID); err != nil {
+				return fmt.Errorf("error deleting classification history of missing document: %w", err)
+			}
+		}
+		if err := r.ReassignPage(ctx, page.ID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryIDs returns the first column of the rows of a query
+func (r *SplitRepositorySQL) queryIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// splitColumns are the split columns read by scanSplit, in order, selected FROM
+// splits; the tags come joined by tagSeparator
+const splitColumns = "id, client_id, name, status, created_at, updated_at, last_modified_by, finalized_at, " +
+	"(SELECT group_concat(tag, char(31)) FROM split_tags WHERE split_tags.split_id = splits.id)"
+
+// scanSplit scans a row of splitColumns into a split without its children. The
+// error is returned unwrapped so callers can check for sql.ErrNoRows.
+func scanSplit(row interface{ Scan(dest ...any) error }) (*domain.Split, error) {
+	var split domain.Split
+	var finalizedAt sql.NullTime
+	var tags sql.NullString
+	if err := row.Scan(&split.ID, &split.ClientID, &split.Name, &split.Status, &split.CreatedAt, &split.UpdatedAt, &split.LastModifiedBy, &finalizedAt, &tags); err != nil {
+		return nil, err
+	}
+	if finalizedAt.Valid {
+		split.FinalizedAt = &finalizedAt.Time
+	}
+	if tags.Valid {
+		split.Tags = strings.Split(tags.String, tagSeparator)
+		slices.Sort(split.Tags)
+	}
+	return &split, nil
+}
+
+// documentColumns are the document columns read by scanDocument, in order
+const documentColumns = "id, split_id, name, classification, filename, short_description, start_page, end_page, sort_order, reviewed, reviewed_by, reviewed_at, last_modified_by"
+
+// scanDocument scans a row of documentColumns into a document without its pages
+func scanDocument(rows *sql.Rows) (domain.Document, error) {
+	var doc domain.Document
+	var reviewedAt sql.NullTime
+	err := rows.Scan(&doc.ID, &doc.SplitID, &doc.Name, &doc.Classification, &doc.Filename, &doc.ShortDescription, &doc.StartPage, &doc.EndPage, &doc.SortOrder, &doc.Reviewed, &doc.ReviewedBy, &reviewedAt, &doc.LastModifiedBy)
+	if err != nil {
+		return doc, fmt.Errorf("error scanning document: %w", err)
+	}
+	if reviewedAt.Valid {
+		doc.ReviewedAt = &reviewedAt.Time
+	}
+	return doc, nil
+}
+
+// getDocuments retrieves all documents for a split, ordered by their custom
+// sort order and then by their stored numeric start page number
 func (r *SplitRepositorySQL) getDocuments(ctx context.Context, splitID string) ([]domain.Document, error) {
+	var documents []domain.Document
+	err := r.EachDocument(ctx, splitID, func(doc *domain.Document) error {
+		documents = append(documents, *doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// EachDocument calls fn with each document of a split and its pages, in the order
+// of getDocuments, holding only the current document in memory
+func (r *SplitRepositorySQL) EachDocument(ctx context.Context, splitID string, fn func(*domain.Document) error) error {
 	rows, err := r.tx.QueryContext(ctx, `
-		SELECT id, split_id, name, classification, filename, short_description, start_page, end_page
+		SELECT `+documentColumns+`
 		FROM documents
 		WHERE split_id = ?
-		ORDER BY start_page
+		ORDER BY sort_order, start_page_number, id
 	`, splitID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting documents: %w", err)
+		return fmt.Errorf("error getting documents: %w", err)
 	}
 	defer rows.Close()
 
-	var documents []domain.Document
 	for rows.Next() {
-		var doc domain.Document
-		err := rows.Scan(&doc.ID, &doc.SplitID, &doc.Name, &doc.Classification, &doc.Filename, &doc.ShortDescription, &doc.StartPage, &doc.EndPage)
+		doc, err := scanDocument(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning document: %w", err)
+			return err
 		}
 
 		// Get pages
 		pages, err := r.getPages(ctx, doc.ID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		doc.Pages = pages
 
-		documents = append(documents, doc)
+		if err := fn(&doc); err != nil {
+			return err
+		}
 	}
 
-	return documents, nil
+	return rows.Err()
 }
 
-// getUnassignedPages retrieves all unassigned pages for a split
+// getUnassignedPages retrieves all unassigned pages for a split, ordered by page number
 func (r *SplitRepositorySQL) getUnassignedPages(ctx context.Context, splitID string) ([]*domain.Page, error) {
 	rows, err := r.tx.QueryContext(ctx, `
 		SELECT id, split_id, page_number, url
 		FROM pages
 		WHERE split_id = ? AND document_id IS NULL
-		ORDER BY page_number
+		ORDER BY CAST(page_number AS INTEGER)
 	`, splitID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting unassigned pages: %w", err)
@@ -319,13 +962,13 @@ func (r *SplitRepositorySQL) getUnassignedPages(ctx context.Context, splitID str
 	return pages, nil
 }
 
-// getPages retrieves all pages for a document
+// getPages retrieves all pages for a document, in their explicit order, then by page number
 func (r *SplitRepositorySQL) getPages(ctx context.Context, documentID string) ([]*domain.Page, error) {
 	rows, err := r.tx.QueryContext(ctx, `
-		SELECT id, split_id, page_number, url
+		SELECT id, split_id, page_number, url, sort_order
 		FROM pages
 		WHERE document_id = ?
-		ORDER BY page_number
+		ORDER BY sort_order, CAST(page_number AS INTEGER)
 	`, documentID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting pages: %w", err)
@@ -335,7 +978,7 @@ func (r *SplitRepositorySQL) getPages(ctx context.Context, documentID string) ([
 	var pages []*domain.Page
 	for rows.Next() {
 		var page domain.Page
-		err := rows.Scan(&page.ID, &page.SplitID, &page.PageNumber, &page.URL)
+		err := rows.Scan(&page.ID, &page.SplitID, &page.PageNumber, &page.URL, &page.SortOrder)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning page: %w", err)
 		}
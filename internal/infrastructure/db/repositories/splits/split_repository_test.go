@@ -2,8 +2,11 @@ package splits
 
 import (
 	"accounting/internal/domain"
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
+	"log"
 	"testing"
 	"time"
 
@@ -21,9 +24,12 @@ func setupTestDB(t *testing.T) (*sql.DB, *sql.Tx) {
 		CREATE TABLE splits (
 			id TEXT PRIMARY KEY,
 			client_id TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
 			status TEXT NOT NULL,
 			created_at TIMESTAMP NOT NULL,
-			updated_at TIMESTAMP NOT NULL
+			updated_at TIMESTAMP NOT NULL,
+			last_modified_by TEXT NOT NULL DEFAULT '',
+			finalized_at TIMESTAMP
 		);
 		CREATE TABLE documents (
 			id TEXT PRIMARY KEY,
@@ -34,6 +40,12 @@ func setupTestDB(t *testing.T) (*sql.DB, *sql.Tx) {
 			short_description TEXT,
 			start_page TEXT,
 			end_page TEXT,
+			start_page_number INTEGER NOT NULL DEFAULT 0,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			reviewed INTEGER NOT NULL DEFAULT 0,
+			reviewed_by TEXT NOT NULL DEFAULT '',
+			reviewed_at TIMESTAMP,
+			last_modified_by TEXT NOT NULL DEFAULT '',
 			FOREIGN KEY (split_id) REFERENCES splits(id)
 		);
 		CREATE TABLE pages (
@@ -42,9 +54,22 @@ func setupTestDB(t *testing.T) (*sql.DB, *sql.Tx) {
 			document_id TEXT,
 			page_number TEXT NOT NULL,
 			url TEXT NOT NULL,
+			sort_order INTEGER NOT NULL DEFAULT 0,
 			FOREIGN KEY (split_id) REFERENCES splits(id),
 			FOREIGN KEY (document_id) REFERENCES documents(id)
 		);
+		CREATE TABLE split_tags (
+			split_id TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (split_id, tag)
+		);
+		CREATE TABLE classification_history (
+			id TEXT PRIMARY KEY,
+			document_id TEXT NOT NULL,
+			old_classification TEXT NOT NULL,
+			new_classification TEXT NOT NULL,
+			changed_at TIMESTAMP NOT NULL
+		);
 	`)
 	require.NoError(t, err)
 
@@ -136,6 +161,199 @@ func TestSplitRepositorySQL_Save(t *testing.T) {
 	assert.Len(t, savedSplit.Documents, 1)
 	assert.Equal(t, split.Documents[0].ID, savedSplit.Documents[0].ID)
 	assert.Len(t, savedSplit.Documents[0].Pages, 1)
+	assert.Nil(t, savedSplit.FinalizedAt)
+	assert.False(t, savedSplit.Documents[0].Reviewed)
+
+	// The review round-trips
+	require.NoError(t, split.ReviewDocument("doc1", true, "alice", now))
+	require.NoError(t, repo.Save(ctx, split))
+	savedSplit, err = repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	assert.True(t, savedSplit.Documents[0].Reviewed)
+	assert.Equal(t, "alice", savedSplit.Documents[0].ReviewedBy)
+	require.NotNil(t, savedSplit.Documents[0].ReviewedAt)
+	assert.True(t, now.Equal(*savedSplit.Documents[0].ReviewedAt))
+
+	// The name round-trips
+	require.NoError(t, split.Rename("Smith household"))
+	require.NoError(t, repo.Save(ctx, split))
+	savedSplit, err = repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "Smith household", savedSplit.Name)
+
+	// The finalization time round-trips
+	require.NoError(t, split.Finalize(now))
+	require.NoError(t, repo.Save(ctx, split))
+	savedSplit, err = repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SplitStatusFinalized, savedSplit.Status)
+	require.NotNil(t, savedSplit.FinalizedAt)
+	assert.True(t, now.Equal(*savedSplit.FinalizedAt))
+}
+
+func TestSplitRepositorySQL_SaveDeletesOnlyRemovedPage(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	repo := NewSplitRepositorySQL(tx)
+	repo.SetVerifySaves(true)
+	ctx := context.Background()
+
+	now := time.Now()
+	page := func(id string, docID *string, n int) *domain.Page {
+		return &domain.Page{ID: id, SplitID: "test-split", DocumentID: docID, PageNumber: n, URL: id + ".png"}
+	}
+	split := &domain.Split{
+		ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "doc1", Classification: "W-2", Filename: "doc1.pdf",
+				Pages: []*domain.Page{page("page1", stringPtr("doc1"), 1), page("page2", stringPtr("doc1"), 2)}},
+			{ID: "doc2", SplitID: "test-split", Name: "doc2", Classification: "W-2", Filename: "doc2.pdf",
+				Pages: []*domain.Page{page("page3", stringPtr("doc2"), 3)}},
+		},
+		UnassignedPages: []*domain.Page{page("page4", nil, 4)},
+	}
+	require.NoError(t, repo.Save(ctx, split))
+
+	// Drop page2 from doc1 and save again
+	split.Documents[0].Pages = split.Documents[0].Pages[:1]
+	require.NoError(t, repo.Save(ctx, split))
+
+	var pageIDs []string
+	rows, err := tx.Query(`SELECT id FROM pages ORDER BY id`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		pageIDs = append(pageIDs, id)
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	assert.Equal(t, []string{"page1", "page3", "page4"}, pageIDs)
+	assert.Empty(t, logs.String(), "consistent saves log nothing")
+
+	// A page the aggregate does not know about is reported
+	_, err = tx.Exec(`INSERT INTO pages (id, split_id, document_id, page_number, url) VALUES ('stray', 'test-split', 'doc2', 5, 'stray.png')`)
+	require.NoError(t, err)
+	mismatches, err := repo.pageCountMismatches(ctx, split)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"document: doc2, expected pages: 1, saved pages: 2"}, mismatches)
+}
+
+func TestSplitRepositorySQL_SaveRejectsPageInTwoPlaces(t *testing.T) {
+	now := time.Now()
+	newPage := func(docID *string) *domain.Page {
+		return &domain.Page{ID: "page1", SplitID: "test-split", DocumentID: docID, PageNumber: 1, URL: "page_1.png"}
+	}
+	newDoc := func(id string, pages ...*domain.Page) domain.Document {
+		return domain.Document{ID: id, SplitID: "test-split", Name: id, Classification: "W-2", Filename: id + ".pdf", Pages: pages}
+	}
+
+	tests := []struct {
+		name  string
+		split *domain.Split
+	}{
+		{
+			name: "two documents",
+			split: &domain.Split{
+				ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+				Documents: []domain.Document{
+					newDoc("doc1", newPage(stringPtr("doc1"))),
+					newDoc("doc2", newPage(stringPtr("doc2"))),
+				},
+			},
+		},
+		{
+			name: "document and unassigned",
+			split: &domain.Split{
+				ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+				Documents:       []domain.Document{newDoc("doc1", newPage(stringPtr("doc1")))},
+				UnassignedPages: []*domain.Page{newPage(nil)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, tx := setupTestDB(t)
+			defer db.Close()
+			defer tx.Rollback()
+
+			repo := NewSplitRepositorySQL(tx)
+			err := repo.Save(context.Background(), tt.split)
+			var domainErr *domain.DomainError
+			require.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, domain.DomainErrorInternal, domainErr.Kind)
+			assert.Contains(t, err.Error(), "page1")
+
+			// Nothing was written
+			var n int
+			require.NoError(t, tx.QueryRow(`SELECT COUNT(*) FROM splits`).Scan(&n))
+			assert.Equal(t, 0, n)
+		})
+	}
+}
+
+func TestSplitRepositorySQL_GetMany(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// Nothing requested, nothing loaded
+	splits, err := repo.GetMany(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, splits)
+
+	now := time.Now()
+	page := func(id, splitID string, docID *string, n int) *domain.Page {
+		return &domain.Page{ID: id, SplitID: splitID, DocumentID: docID, PageNumber: n, URL: fmt.Sprintf("page_%d.png", n)}
+	}
+	require.NoError(t, repo.Save(ctx, &domain.Split{
+		ID: "split-a", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc-a2", SplitID: "split-a", Name: "Second", SortOrder: 2, Pages: []*domain.Page{page("a3", "split-a", stringPtr("doc-a2"), 3)}},
+			{ID: "doc-a1", SplitID: "split-a", Name: "First", SortOrder: 1, Pages: []*domain.Page{
+				page("a2", "split-a", stringPtr("doc-a1"), 2),
+				page("a1", "split-a", stringPtr("doc-a1"), 1),
+			}},
+		},
+		UnassignedPages: []*domain.Page{page("a4", "split-a", nil, 4)},
+	}))
+	require.NoError(t, repo.Save(ctx, &domain.Split{
+		ID: "split-b", ClientID: "client2", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		UnassignedPages: []*domain.Page{page("b1", "split-b", nil, 1)},
+	}))
+	require.NoError(t, repo.Save(ctx, &domain.Split{
+		ID: "split-c", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+	}))
+
+	// Splits come back in request order, once each, without the missing ID
+	splits, err = repo.GetMany(ctx, []string{"split-b", "missing", "split-a", "split-b"})
+	require.NoError(t, err)
+	require.Len(t, splits, 2)
+	assert.Equal(t, "split-b", splits[0].ID)
+	assert.Equal(t, "split-a", splits[1].ID)
+
+	// Each split is loaded exactly as Get loads it
+	for _, split := range splits {
+		want, err := repo.Get(ctx, split.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, split)
+	}
+	assert.Equal(t, "doc-a1", splits[1].Documents[0].ID)
+	assert.Equal(t, "a1", splits[1].Documents[0].Pages[0].ID)
+
+	splits, err = repo.GetMany(ctx, []string{"missing"})
+	require.NoError(t, err)
+	assert.Empty(t, splits)
 }
 
 func TestSplitRepositorySQL_Delete(t *testing.T) {
@@ -220,10 +438,402 @@ func TestSplitRepositorySQL_GetSplitIDByDocumentID(t *testing.T) {
 
 	// Test non-existent document
 	_, err = repo.GetSplitIDByDocumentID(ctx, "non-existent")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitRepositorySQL_CountPagesByDocument(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	_, err := tx.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url)
+		VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, NULL, ?, ?)
+	`, "page1", "test-split", "test-doc", 1, "page_1.png",
+		"page2", "test-split", "test-doc", 2, "page_2.png",
+		"page3", "test-split", "other-doc", 3, "page_3.png",
+		"page4", "test-split", 4, "page_4.png")
+	require.NoError(t, err)
+
+	count, err := repo.CountPagesByDocument(ctx, "test-doc")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = repo.CountPagesByDocument(ctx, "non-existent")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestSplitRepositorySQL_GetPage(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// Insert test data
+	now := time.Now()
+	_, err := tx.Exec(`
+		INSERT INTO splits (id, client_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, "test-split", "test-client", domain.SplitStatusDraft, now, now)
+	require.NoError(t, err)
+
+	_, err = tx.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url)
+		VALUES (?, ?, ?, ?, ?), (?, ?, NULL, ?, ?)
+	`, "page1", "test-split", "test-doc", 1, "page_1.png",
+		"page2", "test-split", 2, "page_2.png")
+	require.NoError(t, err)
+
+	// Test assigned page
+	page, err := repo.GetPage(ctx, "page1")
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Equal(t, "test-split", page.SplitID)
+	assert.Equal(t, "page_1.png", page.URL)
+	require.NotNil(t, page.DocumentID)
+	assert.Equal(t, "test-doc", *page.DocumentID)
+
+	// Test unassigned page
+	page, err = repo.GetPage(ctx, "page2")
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Nil(t, page.DocumentID)
+
+	// Test non-existent page
+	page, err = repo.GetPage(ctx, "non-existent")
+	require.NoError(t, err)
+	assert.Nil(t, page)
 }
 
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestSplitRepositorySQL_GetClientStats(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// Insert test data
+	now := time.Now()
+	_, err := tx.Exec(`
+		INSERT INTO splits (id, client_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)
+	`, "split1", "client1", domain.SplitStatusDraft, now, now,
+		"split2", "client1", domain.SplitStatusFinalized, now, now,
+		"split3", "client2", domain.SplitStatusDraft, now, now)
+	require.NoError(t, err)
+
+	_, err = tx.Exec(`
+		INSERT INTO documents (id, split_id, name)
+		VALUES (?, ?, ?), (?, ?, ?), (?, ?, ?)
+	`, "doc1", "split1", "Doc 1", "doc2", "split2", "Doc 2", "doc3", "split3", "Doc 3")
+	require.NoError(t, err)
+
+	_, err = tx.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url)
+		VALUES (?, ?, ?, ?, ?), (?, ?, NULL, ?, ?), (?, ?, ?, ?, ?)
+	`, "page1", "split1", "doc1", 1, "page_1.png",
+		"page2", "split1", 2, "page_2.png",
+		"page3", "split3", "doc3", 1, "page_3.png")
+	require.NoError(t, err)
+
+	stats, err := repo.GetClientStats(ctx, "client1")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ClientStats{
+		TotalSplits:     2,
+		DraftSplits:     1,
+		FinalizedSplits: 1,
+		TotalDocuments:  2,
+		TotalPages:      2,
+	}, stats)
+
+	// Test client without splits
+	stats, err = repo.GetClientStats(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ClientStats{}, stats)
+}
+
+func TestSplitRepositorySQL_ReassignPage(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// Insert test data
+	now := time.Now()
+	_, err := tx.Exec(`
+		INSERT INTO splits (id, client_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, "test-split", "test-client", domain.SplitStatusDraft, now, now)
+	require.NoError(t, err)
+
+	_, err = tx.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url)
+		VALUES (?, ?, ?, ?, ?)
+	`, "page1", "test-split", "doc1", 1, "page_1.png")
+	require.NoError(t, err)
+
+	// Move to another document
+	docID := "doc2"
+	require.NoError(t, repo.ReassignPage(ctx, "page1", &docID))
+	page, err := repo.GetPage(ctx, "page1")
+	require.NoError(t, err)
+	require.NotNil(t, page.DocumentID)
+	assert.Equal(t, "doc2", *page.DocumentID)
+
+	// Unassign
+	require.NoError(t, repo.ReassignPage(ctx, "page1", nil))
+	page, err = repo.GetPage(ctx, "page1")
+	require.NoError(t, err)
+	assert.Nil(t, page.DocumentID)
+
+	// Unknown page
+	err = repo.ReassignPage(ctx, "non-existent", &docID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitRepositorySQL_LastModifiedBy(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	now := time.Now()
+	docID := "doc1"
+	doc := domain.Document{ID: docID, SplitID: "test-split", Name: "W2", LastModifiedBy: "alice", Pages: []*domain.Page{
+		{ID: "page1", SplitID: "test-split", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+	}}
+	require.NoError(t, repo.Save(ctx, &domain.Split{
+		ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		LastModifiedBy: "alice", Documents: []domain.Document{doc},
+	}))
+
+	split, err := repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", split.LastModifiedBy)
+	require.Len(t, split.Documents, 1)
+	assert.Equal(t, "alice", split.Documents[0].LastModifiedBy)
+
+	// The targeted updates record the editor too
+	require.NoError(t, repo.TouchSplit(ctx, "test-split", now.Add(time.Second), "bob"))
+	doc.LastModifiedBy = "bob"
+	require.NoError(t, repo.UpdateDocumentPageRange(ctx, &doc))
+	split, err = repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", split.LastModifiedBy)
+	assert.Equal(t, "bob", split.Documents[0].LastModifiedBy)
+}
+
+func TestSplitRepositorySQL_ListClients(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// No splits yields an empty, non-nil list
+	clients, err := repo.ListClients(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, clients)
+	assert.Empty(t, clients)
+
+	// Insert test data
+	now := time.Now()
+	_, err = tx.Exec(`
+		INSERT INTO splits (id, client_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)
+	`, "split1", "client2", domain.SplitStatusDraft, now, now,
+		"split2", "client1", domain.SplitStatusDraft, now, now,
+		"split3", "client2", domain.SplitStatusFinalized, now, now)
+	require.NoError(t, err)
+
+	clients, err = repo.ListClients(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ClientSummary{
+		{ClientID: "client1", SplitCount: 1},
+		{ClientID: "client2", SplitCount: 2},
+	}, clients)
+
+	// Pagination
+	clients, err = repo.ListClients(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ClientSummary{{ClientID: "client2", SplitCount: 2}}, clients)
+}
+
+func TestSplitRepositorySQL_Tags(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i, split := range []*domain.Split{
+		{ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, Tags: []string{"tax-2024", "urgent"}},
+		{ID: "split2", ClientID: "client1", Status: domain.SplitStatusDraft, Tags: []string{"tax-2024"}},
+		{ID: "split3", ClientID: "client1", Status: domain.SplitStatusDraft},
+		{ID: "split4", ClientID: "client2", Status: domain.SplitStatusDraft, Tags: []string{"urgent"}},
+	} {
+		split.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		split.UpdatedAt = split.CreatedAt
+		require.NoError(t, repo.Save(ctx, split))
+	}
+
+	// Tags round-trip, sorted
+	split, err := repo.Get(ctx, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tax-2024", "urgent"}, split.Tags)
+	split, err = repo.Get(ctx, "split3")
+	require.NoError(t, err)
+	assert.Empty(t, split.Tags)
+
+	// Saving replaces the tags
+	split.Tags = []string{"review"}
+	require.NoError(t, repo.Save(ctx, split))
+	many, err := repo.GetMany(ctx, []string{"split3", "split1"})
+	require.NoError(t, err)
+	require.Len(t, many, 2)
+	assert.Equal(t, []string{"review"}, many[0].Tags)
+	assert.Equal(t, []string{"tax-2024", "urgent"}, many[1].Tags)
+
+	splitIDs := func(splits []*domain.Split) []string {
+		ids := make([]string, len(splits))
+		for i, s := range splits {
+			ids[i] = s.ID
+		}
+		return ids
+	}
+
+	// Listing is newest first and filters by client and tag
+	splits, err := repo.ListClientSplits(ctx, "client1", "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"split3", "split2", "split1"}, splitIDs(splits))
+	splits, err = repo.ListClientSplits(ctx, "client1", "tax-2024", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"split2", "split1"}, splitIDs(splits))
+	splits, err = repo.ListClientSplits(ctx, "client1", "urgent", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"split1"}, splitIDs(splits))
+	assert.Equal(t, []string{"tax-2024", "urgent"}, splits[0].Tags)
+	splits, err = repo.ListClientSplits(ctx, "client1", "tax-2024", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"split1"}, splitIDs(splits))
+	splits, err = repo.ListClientSplits(ctx, "client1", "missing", 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, splits)
+	assert.Empty(t, splits)
+
+	// Deleting a split deletes its tags
+	require.NoError(t, repo.Delete(ctx, "split1"))
+	var count int
+	require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM split_tags WHERE split_id = ?", "split1").Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestSplitRepositorySQL_ListClientPagesByClassification(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := tx.Exec(`
+		INSERT INTO splits (id, client_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)
+	`, "split1", "client1", domain.SplitStatusDraft, now, now,
+		"split2", "client1", domain.SplitStatusFinalized, now, now,
+		"split3", "client2", domain.SplitStatusDraft, now, now)
+	require.NoError(t, err)
+	_, err = tx.Exec(`
+		INSERT INTO documents (id, split_id, name, classification)
+		VALUES ('doc1', 'split1', 'a', '1099'), ('doc2', 'split1', 'b', 'W-2'),
+		       ('doc3', 'split2', 'c', '1099'), ('doc4', 'split3', 'd', '1099')
+	`)
+	require.NoError(t, err)
+	_, err = tx.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url)
+		VALUES ('p10', 'split1', 'doc1', '10', 'page_10.png'), ('p2', 'split1', 'doc1', '2', 'page_2.png'),
+		       ('p3', 'split1', 'doc2', '3', 'page_3.png'), ('p1', 'split2', 'doc3', '1', 'page_1.png'),
+		       ('p4', 'split3', 'doc4', '4', 'page_4.png'), ('p5', 'split1', NULL, '5', 'page_5.png')
+	`)
+	require.NoError(t, err)
+
+	pages, err := repo.ListClientPagesByClassification(ctx, "client1", "1099", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ClientPage{
+		{PageID: "p2", PageNumber: 2, URL: "page_2.png", DocumentID: "doc1", SplitID: "split1"},
+		{PageID: "p10", PageNumber: 10, URL: "page_10.png", DocumentID: "doc1", SplitID: "split1"},
+		{PageID: "p1", PageNumber: 1, URL: "page_1.png", DocumentID: "doc3", SplitID: "split2"},
+	}, pages)
+
+	// Pagination
+	pages, err = repo.ListClientPagesByClassification(ctx, "client1", "1099", 1, 2)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, "p1", pages[0].PageID)
+
+	// No match yields an empty, non-nil list
+	pages, err = repo.ListClientPagesByClassification(ctx, "client1", "Invoice", 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, pages)
+	assert.Empty(t, pages)
+}
+
+func TestSplitRepositorySQL_DocumentsOrderedByNumericStartPage(t *testing.T) {
+	db, tx := setupTestDB(t)
+	defer db.Close()
+	defer tx.Rollback()
+
+	repo := NewSplitRepositorySQL(tx)
+	ctx := context.Background()
+
+	// 11 pages split into documents starting at pages 1, 2 and 10; ordering the
+	// start_page URLs lexicographically would put page_10 before page_2
+	now := time.Now()
+	split := &domain.Split{ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now}
+	pages := make([]*domain.Page, 11)
+	for i := range pages {
+		pages[i] = &domain.Page{ID: fmt.Sprintf("page%d", i+1), SplitID: "test-split", PageNumber: i + 1, URL: fmt.Sprintf("page_%d.png", i+1)}
+	}
+	for _, d := range []struct {
+		id    string
+		pages []*domain.Page
+	}{
+		{"doc-c", pages[9:]},
+		{"doc-b", pages[1:9]},
+		{"doc-a", pages[:1]},
+	} {
+		doc, err := domain.NewDocument(d.id, "test-split", d.id, "W-2", d.id+".pdf", "", d.pages)
+		require.NoError(t, err)
+		require.NoError(t, split.AddDocument(doc))
+	}
+	require.NoError(t, repo.Save(ctx, split))
+
+	loaded, err := repo.Get(ctx, "test-split")
+	require.NoError(t, err)
+	ids := make([]string, len(loaded.Documents))
+	for i, doc := range loaded.Documents {
+		ids[i] = doc.ID
+	}
+	assert.Equal(t, []string{"doc-a", "doc-b", "doc-c"}, ids)
+	assert.Equal(t, "page_10.png", loaded.Documents[2].StartPage)
+}
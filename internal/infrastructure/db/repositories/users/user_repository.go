@@ -0,0 +1,65 @@
+package users
+
+import (
+	"accounting/internal/auth"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Assert that *UserRepositorySQL implements auth.UserStore interface
+var _ auth.UserStore = (*UserRepositorySQL)(nil)
+
+// UserRepositorySQL implements auth.UserStore using SQLite
+type UserRepositorySQL struct {
+	db *sql.DB
+}
+
+// NewUserRepositorySQL creates a new SQLite-based user repository
+func NewUserRepositorySQL(db *sql.DB) *UserRepositorySQL {
+	return &UserRepositorySQL{db: db}
+}
+
+// List retrieves all stored users
+func (r *UserRepositorySQL) List(ctx context.Context) ([]auth.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT username, password_hash
+		FROM users
+		ORDER BY username
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []auth.User
+	for rows.Next() {
+		var user auth.User
+		if err := rows.Scan(&user.Username, &user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("error scanning user: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// Create stores a new user with a hashed password
+func (r *UserRepositorySQL) Create(ctx context.Context, user auth.User) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (username, password_hash, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(username) DO NOTHING
+	`, user.Username, user.PasswordHash, time.Now())
+	if err != nil {
+		return fmt.Errorf("error creating user: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error creating user: %w", err)
+	}
+	if n == 0 {
+		return auth.ErrUserExists
+	}
+	return nil
+}
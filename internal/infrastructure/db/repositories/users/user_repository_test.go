@@ -0,0 +1,57 @@
+package users
+
+import (
+	"accounting/internal/auth"
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE users (
+			username TEXT PRIMARY KEY,
+			password_hash TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestUserRepositorySQL_CreateAndList(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepositorySQL(db)
+	ctx := context.Background()
+
+	// Test empty list
+	users, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	// Create users
+	require.NoError(t, repo.Create(ctx, auth.User{Username: "bob", PasswordHash: "hash-b"}))
+	require.NoError(t, repo.Create(ctx, auth.User{Username: "alice", PasswordHash: "hash-a"}))
+
+	// Test duplicate username
+	err = repo.Create(ctx, auth.User{Username: "bob", PasswordHash: "other"})
+	assert.ErrorIs(t, err, auth.ErrUserExists)
+
+	// Verify stored users
+	users, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []auth.User{
+		{Username: "alice", PasswordHash: "hash-a"},
+		{Username: "bob", PasswordHash: "hash-b"},
+	}, users)
+}
@@ -0,0 +1,118 @@
+package uow
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// Registry begins units of work and tracks the ones still open, so that a
+// transaction abandoned by a bug (never committed nor rolled back) cannot hold
+// its connection and SQLite write lock forever.
+type Registry struct {
+	db     *sql.DB
+	maxAge time.Duration
+	// verifySaves is passed on to the units of work begun
+	verifySaves bool
+
+	mu   sync.Mutex
+	open map[*UnitOfWorkSQL]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRegistry creates a registry that considers transactions older than maxAge abandoned
+func NewRegistry(db *sql.DB, maxAge time.Duration) *Registry {
+	return &Registry{
+		db:     db,
+		maxAge: maxAge,
+		open:   make(map[*UnitOfWorkSQL]time.Time),
+	}
+}
+
+// SetVerifySaves enables the split repository's post-save page count check in the
+// units of work begun from now on
+func (r *Registry) SetVerifySaves(verify bool) {
+	r.verifySaves = verify
+}
+
+// BeginTx starts a tracked unit of work with the given transaction options
+func (r *Registry) BeginTx(ctx context.Context, opts *sql.TxOptions) (*UnitOfWorkSQL, error) {
+	u := NewUnitOfWorkSQL(r.db)
+	u.verifySaves = r.verifySaves
+	if err := u.BeginTx(ctx, opts); err != nil {
+		return nil, err
+	}
+	u.onDone = func() { r.forget(u) }
+
+	r.mu.Lock()
+	r.open[u] = time.Now()
+	r.mu.Unlock()
+	return u, nil
+}
+
+func (r *Registry) forget(u *UnitOfWorkSQL) {
+	r.mu.Lock()
+	delete(r.open, u)
+	r.mu.Unlock()
+}
+
+// Open returns the number of units of work not yet committed or rolled back
+func (r *Registry) Open() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.open)
+}
+
+// Sweep rolls back every unit of work begun more than maxAge before now and
+// returns how many were rolled back
+func (r *Registry) Sweep(now time.Time) int {
+	r.mu.Lock()
+	var stale []*UnitOfWorkSQL
+	for u, started := range r.open {
+		if now.Sub(started) > r.maxAge {
+			stale = append(stale, u)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, u := range stale {
+		if err := u.Rollback(context.Background()); err != nil && err != sql.ErrTxDone {
+			log.Printf("failed to roll back abandoned transaction: %v", err)
+			continue
+		}
+		log.Printf("rolled back abandoned transaction, max_age: %v", r.maxAge)
+	}
+	return len(stale)
+}
+
+// Start sweeps abandoned transactions in the background every interval
+func (r *Registry) Start(interval time.Duration) {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case now := <-ticker.C:
+				r.Sweep(now)
+			}
+		}
+	}()
+}
+
+// Stop stops the background sweep
+func (r *Registry) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
@@ -0,0 +1,87 @@
+package uow
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	// A single connection makes a leaked transaction block every later one
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`CREATE TABLE items (id TEXT PRIMARY KEY)`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestRegistry_TracksOpenUnitsOfWork(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	registry := NewRegistry(db, time.Minute)
+	ctx := context.Background()
+
+	u, err := registry.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, registry.Open())
+	require.NoError(t, u.Commit(ctx))
+	assert.Equal(t, 0, registry.Open())
+
+	u, err = registry.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, u.Rollback(ctx))
+	assert.Equal(t, 0, registry.Open())
+}
+
+func TestRegistry_SweepRollsBackAbandoned(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	registry := NewRegistry(db, time.Minute)
+	ctx := context.Background()
+
+	// Abandon a transaction holding a write
+	abandoned, err := registry.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = abandoned.tx.Exec(`INSERT INTO items (id) VALUES ('leaked')`)
+	require.NoError(t, err)
+
+	// Young transactions are left alone
+	assert.Equal(t, 0, registry.Sweep(time.Now()))
+	assert.Equal(t, 1, registry.Open())
+
+	assert.Equal(t, 1, registry.Sweep(time.Now().Add(2*time.Minute)))
+	assert.Equal(t, 0, registry.Open())
+
+	// The connection is free again and the abandoned write was discarded
+	u, err := registry.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer u.Rollback(ctx)
+	var count int
+	require.NoError(t, u.tx.QueryRow(`SELECT COUNT(*) FROM items`).Scan(&count))
+	assert.Equal(t, 0, count)
+
+	// A late Rollback by the owner is harmless
+	assert.ErrorIs(t, abandoned.Rollback(ctx), sql.ErrTxDone)
+}
+
+func TestRegistry_StartStop(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	registry := NewRegistry(db, time.Millisecond)
+	_, err := registry.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	registry.Start(5 * time.Millisecond)
+	assert.Eventually(t, func() bool { return registry.Open() == 0 }, time.Second, 5*time.Millisecond)
+	registry.Stop()
+}
@@ -2,15 +2,30 @@ package uow
 
 import (
 	"accounting/internal/domain"
+	"accounting/internal/infrastructure/db/repositories/audit"
+	"accounting/internal/infrastructure/db/repositories/locks"
+	"accounting/internal/infrastructure/db/repositories/outbox"
 	"accounting/internal/infrastructure/db/repositories/splits"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
 )
 
+// savepointName restricts savepoint names to identifiers, since they are part of the SQL
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // UnitOfWorkSQL implements domain.UnitOfWork using SQLite
 type UnitOfWorkSQL struct {
 	db *sql.DB
 	tx *sql.Tx
+	// conn is the dedicated connection of a read-only transaction
+	conn *sql.Conn
+	// onDone is called once the transaction is committed or rolled back
+	onDone func()
+	// verifySaves enables the split repository's post-save integrity check
+	verifySaves bool
 }
 
 // NewUnitOfWorkSQL creates a new SQLite-based unit of work
@@ -18,13 +33,36 @@ func NewUnitOfWorkSQL(db *sql.DB) *UnitOfWorkSQL {
 	return &UnitOfWorkSQL{db: db}
 }
 
-// Begin starts a new transaction
-func (u *UnitOfWorkSQL) Begin() error {
-	tx, err := u.db.Begin()
+// BeginTx starts a new transaction with the given options. The SQLite driver
+// ignores sql.TxOptions: transactions are always serializable, so any isolation
+// level is satisfied, and ReadOnly is enforced here by running the transaction
+// on a dedicated connection with PRAGMA query_only set.
+func (u *UnitOfWorkSQL) BeginTx(ctx context.Context, opts *sql.TxOptions) error {
+	if opts == nil || !opts.ReadOnly {
+		tx, err := u.db.BeginTx(ctx, opts)
+		if err != nil {
+			return err
+		}
+		u.tx = tx
+		return nil
+	}
+
+	conn, err := u.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		conn.Close()
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, opts)
 	if err != nil {
+		u.conn = conn
+		u.releaseConn()
 		return err
 	}
 	u.tx = tx
+	u.conn = conn
 	return nil
 }
 
@@ -33,6 +71,7 @@ func (u *UnitOfWorkSQL) Commit(ctx context.Context) error {
 	if u.tx == nil {
 		return nil
 	}
+	defer u.done()
 	return u.tx.Commit()
 }
 
@@ -41,10 +80,80 @@ func (u *UnitOfWorkSQL) Rollback(ctx context.Context) error {
 	if u.tx == nil {
 		return nil
 	}
+	defer u.done()
 	return u.tx.Rollback()
 }
 
+// Savepoint marks a point in the transaction that RollbackTo can return to, so a
+// batch can undo one failed item and still commit the others
+func (u *UnitOfWorkSQL) Savepoint(ctx context.Context, name string) error {
+	return u.execSavepoint(ctx, "SAVEPOINT ", name)
+}
+
+// RollbackTo undoes the changes made since the named savepoint, leaving the rest of
+// the transaction intact
+func (u *UnitOfWorkSQL) RollbackTo(ctx context.Context, name string) error {
+	if err := u.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT ", name); err != nil {
+		return err
+	}
+	// SQLite keeps the savepoint after rolling back to it
+	return u.Release(ctx, name)
+}
+
+// Release forgets the named savepoint, keeping the changes made since it
+func (u *UnitOfWorkSQL) Release(ctx context.Context, name string) error {
+	return u.execSavepoint(ctx, "RELEASE SAVEPOINT ", name)
+}
+
+func (u *UnitOfWorkSQL) execSavepoint(ctx context.Context, stmt, name string) error {
+	if u.tx == nil {
+		return fmt.Errorf("savepoint %s: no transaction", name)
+	}
+	if !savepointName.MatchString(name) {
+		return fmt.Errorf("invalid savepoint name %q", name)
+	}
+	_, err := u.tx.ExecContext(ctx, stmt+name)
+	return err
+}
+
+func (u *UnitOfWorkSQL) done() {
+	u.releaseConn()
+	if u.onDone != nil {
+		u.onDone()
+	}
+}
+
+// releaseConn clears query_only and returns the dedicated connection to the pool
+func (u *UnitOfWorkSQL) releaseConn() {
+	if u.conn == nil {
+		return
+	}
+	if _, err := u.conn.ExecContext(context.Background(), "PRAGMA query_only = OFF"); err != nil {
+		// Don't hand a read-only connection back to the pool
+		u.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	u.conn.Close()
+	u.conn = nil
+}
+
 // SplitRepository returns a new split repository instance
 func (u *UnitOfWorkSQL) SplitRepository() domain.SplitRepository {
-	return splits.NewSplitRepositorySQL(u.tx)
+	repo := splits.NewSplitRepositorySQL(u.tx)
+	repo.SetVerifySaves(u.verifySaves)
+	return repo
+}
+
+// OutboxRepository returns a new outbox repository bound to the transaction
+func (u *UnitOfWorkSQL) OutboxRepository() domain.OutboxRepository {
+	return outbox.NewOutboxRepositorySQL(u.tx)
+}
+
+// AuditRepository returns a new audit repository bound to the transaction
+func (u *UnitOfWorkSQL) AuditRepository() domain.AuditRepository {
+	return audit.NewAuditRepositorySQL(u.tx)
+}
+
+// LockRepository returns a new split lock repository bound to the transaction
+func (u *UnitOfWorkSQL) LockRepository() domain.LockRepository {
+	return locks.NewLockRepositorySQL(u.tx)
 }
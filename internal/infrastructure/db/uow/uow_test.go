@@ -0,0 +1,105 @@
+package uow
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWorkSQL_ReadOnlyRejectsWrites(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO items (id) VALUES ('existing')`)
+	require.NoError(t, err)
+
+	u := NewUnitOfWorkSQL(db)
+	require.NoError(t, u.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}))
+
+	var n int
+	require.NoError(t, u.tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`).Scan(&n))
+	assert.Equal(t, 1, n)
+
+	_, err = u.tx.ExecContext(ctx, `INSERT INTO items (id) VALUES ('new')`)
+	assert.Error(t, err)
+	require.NoError(t, u.Rollback(ctx))
+
+	// The connection is writable again once the read-only unit of work is done
+	u = NewUnitOfWorkSQL(db)
+	require.NoError(t, u.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}))
+	_, err = u.tx.ExecContext(ctx, `INSERT INTO items (id) VALUES ('new')`)
+	require.NoError(t, err)
+	require.NoError(t, u.Commit(ctx))
+}
+
+func TestUnitOfWorkSQL_BeginTxHonoursCancelledContext(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u := NewUnitOfWorkSQL(db)
+	assert.ErrorIs(t, u.BeginTx(ctx, nil), context.Canceled)
+}
+
+func TestUnitOfWorkSQL_SavepointRollsBackOneItem(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO items (id) VALUES ('b')`)
+	require.NoError(t, err)
+
+	// Insert a batch where the duplicate item fails and the others are kept
+	u := NewUnitOfWorkSQL(db)
+	require.NoError(t, u.BeginTx(ctx, nil))
+	var failed []string
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, u.Savepoint(ctx, "batch_item"))
+		if _, err := u.tx.ExecContext(ctx, `INSERT INTO items (id) VALUES (?)`, id); err != nil {
+			failed = append(failed, id)
+			require.NoError(t, u.RollbackTo(ctx, "batch_item"))
+			continue
+		}
+		require.NoError(t, u.Release(ctx, "batch_item"))
+	}
+	require.NoError(t, u.Commit(ctx))
+	assert.Equal(t, []string{"b"}, failed)
+
+	var ids []string
+	rows, err := db.Query(`SELECT id FROM items ORDER BY id`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+
+	// A rolled back savepoint undoes only its own changes
+	u = NewUnitOfWorkSQL(db)
+	require.NoError(t, u.BeginTx(ctx, nil))
+	_, err = u.tx.ExecContext(ctx, `INSERT INTO items (id) VALUES ('d')`)
+	require.NoError(t, err)
+	require.NoError(t, u.Savepoint(ctx, "sp"))
+	_, err = u.tx.ExecContext(ctx, `INSERT INTO items (id) VALUES ('e')`)
+	require.NoError(t, err)
+	require.NoError(t, u.RollbackTo(ctx, "sp"))
+	require.NoError(t, u.Commit(ctx))
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM items WHERE id IN ('d', 'e')`).Scan(&n))
+	assert.Equal(t, 1, n)
+
+	assert.EqualError(t, NewUnitOfWorkSQL(db).Savepoint(ctx, "sp"), "savepoint sp: no transaction")
+	u = NewUnitOfWorkSQL(db)
+	require.NoError(t, u.BeginTx(ctx, nil))
+	defer u.Rollback(ctx)
+	assert.EqualError(t, u.Savepoint(ctx, "x; DROP TABLE items"), `invalid savepoint name "x; DROP TABLE items"`)
+}
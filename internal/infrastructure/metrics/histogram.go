@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, used when none are given
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// LatencyHistogram records durations into Prometheus-style cumulative buckets,
+// one series per key
+type LatencyHistogram struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	counts []uint64 // per bucket, not cumulative; the last entry is +Inf
+	count  uint64
+	sum    float64
+}
+
+// HistogramSnapshot is the JSON view of one series. Buckets are
+// cumulative and keyed by their upper bound ("le"), including "+Inf".
+type HistogramSnapshot struct {
+	Buckets    map[string]uint64 `json:"buckets"`
+	Count      uint64            `json:"count"`
+	SumSeconds float64           `json:"sum_seconds"`
+}
+
+// NewLatencyHistogram creates a histogram with the given bucket upper bounds in
+// seconds; DefaultBuckets are used when none are given
+func NewLatencyHistogram(buckets ...float64) *LatencyHistogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &LatencyHistogram{
+		buckets: sorted,
+		series:  make(map[string]*series),
+	}
+}
+
+// Observe records one duration in the series with the given key
+func (h *LatencyHistogram) Observe(key string, d time.Duration) {
+	seconds := d.Seconds()
+	i := sort.SearchFloat64s(h.buckets, seconds)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &series{counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = s
+	}
+	s.counts[i]++
+	s.count++
+	s.sum += seconds
+}
+
+// Snapshot returns the current histograms keyed by series
+func (h *LatencyHistogram) Snapshot() map[string]HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make(map[string]HistogramSnapshot, len(h.series))
+	for op, s := range h.series {
+		snap := HistogramSnapshot{
+			Buckets:    make(map[string]uint64, len(s.counts)),
+			Count:      s.count,
+			SumSeconds: s.sum,
+		}
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			snap.Buckets[strconv.FormatFloat(le, 'g', -1, 64)] = cumulative
+		}
+		snap.Buckets["+Inf"] = s.count
+		out[op] = snap
+	}
+	return out
+}
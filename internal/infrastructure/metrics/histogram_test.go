@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram(0.1, 1)
+
+	h.Observe("download_document", 50*time.Millisecond)
+	h.Observe("download_document", 100*time.Millisecond) // bounds are inclusive
+	h.Observe("download_document", 500*time.Millisecond)
+	h.Observe("download_document", 3*time.Second)
+	h.Observe("other", time.Millisecond)
+
+	snap := h.Snapshot()
+	require.Contains(t, snap, "download_document")
+	doc := snap["download_document"]
+	assert.Equal(t, map[string]uint64{"0.1": 2, "1": 3, "+Inf": 4}, doc.Buckets)
+	assert.Equal(t, uint64(4), doc.Count)
+	assert.InDelta(t, 3.65, doc.SumSeconds, 1e-9)
+	assert.Equal(t, uint64(1), snap["other"].Count)
+}
+
+func TestLatencyHistogram_DefaultBuckets(t *testing.T) {
+	h := NewLatencyHistogram()
+	h.Observe("op", 7*time.Millisecond)
+	snap := h.Snapshot()["op"]
+	assert.Equal(t, uint64(0), snap.Buckets["0.005"])
+	assert.Equal(t, uint64(1), snap.Buckets["0.01"])
+	assert.Len(t, snap.Buckets, len(DefaultBuckets)+1)
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"accounting/internal/domain/ports"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Assert that *Recorder implements ports.MetricsRecorder interface
+var _ ports.MetricsRecorder = (*Recorder)(nil)
+
+// Recorder is the in-memory ports.MetricsRecorder exposed on /metrics. Series are
+// keyed Prometheus-style: the bare name without labels, name{k="v",...} with them.
+type Recorder struct {
+	Latency *LatencyHistogram
+
+	mu       sync.Mutex
+	counters map[string]uint64
+}
+
+// NewRecorder creates a recorder using the default latency buckets
+func NewRecorder() *Recorder {
+	return &Recorder{
+		Latency:  NewLatencyHistogram(),
+		counters: make(map[string]uint64),
+	}
+}
+
+// IncCounter increments the named counter by one
+func (r *Recorder) IncCounter(name string, labels map[string]string) {
+	key := seriesKey(name, labels)
+	r.mu.Lock()
+	r.counters[key]++
+	r.mu.Unlock()
+}
+
+// ObserveDuration records a duration in the named latency histogram
+func (r *Recorder) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	r.Latency.Observe(seriesKey(name, labels), d)
+}
+
+// Counters returns the current counter values keyed by series
+func (r *Recorder) Counters() map[string]uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]uint64, len(r.counters))
+	for k, v := range r.counters {
+		out[k] = v
+	}
+	return out
+}
+
+// seriesKey formats name and labels as name{k="v",...} with labels sorted by key
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteString(`="`)
+		b.WriteString(labels[k])
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
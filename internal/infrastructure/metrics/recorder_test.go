@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+
+	r.IncCounter("splits_finalized_total", nil)
+	r.IncCounter("splits_finalized_total", nil)
+	r.IncCounter("splits_deleted_total", map[string]string{"force": "true"})
+	r.IncCounter("requests_total", map[string]string{"method": "GET", "code": "200"})
+	r.ObserveDuration("download_document", 10*time.Millisecond, nil)
+
+	assert.Equal(t, map[string]uint64{
+		"splits_finalized_total":                  2,
+		`splits_deleted_total{force="true"}`:      1,
+		`requests_total{code="200",method="GET"}`: 1,
+	}, r.Counters())
+	assert.Equal(t, uint64(1), r.Latency.Snapshot()["download_document"].Count)
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"accounting/internal/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultBatchSize   = 50
+	defaultMaxAttempts = 10
+	requestTimeout     = 10 * time.Second
+)
+
+// OutboxStore is the view of the outbox the dispatcher needs
+type OutboxStore interface {
+	ListPending(ctx context.Context, limit, maxAttempts int) ([]domain.OutboxMessage, error)
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	MarkFailed(ctx context.Context, id string, reason string) error
+}
+
+// Dispatcher polls the outbox and POSTs pending messages to a webhook URL.
+// Delivery is at-least-once: a message is only marked sent after a 2xx response,
+// and failed messages are retried on later polls until maxAttempts is reached.
+type Dispatcher struct {
+	store       OutboxStore
+	url         string
+	client      *http.Client
+	interval    time.Duration
+	maxAttempts int
+	batchSize   int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher delivering to url every interval
+func NewDispatcher(store OutboxStore, url string, interval time.Duration, maxAttempts int) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	return &Dispatcher{
+		store:       store,
+		url:         url,
+		client:      &http.Client{Timeout: requestTimeout},
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		batchSize:   defaultBatchSize,
+	}
+}
+
+// Start begins polling in the background
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			d.DispatchPending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for an in-flight batch to finish or ctx to expire
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DispatchPending delivers one batch of pending messages
+func (d *Dispatcher) DispatchPending(ctx context.Context) {
+	msgs, err := d.store.ListPending(ctx, d.batchSize, d.maxAttempts)
+	if err != nil {
+		log.Printf("outbox dispatch failed, error: %v", err)
+		return
+	}
+
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := d.send(ctx, msg); err != nil {
+			log.Printf("webhook delivery failed, id: %s, event: %s, attempt: %d, error: %v", msg.ID, msg.EventType, msg.Attempts+1, err)
+			if err := d.store.MarkFailed(ctx, msg.ID, err.Error()); err != nil {
+				log.Printf("outbox update failed, id: %s, error: %v", msg.ID, err)
+			}
+			continue
+		}
+		if err := d.store.MarkSent(ctx, msg.ID, time.Now()); err != nil {
+			log.Printf("outbox update failed, id: %s, error: %v", msg.ID, err)
+		}
+	}
+}
+
+// send POSTs a single message to the webhook URL
+func (d *Dispatcher) send(ctx context.Context, msg domain.OutboxMessage) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", msg.EventType)
+	req.Header.Set("X-Event-ID", msg.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore implements OutboxStore in memory for testing
+type memoryStore struct {
+	msgs   []domain.OutboxMessage
+	sent   map[string]bool
+	errors map[string]string
+}
+
+func (s *memoryStore) ListPending(ctx context.Context, limit, maxAttempts int) ([]domain.OutboxMessage, error) {
+	var pending []domain.OutboxMessage
+	for _, m := range s.msgs {
+		if !s.sent[m.ID] && m.Attempts < maxAttempts && len(pending) < limit {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func (s *memoryStore) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	s.sent[id] = true
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(ctx context.Context, id string, reason string) error {
+	for i := range s.msgs {
+		if s.msgs[i].ID == id {
+			s.msgs[i].Attempts++
+		}
+	}
+	s.errors[id] = reason
+	return nil
+}
+
+func TestDispatcher_DispatchPending(t *testing.T) {
+	var bodies []string
+	failNext := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failNext {
+			failNext = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		assert.Equal(t, domain.EventSplitFinalized, r.Header.Get("X-Event-Type"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{
+		msgs:   []domain.OutboxMessage{{ID: "msg1", EventType: domain.EventSplitFinalized, Payload: []byte(`{"split_id":"s1"}`)}},
+		sent:   map[string]bool{},
+		errors: map[string]string{},
+	}
+	d := NewDispatcher(store, server.URL, time.Second, 3)
+	ctx := context.Background()
+
+	// First attempt fails and is recorded for retry
+	d.DispatchPending(ctx)
+	assert.False(t, store.sent["msg1"])
+	assert.Equal(t, "unexpected status 503", store.errors["msg1"])
+
+	// Retry succeeds
+	d.DispatchPending(ctx)
+	require.True(t, store.sent["msg1"])
+	assert.Equal(t, []string{`{"split_id":"s1"}`}, bodies)
+
+	// Nothing left to send
+	d.DispatchPending(ctx)
+	assert.Len(t, bodies, 1)
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	store := &memoryStore{sent: map[string]bool{}, errors: map[string]string{}}
+	d := NewDispatcher(store, "http://127.0.0.1:0", 10*time.Millisecond, 3)
+	d.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, d.Stop(ctx))
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsistencyCheckResponse lists the orphaned rows a consistency check found and
+// how many of them it repaired
+type ConsistencyCheckResponse struct {
+	// OrphanedDocumentIDs and OrphanedPageIDs belong to splits that no longer exist
+	OrphanedDocumentIDs []string `json:"orphaned_document_ids"`
+	OrphanedPageIDs     []string `json:"orphaned_page_ids"`
+	// UnlinkedPageIDs are pages of existing splits assigned to documents that no longer exist
+	UnlinkedPageIDs []string `json:"unlinked_page_ids"`
+	IssuesFound     int      `json:"issues_found"`
+	IssuesFixed     int      `json:"issues_fixed"`
+}
+
+// CheckConsistency finds documents and pages left behind by deletions. With
+// repair, the rows of missing splits are deleted and pages of missing documents
+// become unassigned pages of their split, and the repair is recorded in the
+// audit log under the context's actor.
+func (s *SplitService) CheckConsistency(ctx context.Context, repair bool) (*ConsistencyCheckResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	orphans, err := uow.SplitRepository().FindOrphans(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ConsistencyCheckResponse{
+		OrphanedDocumentIDs: nonNilIDs(orphans.DocumentIDs),
+		OrphanedPageIDs:     nonNilIDs(orphans.PageIDs),
+		UnlinkedPageIDs:     make([]string, len(orphans.UnlinkedPages)),
+		IssuesFound:         orphans.Count(),
+	}
+	for i, page := range orphans.UnlinkedPages {
+		resp.UnlinkedPageIDs[i] = page.ID
+	}
+	if !repair || orphans.Count() == 0 {
+		return resp, nil
+	}
+
+	if err := uow.SplitRepository().RepairOrphans(ctx, orphans); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	touched := make(map[string]struct{})
+	for _, page := range orphans.UnlinkedPages {
+		if _, ok := touched[page.SplitID]; ok {
+			continue
+		}
+		touched[page.SplitID] = struct{}{}
+		if err := uow.SplitRepository().TouchSplit(ctx, page.SplitID, now, ActorFromContext(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if err := uow.AuditRepository().Add(ctx, &domain.AuditEntry{
+		ID:         uuid.NewString(),
+		Actor:      ActorFromContext(ctx),
+		Action:     domain.AuditActionRepairOrphans,
+		Resource:   "database",
+		ResourceID: "splits",
+		CreatedAt:  now,
+	}); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	for splitID := range touched {
+		s.splitCache.invalidate(splitID)
+	}
+
+	resp.IssuesFixed = orphans.Count()
+	return resp, nil
+}
+
+// nonNilIDs returns ids, or an empty list if it is nil, so responses show [] rather than null
+func nonNilIDs(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
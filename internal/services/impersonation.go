@@ -0,0 +1,64 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type onBehalfOfKey struct{}
+
+// WithOnBehalfOf returns a context whose reads are scoped to the splits of
+// clientID, for admins acting on behalf of that client
+func WithOnBehalfOf(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, onBehalfOfKey{}, clientID)
+}
+
+// OnBehalfOfFromContext returns the client set by WithOnBehalfOf, or "" if reads are not scoped
+func OnBehalfOfFromContext(ctx context.Context) string {
+	clientID, _ := ctx.Value(onBehalfOfKey{}).(string)
+	return clientID
+}
+
+// visibleSplit returns split, or nil if the context is scoped to another client.
+// Callers report nil as not found, so the scope does not reveal other clients' splits.
+func visibleSplit(ctx context.Context, split *domain.Split) *domain.Split {
+	if split == nil {
+		return nil
+	}
+	if clientID := OnBehalfOfFromContext(ctx); clientID != "" && split.ClientID != clientID {
+		return nil
+	}
+	return split
+}
+
+// checkClientScope rejects reads of a client other than the one the context is scoped to
+func checkClientScope(ctx context.Context, clientID string) error {
+	if scoped := OnBehalfOfFromContext(ctx); scoped != "" && scoped != clientID {
+		return domain.NewNotFoundError("client", clientID, "client not found", nil)
+	}
+	return nil
+}
+
+// RecordImpersonation records in the audit log that actor is acting on behalf of clientID
+func (s *SplitService) RecordImpersonation(ctx context.Context, actor, clientID string) error {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+
+	if err := uow.AuditRepository().Add(ctx, &domain.AuditEntry{
+		ID:         uuid.NewString(),
+		Actor:      actor,
+		Action:     domain.AuditActionImpersonateClient,
+		Resource:   "client",
+		ResourceID: clientID,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	return uow.Commit(ctx)
+}
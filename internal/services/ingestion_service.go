@@ -0,0 +1,223 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"path"
+
+	"github.com/google/uuid"
+)
+
+// Assert that *IngestionService implements ports.SplitIngestionService interface
+var _ ports.SplitIngestionService = (*IngestionService)(nil)
+
+// IngestSplitField is the form field of an upload carrying the split JSON
+const IngestSplitField = "split"
+
+// maxIngestSplitJSON caps the split JSON part of an upload
+const maxIngestSplitJSON = 1 << 20 // 1MB
+
+// IngestionService creates splits from uploads of the split JSON and its page files
+type IngestionService struct {
+	uowFactory func(ctx context.Context) (ports.UnitOfWork, error)
+	blobs      ports.BlobWriter
+	// maxPages caps the pages of an ingested split (0 disables the cap)
+	maxPages int
+}
+
+// NewIngestionService creates a new IngestionService storing page files in blobs
+func NewIngestionService(uowFactory func(ctx context.Context) (ports.UnitOfWork, error), blobs ports.BlobWriter) *IngestionService {
+	return &IngestionService{
+		uowFactory: uowFactory,
+		blobs:      blobs,
+	}
+}
+
+// SetMaxPages sets the pages allowed in an ingested split; zero disables the cap
+func (s *IngestionService) SetMaxPages(max int) {
+	s.maxPages = max
+}
+
+// IngestSplit creates a split from a multipart upload. The first part, the split
+// field, holds the split in the JSON shape domain.NewSplit consumes; each following
+// part is a file holding the content of the page whose URL is its filename. Page
+// files are stored as they are read, so the upload is never held in memory, and
+// every page of the split must be uploaded. Each upload stores its files below a
+// prefix of its own under the split ID, so concurrent uploads of the same split
+// never write or clean up each other's files. Nothing is kept when the upload is
+// refused.
+func (s *IngestionService) IngestSplit(ctx context.Context, req ports.IngestSplitRequest) (resp *ports.IngestSplitResponse, err error) {
+	mr := multipart.NewReader(req.File, req.Boundary)
+	split, err := s.readSplitPart(mr)
+	if err != nil {
+		return nil, err
+	}
+	if req.ClientID != "" && split.ClientID != req.ClientID {
+		return nil, domain.NewValidationError(fmt.Sprintf("split %s belongs to client %s, not %s", split.ID, split.ClientID, req.ClientID), nil)
+	}
+	if !fs.ValidPath(split.ID) || path.Base(split.ID) != split.ID {
+		return nil, domain.NewValidationError(fmt.Sprintf("invalid split id %q", split.ID), nil)
+	}
+
+	// Refuse a split ID in use before storing any page
+	if err := s.checkSplitIDFree(ctx, split.ID); err != nil {
+		return nil, err
+	}
+
+	// Pages by the URL the upload names their file with
+	pending := make(map[string][]*domain.Page)
+	for i := range split.Documents {
+		for _, page := range split.Documents[i].Pages {
+			pending[page.URL] = append(pending[page.URL], page)
+		}
+	}
+
+	// Only this upload writes below prefix, so its files are safe to delete on failure
+	prefix := path.Join(split.ID, uuid.NewString())
+	var stored []string
+	defer func() {
+		if err != nil {
+			for _, url := range stored {
+				s.blobs.Delete(context.WithoutCancel(ctx), url)
+			}
+		}
+	}()
+	uploaded := make(map[string]struct{})
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("malformed upload: %v", err), err)
+		}
+		name := part.FileName()
+		if name == "" {
+			return nil, domain.NewValidationError(fmt.Sprintf("unexpected form field %q; pages must be uploaded as files", part.FormName()), nil)
+		}
+		if _, ok := uploaded[name]; ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("file %s is uploaded more than once", name), nil)
+		}
+		pages, ok := pending[name]
+		if !ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("file %s is not a page of split %s", name, split.ID), nil)
+		}
+
+		url := path.Join(prefix, name)
+		src := &uploadReader{r: part}
+		if err := s.blobs.Put(ctx, url, src); err != nil {
+			if src.err != nil {
+				return nil, domain.NewValidationError(fmt.Sprintf("malformed upload: %v", src.err), src.err)
+			}
+			return nil, fmt.Errorf("failed to store page %s: %w", name, err)
+		}
+		stored = append(stored, url)
+		uploaded[name] = struct{}{}
+		for _, page := range pages {
+			page.URL = url
+		}
+	}
+	for name := range pending {
+		if _, ok := uploaded[name]; !ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("page %s of split %s was not uploaded", name, split.ID), nil)
+		}
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	// Another upload of the split may have been saved while this one was read
+	if err := checkNewSplit(ctx, uow.SplitRepository(), split.ID); err != nil {
+		return nil, err
+	}
+	split.LastModifiedBy = ActorFromContext(ctx)
+	for i := range split.Documents {
+		split.Documents[i].LastModifiedBy = split.LastModifiedBy
+	}
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &ports.IngestSplitResponse{SplitID: split.ID}, nil
+}
+
+// readSplitPart reads the split JSON from the first part of the upload
+func (s *IngestionService) readSplitPart(mr *multipart.Reader) (*domain.Split, error) {
+	part, err := mr.NextPart()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.NewValidationError("upload is empty; the split field is required", nil)
+	}
+	if err != nil {
+		return nil, domain.NewValidationError(fmt.Sprintf("malformed upload: %v", err), err)
+	}
+	if part.FormName() != IngestSplitField || part.FileName() != "" {
+		return nil, domain.NewValidationError(fmt.Sprintf("the first part of the upload must be the %s field", IngestSplitField), nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(part, maxIngestSplitJSON+1))
+	if err != nil {
+		return nil, domain.NewValidationError(fmt.Sprintf("malformed upload: %v", err), err)
+	}
+	if len(data) > maxIngestSplitJSON {
+		return nil, domain.NewValidationError(fmt.Sprintf("the %s field is larger than %d bytes", IngestSplitField, maxIngestSplitJSON), nil)
+	}
+
+	split, err := domain.NewSplit(string(data), domain.WithMaxPages(s.maxPages))
+	if err != nil {
+		var domainErr *domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, err
+		}
+		return nil, domain.NewValidationError(fmt.Sprintf("invalid split: %v", err), err)
+	}
+	return split, nil
+}
+
+// uploadReader remembers the error reading the upload failed with, telling a
+// malformed or oversized upload apart from a failure to store it
+type uploadReader struct {
+	r   io.Reader
+	err error
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		u.err = err
+	}
+	return n, err
+}
+
+// checkSplitIDFree returns a conflict error if a split with the ID already exists
+func (s *IngestionService) checkSplitIDFree(ctx context.Context, splitID string) error {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+	return checkNewSplit(ctx, uow.SplitRepository(), splitID)
+}
+
+// checkNewSplit returns a conflict error if repo has a split with the ID
+func checkNewSplit(ctx context.Context, repo domain.SplitRepository, splitID string) error {
+	existing, err := repo.Get(ctx, splitID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return domain.NewConflictError(fmt.Sprintf("split %s already exists", splitID), nil)
+	}
+	return nil
+}
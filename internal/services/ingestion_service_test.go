@@ -0,0 +1,238 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ingestSplitJSON = `{
+	"split_id": "ingested",
+	"client_id": "test-client",
+	"status": "draft",
+	"documents": [
+		{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_1.png", "page_2.png"]}
+	]
+}`
+
+// ingestUpload builds a multipart upload of the split JSON followed by the files,
+// given as filename/content pairs, returning the body and its boundary
+func ingestUpload(t *testing.T, splitJSON string, files ...string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField(IngestSplitField, splitJSON))
+	for i := 0; i < len(files); i += 2 {
+		fw, err := mw.CreateFormFile("page", files[i])
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(files[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+	return &body, mw.Boundary()
+}
+
+func TestIngestionService_IngestSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	blobs := &mockBlobStore{}
+	ingestion := NewIngestionService(uowFactory, blobs)
+	service := NewSplitService(uowFactory, &mockRenderService{}, blobs)
+	ctx := context.Background()
+
+	body, boundary := ingestUpload(t, ingestSplitJSON, "page_1.png", "one", "page_2.png", "two")
+	resp, err := ingestion.IngestSplit(ctx, ports.IngestSplitRequest{ClientID: "test-client", File: body, Boundary: boundary})
+	require.NoError(t, err)
+	assert.Equal(t, "ingested", resp.SplitID)
+
+	// Pages are stored below a prefix of the upload under the split and the split
+	// references them
+	loaded, err := service.LoadSplit(ctx, "ingested")
+	require.NoError(t, err)
+	require.Len(t, loaded.Documents, 1)
+	require.Len(t, loaded.Documents[0].Pages, 2)
+	first, second := loaded.Documents[0].Pages[0].URL, loaded.Documents[0].Pages[1].URL
+	assert.Regexp(t, `^ingested/[0-9a-f-]{36}/page_1\.png$`, first)
+	assert.Equal(t, "1", loaded.Documents[0].Pages[0].PageNumber)
+	assert.Equal(t, path.Join(path.Dir(first), "page_2.png"), second)
+	assert.Equal(t, map[string]string{first: "one", second: "two"}, blobs.blobs)
+	content, err := service.GetPageContent(ctx, loaded.Documents[0].Pages[1].ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(content.Content)
+	require.NoError(t, err)
+	content.Content.Close()
+	assert.Equal(t, "two", string(data))
+
+	// The same split cannot be ingested twice
+	body, boundary = ingestUpload(t, ingestSplitJSON, "page_1.png", "one", "page_2.png", "two")
+	_, err = ingestion.IngestSplit(ctx, ports.IngestSplitRequest{File: body, Boundary: boundary})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorConflict, domainErr.Kind)
+}
+
+// racingBlobStore runs race once, before storing the first blob
+type racingBlobStore struct {
+	mockBlobStore
+	race func()
+}
+
+func (r *racingBlobStore) Put(ctx context.Context, url string, src io.Reader) error {
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return r.mockBlobStore.Put(ctx, url, src)
+}
+
+func TestIngestionService_IngestSplitConcurrentUploads(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	blobs := &racingBlobStore{}
+	ingestion := NewIngestionService(uowFactory, blobs)
+	service := NewSplitService(uowFactory, &mockRenderService{}, blobs)
+	ctx := context.Background()
+
+	// Another upload of the split wins after this one found the split ID free
+	blobs.race = func() {
+		body, boundary := ingestUpload(t, ingestSplitJSON, "page_1.png", "winner one", "page_2.png", "winner two")
+		_, err := ingestion.IngestSplit(ctx, ports.IngestSplitRequest{File: body, Boundary: boundary})
+		require.NoError(t, err)
+	}
+	body, boundary := ingestUpload(t, ingestSplitJSON, "page_1.png", "loser one", "page_2.png", "loser two")
+	_, err := ingestion.IngestSplit(ctx, ports.IngestSplitRequest{File: body, Boundary: boundary})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorConflict, domainErr.Kind)
+
+	// The loser neither overwrote nor deleted the winner's pages
+	loaded, err := service.LoadSplit(ctx, "ingested")
+	require.NoError(t, err)
+	require.Len(t, loaded.Documents[0].Pages, 2)
+	for i, want := range []string{"winner one", "winner two"} {
+		content, err := service.GetPageContent(ctx, loaded.Documents[0].Pages[i].ID)
+		require.NoError(t, err)
+		data, err := io.ReadAll(content.Content)
+		require.NoError(t, err)
+		content.Content.Close()
+		assert.Equal(t, want, string(data))
+	}
+	assert.Len(t, blobs.blobs, 2)
+}
+
+func TestIngestionService_IngestSplitMalformedUpload(t *testing.T) {
+	missingPage := strings.Replace(ingestSplitJSON, `"page_2.png"]`, `"page_2.png", "page_3.png"]`, 1)
+
+	tests := []struct {
+		name     string
+		body     func(t *testing.T) (*bytes.Buffer, string)
+		clientID string
+		want     string
+	}{
+		{
+			name: "not multipart",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				return bytes.NewBufferString(`{"split_id": "ingested"}`), "boundary"
+			},
+			want: "upload is empty",
+		},
+		{
+			name: "truncated upload",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				body, boundary := ingestUpload(t, ingestSplitJSON, "page_1.png", "one", "page_2.png", "two")
+				body.Truncate(body.Len() - len(boundary) - 8)
+				return body, boundary
+			},
+			want: "malformed upload",
+		},
+		{
+			name: "empty upload",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				require.NoError(t, mw.Close())
+				return &body, mw.Boundary()
+			},
+			want: "upload is empty",
+		},
+		{
+			name: "page before split",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				fw, err := mw.CreateFormFile("page", "page_1.png")
+				require.NoError(t, err)
+				fw.Write([]byte("one"))
+				require.NoError(t, mw.Close())
+				return &body, mw.Boundary()
+			},
+			want: "the first part of the upload must be the split field",
+		},
+		{
+			name: "invalid split JSON",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				return ingestUpload(t, `{"split_id": `)
+			},
+			want: "invalid split",
+		},
+		{
+			name: "file not in split",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				return ingestUpload(t, ingestSplitJSON, "page_1.png", "one", "page_9.png", "nine")
+			},
+			want: "file page_9.png is not a page of split ingested",
+		},
+		{
+			name: "missing page",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				return ingestUpload(t, missingPage, "page_1.png", "one", "page_2.png", "two")
+			},
+			want: "page page_3.png of split ingested was not uploaded",
+		},
+		{
+			name: "other client",
+			body: func(t *testing.T) (*bytes.Buffer, string) {
+				return ingestUpload(t, ingestSplitJSON, "page_1.png", "one", "page_2.png", "two")
+			},
+			clientID: "other-client",
+			want:     "belongs to client test-client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, uowFactory := setupTestDB(t)
+			defer db.Close()
+
+			blobs := &mockBlobStore{}
+			ingestion := NewIngestionService(uowFactory, blobs)
+			ctx := context.Background()
+
+			body, boundary := tt.body(t)
+			_, err := ingestion.IngestSplit(ctx, ports.IngestSplitRequest{ClientID: tt.clientID, File: body, Boundary: boundary})
+			var domainErr *domain.DomainError
+			require.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+			assert.Contains(t, err.Error(), tt.want)
+
+			// Nothing is kept from a refused upload
+			assert.Empty(t, blobs.blobs)
+			uow, err := uowFactory(ctx)
+			require.NoError(t, err)
+			defer uow.Rollback(ctx)
+			split, err := uow.SplitRepository().Get(ctx, "ingested")
+			require.NoError(t, err)
+			assert.Nil(t, split)
+		})
+	}
+}
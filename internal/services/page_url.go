@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parsePageURLBase parses the base that relative page URLs are resolved against.
+// The base is treated as a directory, so "https://cdn.example.com/pages" resolves
+// "page_1.png" to "https://cdn.example.com/pages/page_1.png". An empty base returns nil.
+func parsePageURLBase(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	base, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page URL base %q: %w", raw, err)
+	}
+	if !base.IsAbs() {
+		return nil, fmt.Errorf("invalid page URL base %q: a scheme is required", raw)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return base, nil
+}
+
+// resolvePageURL resolves a stored page URL against base. URLs with a scheme
+// (e.g. https:// or s3://) are returned unchanged, as are all URLs when base is nil.
+func resolvePageURL(base *url.URL, raw string) string {
+	if base == nil {
+		return raw
+	}
+	ref, err := url.Parse(raw)
+	if err != nil || ref.IsAbs() {
+		return raw
+	}
+	return base.ResolveReference(ref).String()
+}
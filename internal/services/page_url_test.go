@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		url  string
+		want string
+	}{
+		{name: "relative", base: "https://cdn.example.com/pages/", url: "page_1.png", want: "https://cdn.example.com/pages/page_1.png"},
+		{name: "relative with base without trailing slash", base: "https://cdn.example.com/pages", url: "split1/page_1.png", want: "https://cdn.example.com/pages/split1/page_1.png"},
+		{name: "absolute", base: "https://cdn.example.com/pages/", url: "https://other.example.com/page_1.png", want: "https://other.example.com/page_1.png"},
+		{name: "scheme specific", base: "https://cdn.example.com/pages/", url: "s3://bucket/page_1.png", want: "s3://bucket/page_1.png"},
+		{name: "relative against s3 base", base: "s3://bucket/pages", url: "page_1.png", want: "s3://bucket/pages/page_1.png"},
+		{name: "no base", base: "", url: "page_1.png", want: "page_1.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := parsePageURLBase(tt.base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resolvePageURL(base, tt.url))
+		})
+	}
+}
+
+func TestParsePageURLBaseRequiresScheme(t *testing.T) {
+	_, err := parsePageURLBase("cdn.example.com/pages")
+	assert.Error(t, err)
+}
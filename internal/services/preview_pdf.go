@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// previewSection is one document of a split preview: a title page followed by the
+// pages of the rendered document
+type previewSection struct {
+	Title string
+	Lines []string
+	PDF   []byte
+}
+
+var (
+	pdfObjectPattern = regexp.MustCompile(`(?s)(\d+)\s+\d+\s+obj\b(.*?)endobj`)
+	pdfRefPattern    = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfRootPattern   = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R\b`)
+	pdfPagesPattern  = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R\b`)
+	pdfKidsPattern   = regexp.MustCompile(`(?s)/Kids\s*\[(.*?)\]`)
+	pdfParentPattern = regexp.MustCompile(`/Parent\s+(?:null|\d+\s+\d+\s+R\b)`)
+	pdfTypePattern   = regexp.MustCompile(`/Type\s*/(Catalog|Pages|Page)\b`)
+)
+
+// pdfWriter assembles a PDF from object bodies numbered from 1
+type pdfWriter struct {
+	objects []string
+}
+
+// reserve allocates an object number whose body is set later
+func (w *pdfWriter) reserve() int {
+	w.objects = append(w.objects, "")
+	return len(w.objects)
+}
+
+func (w *pdfWriter) set(n int, body string) {
+	w.objects[n-1] = body
+}
+
+func (w *pdfWriter) add(body string) int {
+	n := w.reserve()
+	w.set(n, body)
+	return n
+}
+
+// bytes writes the objects with a cross-reference table and the given catalog as root
+func (w *pdfWriter) bytes(root int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(w.objects))
+	for i, body := range w.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.objects)+1, root, xref)
+	return buf.Bytes()
+}
+
+// buildPreviewPDF combines the sections into one PDF, each document preceded by a
+// title page. Rendered documents must be uncompressed PDFs without object streams,
+// with their resources on each page, as RenderService produces.
+func buildPreviewPDF(sections []previewSection) ([]byte, error) {
+	w := &pdfWriter{}
+	catalog := w.reserve()
+	pagesRoot := w.reserve()
+	font := w.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+
+	var kids []string
+	for _, section := range sections {
+		kids = append(kids, fmt.Sprintf("%d 0 R", titlePage(w, pagesRoot, font, section)))
+		pages, err := mergePDFPages(w, pagesRoot, section.PDF)
+		if err != nil {
+			return nil, fmt.Errorf("cannot merge rendered document %q: %w", section.Title, err)
+		}
+		for _, p := range pages {
+			kids = append(kids, fmt.Sprintf("%d 0 R", p))
+		}
+	}
+
+	w.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRoot))
+	w.set(pagesRoot, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 612 792] >>", strings.Join(kids, " "), len(kids)))
+	return w.bytes(catalog), nil
+}
+
+// titlePage adds a separator page showing the section title and lines
+func titlePage(w *pdfWriter, parent, font int, section previewSection) int {
+	var content strings.Builder
+	fmt.Fprintf(&content, "BT\n/F1 24 Tf\n72 700 Td\n(%s) Tj\n/F1 12 Tf\n", pdfText(section.Title))
+	for _, line := range section.Lines {
+		fmt.Fprintf(&content, "0 -20 Td\n(%s) Tj\n", pdfText(line))
+	}
+	content.WriteString("ET")
+	stream := w.add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	return w.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>", parent, font, stream))
+}
+
+// pdfText escapes a string for use in a PDF literal string
+func pdfText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", " ", "\n", " ").Replace(s)
+}
+
+// mergePDFPages copies the pages of a PDF and the objects they use into w, in page
+// tree order, reparented under parent. It returns the new page object numbers.
+func mergePDFPages(w *pdfWriter, parent int, data []byte) ([]int, error) {
+	objects := make(map[int]string)
+	var order []int
+	for _, m := range pdfObjectPattern.FindAllSubmatch(data, -1) {
+		n, _ := strconv.Atoi(string(m[1]))
+		if _, ok := objects[n]; !ok {
+			order = append(order, n)
+		}
+		objects[n] = strings.TrimSpace(string(m[2]))
+	}
+
+	root := pdfRootPattern.FindSubmatch(data)
+	if root == nil {
+		return nil, fmt.Errorf("no document catalog")
+	}
+	catalogNum, _ := strconv.Atoi(string(root[1]))
+	pagesRef := pdfPagesPattern.FindStringSubmatch(objects[catalogNum])
+	if pagesRef == nil {
+		return nil, fmt.Errorf("no page tree")
+	}
+	pagesNum, _ := strconv.Atoi(pagesRef[1])
+
+	var pages []int
+	var walk func(n, depth int) error
+	walk = func(n, depth int) error {
+		if depth > 32 {
+			return fmt.Errorf("page tree too deep")
+		}
+		body, ok := objects[n]
+		if !ok {
+			return fmt.Errorf("missing page tree object %d", n)
+		}
+		switch objectType(body) {
+		case "Page":
+			pages = append(pages, n)
+		case "Pages":
+			kids := pdfKidsPattern.FindStringSubmatch(body)
+			if kids == nil {
+				return nil
+			}
+			for _, ref := range pdfRefPattern.FindAllStringSubmatch(kids[1], -1) {
+				kid, _ := strconv.Atoi(ref[1])
+				if err := walk(kid, depth+1); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("object %d is not part of the page tree", n)
+		}
+		return nil
+	}
+	if err := walk(pagesNum, 0); err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages")
+	}
+
+	// Renumber every object except the catalog and page tree nodes, which are replaced
+	renumbered := make(map[int]int, len(order))
+	for _, n := range order {
+		if t := objectType(objects[n]); t == "Catalog" || t == "Pages" {
+			continue
+		}
+		renumbered[n] = w.reserve()
+	}
+	for _, n := range order {
+		newNum, ok := renumbered[n]
+		if !ok {
+			continue
+		}
+		body := renumberRefs(objects[n], renumbered)
+		if objectType(body) == "Page" {
+			body = pdfParentPattern.ReplaceAllString(body, fmt.Sprintf("/Parent %d 0 R", parent))
+		}
+		w.set(newNum, body)
+	}
+
+	newPages := make([]int, len(pages))
+	for i, p := range pages {
+		newPages[i] = renumbered[p]
+	}
+	return newPages, nil
+}
+
+// objectType returns the /Type of an object's dictionary if it is part of the page tree
+func objectType(body string) string {
+	dict, _, _ := strings.Cut(body, "stream")
+	if m := pdfTypePattern.FindStringSubmatch(dict); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// renumberRefs rewrites the indirect references in an object's dictionary; stream
+// data is copied as is. References to dropped objects become null.
+func renumberRefs(body string, renumbered map[int]int) string {
+	dict, stream, hasStream := strings.Cut(body, "stream")
+	dict = pdfRefPattern.ReplaceAllStringFunc(dict, func(ref string) string {
+		n, _ := strconv.Atoi(pdfRefPattern.FindStringSubmatch(ref)[1])
+		if newNum, ok := renumbered[n]; ok {
+			return fmt.Sprintf("%d 0 R", newNum)
+		}
+		return "null"
+	})
+	if hasStream {
+		return dict + "stream" + stream
+	}
+	return dict
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderPDF(t *testing.T, filename string) []byte {
+	t.Helper()
+	resp, err := NewRenderService().RenderDocument(context.Background(), ports.RenderDocumentRequest{
+		Document: &domain.Document{ID: filename, Filename: filename},
+	})
+	require.NoError(t, err)
+	return resp.Data
+}
+
+func TestBuildPreviewPDF(t *testing.T) {
+	data, err := buildPreviewPDF([]previewSection{
+		{Title: "W-2 (1)", Lines: []string{"Classification: W-2"}, PDF: renderPDF(t, "w2.pdf")},
+		{Title: "1099", Lines: []string{"Classification: 1099"}, PDF: renderPDF(t, "1099.pdf")},
+	})
+	require.NoError(t, err)
+
+	pdf := string(data)
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.Contains(t, pdf, "/Count 4")
+	assert.Contains(t, pdf, `(W-2 \(1\)) Tj`)
+	assert.Less(t, strings.Index(pdf, "(W-2 \\(1\\)) Tj"), strings.Index(pdf, "(w2.pdf) Tj"))
+	assert.Less(t, strings.Index(pdf, "(w2.pdf) Tj"), strings.Index(pdf, "(1099) Tj"))
+	assert.Less(t, strings.Index(pdf, "(1099) Tj"), strings.Index(pdf, "(1099.pdf) Tj"))
+
+	// The cross-reference table points at every object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	require.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(startxref[1])
+	require.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(e[1])
+		assert.True(t, strings.HasPrefix(pdf[off:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	// Every reference resolves to an object of the combined PDF
+	for _, ref := range regexp.MustCompile(`(\d+) 0 R\b`).FindAllStringSubmatch(pdf, -1) {
+		n, _ := strconv.Atoi(ref[1])
+		assert.True(t, n >= 1 && n <= len(entries), "dangling reference %s", ref[0])
+	}
+}
+
+func TestBuildPreviewPDFRejectsUnsupportedDocuments(t *testing.T) {
+	_, err := buildPreviewPDF([]previewSection{{Title: "broken", PDF: []byte("not a pdf")}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sync"
+)
+
+// CachingRenderService decorates a RenderService with an LRU cache of rendered documents.
+// Entries are keyed by document ID and tagged with the document's content hash, so any
+// mutation of the document (metadata, pages or their order) causes a re-render.
+type CachingRenderService struct {
+	next ports.RenderService
+	size int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type renderCacheEntry struct {
+	docID string
+	hash  string
+	resp  *ports.RenderDocumentResponse
+}
+
+// NewCachingRenderService creates a render service caching up to size rendered documents.
+// A size of zero or less disables caching.
+func NewCachingRenderService(next ports.RenderService, size int) ports.RenderService {
+	if size <= 0 {
+		return next
+	}
+	return &CachingRenderService{
+		next:    next,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// RenderDocument returns the cached rendering of the document when it is unchanged
+func (s *CachingRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	docID := req.Document.ID
+	hash := documentContentHash(req.Document)
+
+	s.mu.Lock()
+	if el, ok := s.entries[docID]; ok {
+		entry := el.Value.(*renderCacheEntry)
+		if entry.hash == hash {
+			s.order.MoveToFront(el)
+			s.mu.Unlock()
+			return entry.resp, nil
+		}
+		// Document changed since it was rendered
+		s.order.Remove(el)
+		delete(s.entries, docID)
+	}
+	s.mu.Unlock()
+
+	resp, err := s.next.RenderDocument(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[docID]; ok {
+		s.order.Remove(el)
+	}
+	s.entries[docID] = s.order.PushFront(&renderCacheEntry{docID: docID, hash: hash, resp: resp})
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*renderCacheEntry).docID)
+	}
+	return resp, nil
+}
+
+// Cached returns the cached rendering of the document if it is unchanged, without rendering it
+func (s *CachingRenderService) Cached(doc *domain.Document) (*ports.RenderDocumentResponse, bool) {
+	hash := documentContentHash(doc)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[doc.ID]
+	if !ok || el.Value.(*renderCacheEntry).hash != hash {
+		return nil, false
+	}
+	return el.Value.(*renderCacheEntry).resp, true
+}
+
+// Evict drops the cached renderings of the documents, e.g. once they are deleted
+func (s *CachingRenderService) Evict(docIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, docID := range docIDs {
+		if el, ok := s.entries[docID]; ok {
+			s.order.Remove(el)
+			delete(s.entries, docID)
+		}
+	}
+}
+
+// documentContentHash fingerprints everything that affects a document's rendering
+func documentContentHash(doc *domain.Document) string {
+	h := sha256.New()
+	for _, field := range []string{doc.ID, doc.Name, doc.Classification, doc.Filename, doc.ShortDescription} {
+		io.WriteString(h, field)
+		h.Write([]byte{0})
+	}
+	for _, page := range doc.Pages {
+		io.WriteString(h, page.ID)
+		h.Write([]byte{0})
+		io.WriteString(h, page.URL)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRenderService counts how many times each document is rendered
+type countingRenderService struct {
+	calls map[string]int
+}
+
+func (m *countingRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	m.calls[req.Document.ID]++
+	return &ports.RenderDocumentResponse{
+		Filename:    req.Document.Filename,
+		ContentType: "application/pdf",
+		Data:        []byte("rendered " + req.Document.Name),
+	}, nil
+}
+
+func TestSplitService_DownloadDocument_Cached(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	service := NewSplitService(uowFactory, NewCachingRenderService(renderer, 10), &mockBlobStore{})
+	ctx := context.Background()
+
+	// Create test split with document
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{
+				ID:             "doc1",
+				SplitID:        "test-split",
+				Name:           "Test Document",
+				Classification: "Test Class",
+				Filename:       "test.pdf",
+				Pages: []*domain.Page{
+					{ID: "page1", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+				},
+			},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Second download of an unchanged document is served from cache
+	first, err := service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	second, err := service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, renderer.calls["doc1"])
+	assert.Equal(t, first.Data, second.Data)
+	assert.NotEmpty(t, first.ETag)
+	assert.Equal(t, first.ETag, second.ETag)
+
+	// Mutating the document invalidates the cached rendering
+	newName := "Renamed Document"
+	_, err = service.UpdateDocumentMetadata(ctx, "doc1", UpdateDocumentMetadataRequest{Name: &newName})
+	require.NoError(t, err)
+	third, err := service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, renderer.calls["doc1"])
+	assert.Equal(t, []byte("rendered Renamed Document"), third.Data)
+	assert.NotEqual(t, first.ETag, third.ETag)
+}
+
+func TestSplitService_DocumentDownloadInfo(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	service := NewSplitService(uowFactory, NewCachingRenderService(renderer, 10), &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "Test Document", Filename: "test.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+				{ID: "page2", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 2, URL: "page_2.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// Before the first download the size is estimated and nothing is rendered
+	info, err := service.DocumentDownloadInfo(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, "test.pdf", info.Filename)
+	assert.Equal(t, "application/pdf", info.ContentType)
+	assert.Equal(t, 2, info.PageCount)
+	assert.True(t, info.SizeEstimated)
+	assert.Equal(t, int64(2*EstimatedPageBytes), info.Size)
+	assert.Zero(t, renderer.calls["doc1"])
+
+	// Once the rendering is cached its size is exact
+	download, err := service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	info, err = service.DocumentDownloadInfo(ctx, "doc1")
+	require.NoError(t, err)
+	assert.False(t, info.SizeEstimated)
+	assert.Equal(t, int64(len(download.Data)), info.Size)
+	assert.Equal(t, download.ETag, info.ETag)
+	assert.Equal(t, 1, renderer.calls["doc1"])
+
+	_, err = service.DocumentDownloadInfo(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_DownloadSplitDocument(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	service := NewSplitService(uowFactory, renderer, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for _, splitID := range []string{"split-a", "split-b"} {
+		docID := "doc-" + splitID
+		require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+			ID:        splitID,
+			ClientID:  "test-client",
+			Status:    domain.SplitStatusDraft,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Documents: []domain.Document{
+				{ID: docID, SplitID: splitID, Name: "Test Document", Filename: "test.pdf", Pages: []*domain.Page{
+					{ID: "page-" + splitID, SplitID: splitID, DocumentID: stringPtr(docID), PageNumber: 1, URL: "page_1.png"},
+				}},
+			},
+		}))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	download, err := service.DownloadSplitDocument(ctx, "split-a", "doc-split-a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("rendered Test Document"), download.Data)
+	assert.Equal(t, "test.pdf", download.Filename)
+
+	info, err := service.SplitDocumentDownloadInfo(ctx, "split-a", "doc-split-a")
+	require.NoError(t, err)
+	assert.Equal(t, download.ETag, info.ETag)
+
+	// The document must belong to the split in the path
+	_, err = service.DownloadSplitDocument(ctx, "split-a", "doc-split-b")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.DownloadSplitDocument(ctx, "missing", "doc-split-a")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Reads scoped to another client do not see the split
+	_, err = service.DownloadSplitDocument(WithOnBehalfOf(ctx, "other-client"), "split-a", "doc-split-a")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_ForceDeleteSplitEvictsRenderings(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	cache := NewCachingRenderService(&countingRenderService{calls: make(map[string]int)}, 10).(*CachingRenderService)
+	service := NewSplitService(uowFactory, cache, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for _, splitID := range []string{"deleted", "kept"} {
+		docID := splitID + "-doc"
+		require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+			ID: splitID, ClientID: "test-client", Status: domain.SplitStatusFinalized, CreatedAt: now, UpdatedAt: now, FinalizedAt: &now,
+			Documents: []domain.Document{
+				{ID: docID, SplitID: splitID, Name: "Test Document", Filename: "test.pdf", Pages: []*domain.Page{
+					{ID: splitID + "-page", SplitID: splitID, DocumentID: stringPtr(docID), PageNumber: 1, URL: "page_1.png"},
+				}},
+			},
+		}))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	for _, docID := range []string{"deleted-doc", "kept-doc"} {
+		_, err := service.DownloadDocument(ctx, docID)
+		require.NoError(t, err)
+	}
+	require.Len(t, cache.entries, 2)
+
+	// The deleted split's renderings leave the cache with it
+	require.NoError(t, service.ForceDeleteSplit(ctx, "deleted", "admin"))
+	assert.NotContains(t, cache.entries, "deleted-doc")
+	assert.Contains(t, cache.entries, "kept-doc")
+}
+
+func TestCachingRenderService_Eviction(t *testing.T) {
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	cache := NewCachingRenderService(renderer, 2)
+	ctx := context.Background()
+
+	render := func(id string) {
+		_, err := cache.RenderDocument(ctx, ports.RenderDocumentRequest{
+			Document: &domain.Document{ID: id, Name: id},
+		})
+		require.NoError(t, err)
+	}
+
+	render("doc1")
+	render("doc2")
+	render("doc1") // doc1 is now the most recently used
+	render("doc3") // evicts doc2
+	render("doc1")
+	render("doc2")
+
+	assert.Equal(t, 1, renderer.calls["doc1"])
+	assert.Equal(t, 2, renderer.calls["doc2"])
+	assert.Equal(t, 1, renderer.calls["doc3"])
+}
+
+func TestNewCachingRenderService_Disabled(t *testing.T) {
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	assert.Same(t, renderer, NewCachingRenderService(renderer, 0))
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"accounting/internal/domain/ports"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Renderer backends selectable with NewRenderer
+const (
+	RendererPlaceholder = "placeholder"
+	RendererPDFCPU      = "pdfcpu"
+	RendererImageMagick = "imagemagick"
+)
+
+// NewRenderer returns the render service for the named backend. The placeholder
+// needs nothing installed; the others combine the page images read from blobs
+// into a PDF with the pdfcpu or ImageMagick command, which must be on PATH.
+func NewRenderer(name string, blobs ports.BlobStore) (ports.RenderService, error) {
+	switch name {
+	case RendererPlaceholder, "":
+		return NewRenderService(), nil
+	case RendererPDFCPU:
+		return newCommandRenderService(blobs, "pdfcpu", func(images []string, out string) []string {
+			return append([]string{"import", out}, images...)
+		}), nil
+	case RendererImageMagick:
+		return newCommandRenderService(blobs, "magick", func(images []string, out string) []string {
+			return append(images, out)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown renderer %q: must be %s, %s or %s", name, RendererPlaceholder, RendererPDFCPU, RendererImageMagick)
+	}
+}
+
+// commandRenderService renders a document by running an external command that
+// combines the document's page images into a PDF
+type commandRenderService struct {
+	blobs ports.BlobStore
+	tool  string
+	// args builds the command line converting images, in order, into the PDF out
+	args func(images []string, out string) []string
+}
+
+func newCommandRenderService(blobs ports.BlobStore, tool string, args func(images []string, out string) []string) *commandRenderService {
+	return &commandRenderService{blobs: blobs, tool: tool, args: args}
+}
+
+// RenderDocument implements the ports.RenderService interface
+func (s *commandRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	if len(req.Document.Pages) == 0 {
+		return nil, fmt.Errorf("document %s has no pages to render", req.Document.ID)
+	}
+
+	dir, err := os.MkdirTemp("", "render-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating render directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Copy the page images out of the blob store, keeping their extensions so the
+	// tool can tell their format
+	images := make([]string, len(req.Document.Pages))
+	for i, page := range req.Document.Pages {
+		images[i] = filepath.Join(dir, fmt.Sprintf("page-%04d%s", i+1, path.Ext(page.URL)))
+		if err := s.copyPage(ctx, page.URL, images[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	out := filepath.Join(dir, "document.pdf")
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.tool, s.args(images, out)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", s.tool, err, strings.TrimSpace(stderr.String()))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rendered document: %w", err)
+	}
+	return &ports.RenderDocumentResponse{
+		Filename:    req.Document.Filename,
+		ContentType: "application/pdf",
+		Data:        data,
+	}, nil
+}
+
+func (s *commandRenderService) copyPage(ctx context.Context, url, dst string) error {
+	src, err := s.blobs.Open(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error creating page file: %w", err)
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return fmt.Errorf("error copying page %s: %w", url, err)
+	}
+	return f.Close()
+}
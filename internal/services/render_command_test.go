@@ -0,0 +1,74 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRenderer(t *testing.T) {
+	blobs := &mockBlobStore{}
+	tests := []struct {
+		name string
+		tool string
+	}{
+		{name: "", tool: ""},
+		{name: RendererPlaceholder, tool: ""},
+		{name: RendererPDFCPU, tool: "pdfcpu"},
+		{name: RendererImageMagick, tool: "magick"},
+	}
+
+	for _, tt := range tests {
+		renderer, err := NewRenderer(tt.name, blobs)
+		require.NoError(t, err, tt.name)
+		if tt.tool == "" {
+			assert.IsType(t, &RenderService{}, renderer, tt.name)
+			continue
+		}
+		cmd, ok := renderer.(*commandRenderService)
+		require.True(t, ok, tt.name)
+		assert.Equal(t, tt.tool, cmd.tool)
+		assert.Same(t, blobs, cmd.blobs)
+	}
+
+	_, err := NewRenderer("gofpdf", blobs)
+	assert.EqualError(t, err, `unknown renderer "gofpdf": must be placeholder, pdfcpu or imagemagick`)
+}
+
+func TestCommandRenderService(t *testing.T) {
+	// A stand-in tool that concatenates the images into the output, last argument
+	tool := filepath.Join(t.TempDir(), "fake-render")
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\nfor last; do :; done\nwhile [ $# -gt 1 ]; do cat \"$1\" >> \"$last\"; shift; done\n"), 0o755))
+
+	blobs := &mockBlobStore{blobs: map[string]string{"page_1.png": "one,", "page_2.png": "two"}}
+	renderer := newCommandRenderService(blobs, tool, func(images []string, out string) []string {
+		return append(images, out)
+	})
+
+	docID := "doc1"
+	resp, err := renderer.RenderDocument(context.Background(), ports.RenderDocumentRequest{Document: &domain.Document{
+		ID:       docID,
+		Filename: "w2.pdf",
+		Pages: []*domain.Page{
+			{ID: "page1", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			{ID: "page2", DocumentID: &docID, PageNumber: 2, URL: "page_2.png"},
+		},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "w2.pdf", resp.Filename)
+	assert.Equal(t, "application/pdf", resp.ContentType)
+	assert.Equal(t, "one,two", string(resp.Data))
+
+	// A missing page fails the render
+	_, err = renderer.RenderDocument(context.Background(), ports.RenderDocumentRequest{Document: &domain.Document{
+		ID:    docID,
+		Pages: []*domain.Page{{ID: "page3", DocumentID: &docID, PageNumber: 3, URL: "page_3.png"}},
+	}})
+	assert.ErrorContains(t, err, "blob not found")
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"sync/atomic"
+)
+
+// LimitedRenderService decorates a RenderService with a bound on simultaneous renders.
+// Renders beyond the limit wait for a slot until their context is done; once maxWaiting
+// renders are already waiting, further ones fail immediately. Both failures are
+// unavailable errors, which the API reports as 503.
+type LimitedRenderService struct {
+	next ports.RenderService
+	// slots holds one token per running render (nil disables the limit)
+	slots      chan struct{}
+	maxWaiting int64
+
+	inFlight atomic.Int64
+	waiting  atomic.Int64
+}
+
+// NewLimitedRenderService creates a render service running at most maxConcurrent renders
+// at once with at most maxWaiting renders queued for a slot. A maxConcurrent of zero or
+// less disables the limit; renders are still counted.
+func NewLimitedRenderService(next ports.RenderService, maxConcurrent, maxWaiting int) *LimitedRenderService {
+	s := &LimitedRenderService{next: next, maxWaiting: int64(maxWaiting)}
+	if maxConcurrent > 0 {
+		s.slots = make(chan struct{}, maxConcurrent)
+	}
+	return s
+}
+
+// RenderDocument renders the document once a slot is free
+func (s *LimitedRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	if s.slots != nil {
+		if err := s.acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer func() { <-s.slots }()
+	}
+
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	return s.next.RenderDocument(ctx, req)
+}
+
+func (s *LimitedRenderService) acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if s.waiting.Add(1) > s.maxWaiting {
+		s.waiting.Add(-1)
+		return domain.NewUnavailableError("too many renders in progress, retry later", nil)
+	}
+	defer s.waiting.Add(-1)
+
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return domain.NewUnavailableError("timed out waiting for a render slot", ctx.Err())
+	}
+}
+
+// InFlight returns the number of renders currently running
+func (s *LimitedRenderService) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// Waiting returns the number of renders waiting for a slot
+func (s *LimitedRenderService) Waiting() int64 {
+	return s.waiting.Load()
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"accounting/internal/domain/ports"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingRenderService blocks every render until release is closed
+type blockingRenderService struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	m.started <- struct{}{}
+	<-m.release
+	return &ports.RenderDocumentResponse{Filename: req.Document.Filename, ContentType: "application/pdf"}, nil
+}
+
+func assertUnavailable(t *testing.T, err error) {
+	t.Helper()
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorUnavailable, domainErr.Kind)
+}
+
+func TestLimitedRenderService_Saturated(t *testing.T) {
+	renderer := &blockingRenderService{started: make(chan struct{}, 2), release: make(chan struct{})}
+	limited := NewLimitedRenderService(renderer, 1, 1)
+	req := ports.RenderDocumentRequest{Document: &domain.Document{ID: "doc1", Filename: "doc1.pdf"}}
+
+	// The first render takes the only slot
+	results := make(chan error, 2)
+	go func() {
+		_, err := limited.RenderDocument(context.Background(), req)
+		results <- err
+	}()
+	<-renderer.started
+	assert.Equal(t, int64(1), limited.InFlight())
+
+	// The second waits for it
+	go func() {
+		_, err := limited.RenderDocument(context.Background(), req)
+		results <- err
+	}()
+	require.Eventually(t, func() bool { return limited.Waiting() == 1 }, time.Second, time.Millisecond)
+
+	// The queue is full, so a third is refused immediately
+	_, err := limited.RenderDocument(context.Background(), req)
+	assertUnavailable(t, err)
+
+	// Releasing the slot lets the waiting render run
+	close(renderer.release)
+	<-renderer.started
+	require.NoError(t, <-results)
+	require.NoError(t, <-results)
+	assert.Equal(t, int64(0), limited.InFlight())
+	assert.Equal(t, int64(0), limited.Waiting())
+}
+
+func TestLimitedRenderService_WaitRespectsDeadline(t *testing.T) {
+	renderer := &blockingRenderService{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(renderer.release)
+	limited := NewLimitedRenderService(renderer, 1, 10)
+	req := ports.RenderDocumentRequest{Document: &domain.Document{ID: "doc1", Filename: "doc1.pdf"}}
+
+	go limited.RenderDocument(context.Background(), req)
+	<-renderer.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := limited.RenderDocument(ctx, req)
+	assertUnavailable(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(0), limited.Waiting())
+}
+
+func TestNewLimitedRenderService_Disabled(t *testing.T) {
+	renderer := &countingRenderService{calls: make(map[string]int)}
+	limited := NewLimitedRenderService(renderer, 0, 0)
+	req := ports.RenderDocumentRequest{Document: &domain.Document{ID: "doc1"}}
+
+	for i := 0; i < 3; i++ {
+		_, err := limited.RenderDocument(context.Background(), req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, renderer.calls["doc1"])
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// splitCache is an LRU cache of LoadSplit responses keyed by split ID. Entries are
+// tagged with the split's UpdatedAt, so a split changed by another process misses
+// even though only this service's mutations invalidate entries. Cached responses
+// are shared between callers and must not be modified.
+type splitCache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type splitCacheEntry struct {
+	splitID   string
+	updatedAt time.Time
+	expiresAt time.Time
+	resp      *LoadSplitResponse
+}
+
+// newSplitCache creates a cache of up to size responses that expire after ttl (zero never expires)
+func newSplitCache(size int, ttl time.Duration) *splitCache {
+	return &splitCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached response of the split if it is unexpired and was cached
+// at updatedAt, or nil
+func (c *splitCache) get(splitID string, updatedAt time.Time) *LoadSplitResponse {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[splitID]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*splitCacheEntry)
+	if !entry.updatedAt.Equal(updatedAt) || (c.ttl > 0 && !c.now().Before(entry.expiresAt)) {
+		c.order.Remove(el)
+		delete(c.entries, splitID)
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return entry.resp
+}
+
+// put caches the response, evicting the least recently used entries beyond size
+func (c *splitCache) put(resp *LoadSplitResponse) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[resp.ID]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[resp.ID] = c.order.PushFront(&splitCacheEntry{
+		splitID:   resp.ID,
+		updatedAt: resp.UpdatedAt,
+		expiresAt: c.now().Add(c.ttl),
+		resp:      resp,
+	})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*splitCacheEntry).splitID)
+	}
+}
+
+// invalidate drops the cached responses of the splits
+func (c *splitCache) invalidate(splitIDs ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range splitIDs {
+		if el, ok := c.entries[id]; ok {
+			c.order.Remove(el)
+			delete(c.entries, id)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitService_LoadSplit_Cached(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	service.SetSplitCache(10, time.Minute)
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	docID := "doc1"
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: docID, SplitID: "test-split", Name: "W-2", Classification: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// An unchanged split is served from the cache
+	first, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	second, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// A split updated outside the service no longer matches the cached version
+	uow, err = uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().TouchSplit(ctx, "test-split", now.Add(time.Second), "bob"))
+	require.NoError(t, uow.Commit(ctx))
+	stale, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.NotSame(t, first, stale)
+	assert.True(t, stale.UpdatedAt.Equal(now.Add(time.Second)))
+
+	// Mutations through the service invalidate the entry
+	name := "Renamed"
+	_, err = service.UpdateDocumentMetadata(ctx, docID, UpdateDocumentMetadataRequest{Name: &name})
+	require.NoError(t, err)
+	assert.NotContains(t, service.splitCache.entries, "test-split")
+	updated, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Documents[0].Name)
+
+	// Other clients' splits stay hidden when cached
+	_, err = service.LoadSplit(WithOnBehalfOf(ctx, "other-client"), "test-split")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitCache(t *testing.T) {
+	cache := newSplitCache(2, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	updatedAt := now.Add(-time.Hour)
+	a := &LoadSplitResponse{ID: "a", UpdatedAt: updatedAt}
+	cache.put(a)
+	assert.Same(t, a, cache.get("a", updatedAt))
+	assert.Nil(t, cache.get("a", updatedAt.Add(time.Second)), "another version misses")
+	assert.Nil(t, cache.get("a", updatedAt), "the stale entry is dropped")
+
+	// Entries expire after the TTL
+	cache.put(a)
+	now = now.Add(time.Minute)
+	assert.Nil(t, cache.get("a", updatedAt))
+
+	// The least recently used entry is evicted beyond the size
+	cache.put(a)
+	cache.put(&LoadSplitResponse{ID: "b", UpdatedAt: updatedAt})
+	cache.get("a", updatedAt)
+	cache.put(&LoadSplitResponse{ID: "c", UpdatedAt: updatedAt})
+	assert.NotNil(t, cache.get("a", updatedAt))
+	assert.Nil(t, cache.get("b", updatedAt))
+	assert.NotNil(t, cache.get("c", updatedAt))
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"net/url"
+	"sort"
+)
+
+// diffGroup is one document of a split, or the split's unassigned pages when doc is nil
+type diffGroup struct {
+	doc  *domain.Document
+	urls []string
+}
+
+func (g *diffGroup) documentID() *string {
+	if g.doc == nil {
+		return nil
+	}
+	id := g.doc.ID
+	return &id
+}
+
+// diffGroups returns the documents of a split in order, followed by its unassigned pages
+func diffGroups(split *domain.Split) []*diffGroup {
+	groups := make([]*diffGroup, 0, len(split.Documents)+1)
+	for i := range split.Documents {
+		doc := &split.Documents[i]
+		g := &diffGroup{doc: doc}
+		for _, page := range doc.Pages {
+			g.urls = append(g.urls, page.URL)
+		}
+		groups = append(groups, g)
+	}
+	unassigned := &diffGroup{}
+	for _, page := range split.UnassignedPages {
+		unassigned.urls = append(unassigned.urls, page.URL)
+	}
+	return append(groups, unassigned)
+}
+
+// diffSplits compares split b against split a. Pages are keyed by URL, since page
+// IDs differ across splits. Documents are paired greedily by the number of pages
+// they share, most first, and the unassigned pages of both splits always pair up;
+// a page is reassigned when it sits in groups that are not paired. Reported URLs
+// are resolved against base.
+func diffSplits(a, b *domain.Split, base *url.URL) *SplitDiff {
+	groupsA, groupsB := diffGroups(a), diffGroups(b)
+	inA := make(map[string]int)
+	for i, g := range groupsA {
+		for _, u := range g.urls {
+			inA[u] = i
+		}
+	}
+	inB := make(map[string]int)
+	for j, g := range groupsB {
+		for _, u := range g.urls {
+			inB[u] = j
+		}
+	}
+
+	type pair struct{ a, b, shared int }
+	var pairs []pair
+	for i, g := range groupsA {
+		if g.doc == nil {
+			continue
+		}
+		shared := make(map[int]int)
+		for _, u := range g.urls {
+			if j, ok := inB[u]; ok && groupsB[j].doc != nil {
+				shared[j]++
+			}
+		}
+		for j, n := range shared {
+			pairs = append(pairs, pair{a: i, b: j, shared: n})
+		}
+	}
+	sort.Slice(pairs, func(x, y int) bool {
+		if pairs[x].shared != pairs[y].shared {
+			return pairs[x].shared > pairs[y].shared
+		}
+		if pairs[x].a != pairs[y].a {
+			return pairs[x].a < pairs[y].a
+		}
+		return pairs[x].b < pairs[y].b
+	})
+	pairedA := map[int]int{len(groupsA) - 1: len(groupsB) - 1}
+	pairedB := map[int]int{len(groupsB) - 1: len(groupsA) - 1}
+	for _, p := range pairs {
+		if _, ok := pairedA[p.a]; ok {
+			continue
+		}
+		if _, ok := pairedB[p.b]; ok {
+			continue
+		}
+		pairedA[p.a] = p.b
+		pairedB[p.b] = p.a
+	}
+
+	diff := &SplitDiff{
+		SplitA:           a.ID,
+		SplitB:           b.ID,
+		DocumentsAdded:   make([]*DiffDocument, 0),
+		DocumentsRemoved: make([]*DiffDocument, 0),
+		PagesAdded:       make([]string, 0),
+		PagesRemoved:     make([]string, 0),
+		PagesReassigned:  make([]*PageReassignment, 0),
+	}
+	for i, g := range groupsA {
+		if _, ok := pairedA[i]; !ok {
+			diff.DocumentsRemoved = append(diff.DocumentsRemoved, newDiffDocument(g, base))
+		}
+		for _, u := range g.urls {
+			if _, ok := inB[u]; !ok {
+				diff.PagesRemoved = append(diff.PagesRemoved, resolvePageURL(base, u))
+			}
+		}
+	}
+	for j, g := range groupsB {
+		if _, ok := pairedB[j]; !ok {
+			diff.DocumentsAdded = append(diff.DocumentsAdded, newDiffDocument(g, base))
+		}
+		for _, u := range g.urls {
+			i, ok := inA[u]
+			if !ok {
+				diff.PagesAdded = append(diff.PagesAdded, resolvePageURL(base, u))
+				continue
+			}
+			if paired, ok := pairedA[i]; ok && paired == j {
+				continue
+			}
+			diff.PagesReassigned = append(diff.PagesReassigned, &PageReassignment{
+				URL:            resolvePageURL(base, u),
+				FromDocumentID: groupsA[i].documentID(),
+				ToDocumentID:   g.documentID(),
+			})
+		}
+	}
+	return diff
+}
+
+func newDiffDocument(g *diffGroup, base *url.URL) *DiffDocument {
+	d := &DiffDocument{
+		ID:             g.doc.ID,
+		Name:           g.doc.Name,
+		Classification: g.doc.Classification,
+		PageURLs:       make([]string, len(g.urls)),
+	}
+	for i, u := range g.urls {
+		d.PageURLs[i] = resolvePageURL(base, u)
+	}
+	return d
+}
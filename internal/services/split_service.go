@@ -3,9 +3,18 @@ package services
 import (
 	"accounting/internal/domain"
 	"accounting/internal/domain/ports"
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,35 +25,334 @@ var _ SplitServiceInterface = (*SplitService)(nil)
 
 // SplitService handles business logic for document splitting
 type SplitService struct {
-	uowFactory func() (ports.UnitOfWork, error)
-	renderSvc  ports.RenderService
+	uowFactory func(ctx context.Context) (ports.UnitOfWork, error)
+	// readUoWFactory begins the units of work of operations that only read (nil uses uowFactory)
+	readUoWFactory func(ctx context.Context) (ports.UnitOfWork, error)
+	renderSvc      ports.RenderService
+	blobStore      ports.BlobStore
+
+	// maxUnassignedPages is the advisory limit for unassigned pages (0 disables it)
+	maxUnassignedPages int
+	// allowedClassifications restricts reclassification targets (nil allows any)
+	allowedClassifications map[string]struct{}
+	// maxPageBatch caps the page IDs of a single move or create request (0 disables it)
+	maxPageBatch int
+	// documentNameTemplate names documents created without a name ("" requires a name)
+	documentNameTemplate string
+	// lockTTL is how long a split lock lasts before it expires
+	lockTTL time.Duration
+	// requireReview refuses to finalize splits with documents not marked reviewed
+	requireReview bool
+	// requireClassification refuses to finalize splits with unclassified documents
+	requireClassification bool
+	// finalizeEvents enqueues a split.finalized event in the outbox on every finalize
+	finalizeEvents bool
+	// pageURLBase resolves relative page URLs in responses (nil leaves them as stored)
+	pageURLBase *url.URL
+	// filenamePolicy sanitizes document filenames ("" leaves them as given)
+	filenamePolicy domain.FilenamePolicy
+	// splitCache holds LoadSplit responses (nil disables caching)
+	splitCache *splitCache
+	metrics    ports.MetricsRecorder
+}
+
+// DefaultLockTTL is how long a split lock lasts unless SetLockTTL changes it
+const DefaultLockTTL = 5 * time.Minute
+
+// MaxBatchGetSplits caps the split IDs of a single BatchGetSplits request
+const MaxBatchGetSplits = 100
+
+// MaxFinalizeSplits caps the split IDs of a single FinalizeSplits request
+const MaxFinalizeSplits = 100
+
+// MaxDeleteDocuments caps the document IDs of a single DeleteDocuments request
+const MaxDeleteDocuments = 100
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the authenticated subject making the request.
+// Split locks are held by, and checked against, this subject.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
 }
 
+// ActorFromContext returns the subject set by WithActor, or "" if there is none
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// noopMetricsRecorder discards metrics; it is the default until SetMetricsRecorder is called
+type noopMetricsRecorder struct{}
+
+func (noopMetricsRecorder) IncCounter(string, map[string]string) {}
+
+func (noopMetricsRecorder) ObserveDuration(string, time.Duration, map[string]string) {}
+
+// Metric names recorded through the MetricsRecorder
+const (
+	OperationDownloadDocument = "download_document"
+	OperationPreviewSplit     = "preview_split"
+
+	MetricSplitsFinalized  = "splits_finalized_total"
+	MetricSplitsDeleted    = "splits_deleted_total"
+	MetricDocumentsCreated = "documents_created_total"
+	MetricDocumentsDeleted = "documents_deleted_total"
+	MetricPageMoves        = "page_moves_total"
+)
+
 // NewSplitService creates a new SplitService
-func NewSplitService(uowFactory func() (ports.UnitOfWork, error), renderSvc ports.RenderService) *SplitService {
+func NewSplitService(uowFactory func(ctx context.Context) (ports.UnitOfWork, error), renderSvc ports.RenderService, blobStore ports.BlobStore) *SplitService {
 	return &SplitService{
 		uowFactory: uowFactory,
 		renderSvc:  renderSvc,
+		blobStore:  blobStore,
+		lockTTL:    DefaultLockTTL,
+		metrics:    noopMetricsRecorder{},
+	}
+}
+
+// SetReadUnitOfWorkFactory sets the factory read-only operations begin their units
+// of work with, e.g. on a read replica. Reads may then lag behind recent writes.
+func (s *SplitService) SetReadUnitOfWorkFactory(factory func(ctx context.Context) (ports.UnitOfWork, error)) {
+	s.readUoWFactory = factory
+}
+
+// readUoW begins a unit of work for an operation that does not write
+func (s *SplitService) readUoW(ctx context.Context) (ports.UnitOfWork, error) {
+	if s.readUoWFactory != nil {
+		return s.readUoWFactory(ctx)
+	}
+	return s.uowFactory(ctx)
+}
+
+// SetMetricsRecorder sets where business counters and operation latencies are recorded
+func (s *SplitService) SetMetricsRecorder(metrics ports.MetricsRecorder) {
+	s.metrics = metrics
+}
+
+// SetLockTTL sets how long a split lock lasts before it expires. A non-positive
+// TTL keeps the default.
+func (s *SplitService) SetLockTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	s.lockTTL = ttl
+}
+
+// SetRequireReview sets whether every document must be marked reviewed before its
+// split can be finalized
+func (s *SplitService) SetRequireReview(require bool) {
+	s.requireReview = require
+}
+
+// reviewIssue returns a validation error listing the unreviewed documents of split
+// when reviews are required, or nil
+func (s *SplitService) reviewIssue(split *domain.Split) error {
+	if !s.requireReview {
+		return nil
+	}
+	if ids := split.UnreviewedDocuments(); len(ids) > 0 {
+		return domain.NewValidationError(fmt.Sprintf("cannot finalize split with unreviewed documents: %s", strings.Join(ids, ", ")), nil)
+	}
+	return nil
+}
+
+// SetFinalizeEvents sets whether finalizing a split enqueues a split.finalized event in
+// the outbox. Only enable it when a dispatcher delivers the events, as nothing else
+// removes them.
+func (s *SplitService) SetFinalizeEvents(enabled bool) {
+	s.finalizeEvents = enabled
+}
+
+// SetRequireClassification sets whether every document must have a classification
+// other than the default "unclassified" before its split can be finalized
+func (s *SplitService) SetRequireClassification(require bool) {
+	s.requireClassification = require
+}
+
+// classificationIssue returns a validation error listing the documents of split with
+// no classification or the default one when classifications are required, or nil
+func (s *SplitService) classificationIssue(split *domain.Split) error {
+	if !s.requireClassification {
+		return nil
+	}
+	var ids []string
+	for _, doc := range split.Documents {
+		if doc.Classification == "" || strings.EqualFold(doc.Classification, unclassifiedKey) {
+			ids = append(ids, doc.ID)
+		}
+	}
+	if len(ids) > 0 {
+		return domain.NewValidationError(fmt.Sprintf("cannot finalize split with unclassified documents: %s", strings.Join(ids, ", ")), nil)
+	}
+	return nil
+}
+
+// policyIssues returns the configured finalize requirements split does not meet
+func (s *SplitService) policyIssues(split *domain.Split) []error {
+	var errs []error
+	if err := s.reviewIssue(split); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.classificationIssue(split); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// checkLock rejects changes to a split locked by someone other than the context's actor
+func (s *SplitService) checkLock(ctx context.Context, uow ports.UnitOfWork, splitID string) error {
+	lock, err := uow.LockRepository().Get(ctx, splitID)
+	if err != nil {
+		return err
+	}
+	if lock != nil && lock.BlocksHolder(ActorFromContext(ctx), time.Now()) {
+		return lockedError(lock)
+	}
+	return nil
+}
+
+func lockedError(lock *domain.SplitLock) error {
+	return domain.NewLockedError(fmt.Sprintf("split is locked by %s until %s", lock.Holder, lock.ExpiresAt.UTC().Format(time.RFC3339)), nil)
+}
+
+// SetPageURLBase sets the base URL that relative page URLs such as "page_1.png"
+// are resolved against in responses. Stored URLs are not changed. An empty base
+// leaves URLs as stored.
+func (s *SplitService) SetPageURLBase(base string) error {
+	parsed, err := parsePageURLBase(base)
+	if err != nil {
+		return err
+	}
+	s.pageURLBase = parsed
+	return nil
+}
+
+// SetMaxUnassignedPages sets the number of unassigned pages above which split
+// responses carry an unassigned_warning. A non-positive limit disables the warning.
+func (s *SplitService) SetMaxUnassignedPages(limit int) {
+	s.maxUnassignedPages = limit
+}
+
+// SetMaxPageBatch caps the number of page IDs a single move or create-document
+// request may carry. A non-positive limit disables the cap.
+func (s *SplitService) SetMaxPageBatch(limit int) {
+	s.maxPageBatch = limit
+}
+
+// checkPageBatch rejects page ID batches above the configured cap before any database work
+func (s *SplitService) checkPageBatch(pageIDs []string) error {
+	if s.maxPageBatch > 0 && len(pageIDs) > s.maxPageBatch {
+		return domain.NewValidationError(fmt.Sprintf("too many page IDs: %d exceeds the limit of %d per request", len(pageIDs), s.maxPageBatch), nil)
+	}
+	return nil
+}
+
+// SetDocumentNameTemplate sets the template used to name documents created without
+// a name, e.g. "{classification} ({n})". Explicit names are always kept. An empty
+// template disables automatic naming.
+func (s *SplitService) SetDocumentNameTemplate(template string) {
+	s.documentNameTemplate = template
+}
+
+// SetAllowedClassifications restricts the classifications documents can be
+// reclassified to. An empty list allows any classification.
+func (s *SplitService) SetAllowedClassifications(classifications []string) {
+	if len(classifications) == 0 {
+		s.allowedClassifications = nil
+		return
+	}
+	s.allowedClassifications = make(map[string]struct{}, len(classifications))
+	for _, c := range classifications {
+		s.allowedClassifications[c] = struct{}{}
+	}
+}
+
+// SetFilenamePolicy sets how document filenames are sanitized when documents are
+// created or updated, and when they are downloaded. An empty policy leaves them as given.
+func (s *SplitService) SetFilenamePolicy(policy domain.FilenamePolicy) {
+	s.filenamePolicy = policy
+}
+
+// sanitizeFilename applies the filename policy to a filename being stored
+func (s *SplitService) sanitizeFilename(name string) (string, error) {
+	if s.filenamePolicy == "" {
+		return name, nil
+	}
+	return domain.SanitizeFilename(name, s.filenamePolicy)
+}
+
+// downloadFilename sanitizes a stored filename for a download. Filenames stored
+// before the policy applied are replaced rather than refused, so they still download.
+func (s *SplitService) downloadFilename(name string) string {
+	policy := s.filenamePolicy
+	if policy == "" {
+		return name
+	}
+	if policy == domain.FilenamePolicyReject {
+		policy = domain.FilenamePolicyReplace
+	}
+	sanitized, _ := domain.SanitizeFilename(name, policy)
+	return sanitized
+}
+
+// SetSplitCache caches up to size LoadSplit responses for ttl (zero never expires).
+// Every mutation of a split through the service invalidates its entry, and a split
+// updated elsewhere is reloaded as its UpdatedAt no longer matches. A size of zero
+// or less disables caching.
+func (s *SplitService) SetSplitCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		s.splitCache = nil
+		return
+	}
+	s.splitCache = newSplitCache(size, ttl)
+}
+
+// splitResponse converts a split to a response, resolves page URLs and applies the unassigned pages warning
+func (s *SplitService) splitResponse(split *domain.Split) *LoadSplitResponse {
+	resp := convertSplitToResponse(split)
+	for _, doc := range resp.Documents {
+		s.resolvePageURLs(doc)
+	}
+	for _, page := range resp.UnassignedPages {
+		page.URL = resolvePageURL(s.pageURLBase, page.URL)
+	}
+	resp.UnassignedWarning = split.ExceedsUnassignedPageLimit(s.maxUnassignedPages)
+	return resp
+}
+
+// documentResponse converts a document to a response with resolved page URLs
+func (s *SplitService) documentResponse(doc *domain.Document) *DocumentResponse {
+	resp := convertDocumentToResponse(doc)
+	s.resolvePageURLs(resp)
+	return resp
+}
+
+// resolvePageURLs resolves the page URLs of a document response against the page URL base
+func (s *SplitService) resolvePageURLs(doc *DocumentResponse) {
+	for _, page := range doc.Pages {
+		page.URL = resolvePageURL(s.pageURLBase, page.URL)
 	}
 }
 
-// convertPageToResponse converts a domain page to a page response
+// convertPageToResponse converts a domain page to a page response, whether the
+// page is assigned to a document or not
 func convertPageToResponse(page *domain.Page) *PageResponse {
 	return &PageResponse{
 		ID:         page.ID,
-		PageNumber: fmt.Sprintf("%d", page.PageNumber),
+		PageNumber: strconv.Itoa(page.PageNumber),
+		Number:     page.PageNumber,
+		URL:        page.URL,
 	}
 }
 
-// convertDocumentToResponse converts a domain document to a document response
+// convertDocumentToResponse converts a domain document to a document response.
+// Pages keep the document's order, which is ascending page number.
 func convertDocumentToResponse(doc *domain.Document) *DocumentResponse {
 	pages := make([]*PageResponse, len(doc.Pages))
 	for i, page := range doc.Pages {
-		pages[i] = &PageResponse{
-			ID:         page.ID,
-			PageNumber: strconv.Itoa(page.PageNumber),
-			URL:        page.URL,
-		}
+		pages[i] = convertPageToResponse(page)
 	}
 	return &DocumentResponse{
 		ID:               doc.ID,
@@ -55,340 +363,1953 @@ func convertDocumentToResponse(doc *domain.Document) *DocumentResponse {
 		ShortDescription: doc.ShortDescription,
 		StartPage:        doc.StartPage,
 		EndPage:          doc.EndPage,
+		StartPageNumber:  doc.StartPageNumber(),
+		EndPageNumber:    doc.EndPageNumber(),
+		PageRange:        pageRange(doc.StartPageNumber(), doc.EndPageNumber()),
+		Contiguous:       doc.HasContiguousPages(),
+		SortOrder:        doc.SortOrder,
+		Reviewed:         doc.Reviewed,
+		ReviewedBy:       doc.ReviewedBy,
+		ReviewedAt:       doc.ReviewedAt,
+		LastModifiedBy:   doc.LastModifiedBy,
 		Pages:            pages,
 	}
 }
 
-// LoadSplit loads a split by ID
-func (s *SplitService) LoadSplit(ctx context.Context, id string) (*LoadSplitResponse, error) {
-	uow, err := s.uowFactory()
+// pageRange formats the page numbers from start to end for display: "3-7", "3" for a
+// single page, or "" when there are no pages
+func pageRange(start, end int) string {
+	switch {
+	case start == 0:
+		return ""
+	case start == end:
+		return strconv.Itoa(start)
+	default:
+		return fmt.Sprintf("%d-%d", start, end)
+	}
+}
+
+// convertSplitToResponse converts a domain split to a split response.
+// Documents and unassigned pages keep the order in which the split holds them.
+func convertSplitToResponse(split *domain.Split) *LoadSplitResponse {
+	// Convert domain documents to response documents
+	documents := make([]*DocumentResponse, len(split.Documents))
+	for i, doc := range split.Documents {
+		documents[i] = convertDocumentToResponse(&doc)
+	}
+
+	// Convert unassigned pages to response pages
+	unassignedPages := make([]*PageResponse, len(split.UnassignedPages))
+	for i, page := range split.UnassignedPages {
+		unassignedPages[i] = convertPageToResponse(page)
+	}
+
+	// Tally the documents per classification
+	counts := make(map[string]int)
+	for _, doc := range split.Documents {
+		countClassification(counts, doc.Classification)
+	}
+	unassignedCount := len(split.UnassignedPages)
+	completion := split.CompletionPercent()
+
+	return &LoadSplitResponse{
+		ID:                   split.ID,
+		ClientID:             split.ClientID,
+		Name:                 split.Name,
+		Status:               split.Status,
+		Documents:            documents,
+		UnassignedPages:      unassignedPages,
+		ClassificationCounts: counts,
+		UnassignedPageCount:  &unassignedCount,
+		CompletionPercent:    &completion,
+		Tags:                 splitTags(split),
+		CreatedAt:            split.CreatedAt.UTC(),
+		UpdatedAt:            split.UpdatedAt.UTC(),
+		LastModifiedBy:       split.LastModifiedBy,
+	}
+}
+
+// splitTags returns the tags of a split for a response, empty rather than nil
+func splitTags(split *domain.Split) []string {
+	if split.Tags == nil {
+		return []string{}
+	}
+	return split.Tags
+}
+
+// unclassifiedKey counts documents without a classification in classification counts
+const unclassifiedKey = "unclassified"
+
+// countClassification adds a document with the given classification to counts
+func countClassification(counts map[string]int, classification string) {
+	if classification == "" {
+		classification = unclassifiedKey
+	}
+	counts[classification]++
+}
+
+// LoadSplit loads a split by ID
+func (s *SplitService) LoadSplit(ctx context.Context, id string) (*LoadSplitResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	if s.splitCache != nil {
+		// Loading the split without its children is enough to check the cached version
+		meta, err := uow.SplitRepository().GetParts(ctx, id, domain.SplitParts{})
+		if err != nil {
+			return nil, err
+		}
+		meta = visibleSplit(ctx, meta)
+		if meta == nil {
+			return nil, domain.NewNotFoundError("split", id, "split not found", nil)
+		}
+		if resp := s.splitCache.get(id, meta.UpdatedAt); resp != nil {
+			return resp, nil
+		}
+	}
+
+	split, err := uow.SplitRepository().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	split = visibleSplit(ctx, split)
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+
+	resp := s.splitResponse(split)
+	s.splitCache.put(resp)
+	return resp, nil
+}
+
+// LoadSplitParts loads a split with only the requested parts; the loads of the
+// excluded parts are skipped and they are null in the response
+func (s *SplitService) LoadSplitParts(ctx context.Context, req LoadSplitRequest) (*LoadSplitResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().GetParts(ctx, req.ID, domain.SplitParts{
+		Documents:       req.IncludeDocuments,
+		UnassignedPages: req.IncludeUnassigned,
+	})
+	if err != nil {
+		return nil, err
+	}
+	split = visibleSplit(ctx, split)
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", req.ID, "split not found", nil)
+	}
+
+	resp := s.splitResponse(split)
+	if !req.IncludeDocuments {
+		resp.Documents = nil
+		resp.ClassificationCounts = nil
+	}
+	if !req.IncludeUnassigned {
+		resp.UnassignedPages = nil
+		resp.UnassignedPageCount = nil
+	}
+	if !req.IncludeDocuments || !req.IncludeUnassigned {
+		// The completion needs the pages of both parts
+		resp.CompletionPercent = nil
+	}
+	return resp, nil
+}
+
+// streamedSplitHead is a LoadSplitResponse without its documents, which
+// StreamSplit writes separately; the outer field shadows the embedded one
+type streamedSplitHead struct {
+	*LoadSplitResponse
+	Documents json.RawMessage `json:"documents,omitempty"`
+}
+
+// StreamSplit writes the JSON of LoadSplit to w, encoding the documents one at a
+// time as they are read instead of building the whole response in memory. The
+// documents come first in the object; the other fields are the same. A missing
+// split is reported before anything is written.
+func (s *SplitService) StreamSplit(ctx context.Context, id string, w io.Writer) error {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+
+	repo := uow.SplitRepository()
+	split, err := repo.GetParts(ctx, id, domain.SplitParts{UnassignedPages: true})
+	if err != nil {
+		return err
+	}
+	split = visibleSplit(ctx, split)
+	if split == nil {
+		return domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+	resp := s.splitResponse(split)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"documents":[`)
+	first := true
+	assigned := 0
+	err = repo.EachDocument(ctx, id, func(doc *domain.Document) error {
+		countClassification(resp.ClassificationCounts, doc.Classification)
+		assigned += len(doc.Pages)
+		b, err := json.Marshal(s.documentResponse(doc))
+		if err != nil {
+			return fmt.Errorf("error encoding document: %w", err)
+		}
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		_, err = bw.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// The head goes last so it can carry the counts tallied while streaming
+	completion := domain.CompletionPercent(assigned, len(split.UnassignedPages))
+	resp.CompletionPercent = &completion
+	head, err := json.Marshal(streamedSplitHead{LoadSplitResponse: resp})
+	if err != nil {
+		return fmt.Errorf("error encoding split: %w", err)
+	}
+	bw.WriteString("],")
+	bw.Write(head[1:])
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+// BatchGetSplits loads several splits with one repository call instead of one per ID.
+// Splits are returned in the order requested; IDs that do not exist are listed in Missing.
+func (s *SplitService) BatchGetSplits(ctx context.Context, req BatchGetSplitsRequest) (*BatchGetSplitsResponse, error) {
+	if len(req.IDs) == 0 {
+		return nil, domain.NewValidationError("split IDs are required", nil)
+	}
+	if len(req.IDs) > MaxBatchGetSplits {
+		return nil, domain.NewValidationError(fmt.Sprintf("too many split IDs: %d exceeds the limit of %d per request", len(req.IDs), MaxBatchGetSplits), nil)
+	}
+
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splits, err := uow.SplitRepository().GetMany(ctx, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BatchGetSplitsResponse{
+		Splits:  make([]*LoadSplitResponse, 0, len(splits)),
+		Missing: make([]string, 0),
+	}
+	seen := make(map[string]struct{}, len(req.IDs))
+	for _, split := range splits {
+		if visibleSplit(ctx, split) == nil {
+			continue
+		}
+		resp.Splits = append(resp.Splits, s.splitResponse(split))
+		seen[split.ID] = struct{}{}
+	}
+	for _, id := range req.IDs {
+		if _, ok := seen[id]; !ok {
+			resp.Missing = append(resp.Missing, id)
+			seen[id] = struct{}{}
+		}
+	}
+	return resp, nil
+}
+
+// DiffSplits reports the documents and pages added, removed or reassigned in split b
+// compared to split a
+func (s *SplitService) DiffSplits(ctx context.Context, aID, bID string) (*SplitDiff, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	var splits [2]*domain.Split
+	for i, id := range []string{aID, bID} {
+		split, err := uow.SplitRepository().Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		split = visibleSplit(ctx, split)
+		if split == nil {
+			return nil, domain.NewNotFoundError("split", id, "split not found", nil)
+		}
+		splits[i] = split
+	}
+
+	return diffSplits(splits[0], splits[1], s.pageURLBase), nil
+}
+
+// ReorderDocuments sets a custom order for the documents of a split
+func (s *SplitService) ReorderDocuments(ctx context.Context, splitID string, req ReorderDocumentsRequest) (*LoadSplitResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	// Reorder documents using domain logic
+	if err := split.ReorderDocuments(req.DocumentIDs); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx))
+
+	// Save the aggregate
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	return s.splitResponse(split), nil
+}
+
+// RenameSplit sets a split's display name; an empty name clears it
+func (s *SplitService) RenameSplit(ctx context.Context, splitID, name string) (*LoadSplitResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	if err := split.Rename(name); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx))
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	return s.splitResponse(split), nil
+}
+
+// AddSplitTags adds tags to a split; tags it already has are ignored
+func (s *SplitService) AddSplitTags(ctx context.Context, splitID string, tags []string) (*LoadSplitResponse, error) {
+	return s.updateSplitTags(ctx, splitID, func(split *domain.Split) error {
+		return split.AddTags(tags)
+	})
+}
+
+// RemoveSplitTags removes tags from a split; tags it does not have are ignored
+func (s *SplitService) RemoveSplitTags(ctx context.Context, splitID string, tags []string) (*LoadSplitResponse, error) {
+	return s.updateSplitTags(ctx, splitID, func(split *domain.Split) error {
+		return split.RemoveTags(tags)
+	})
+}
+
+// updateSplitTags applies a change of tags to a split and saves it
+func (s *SplitService) updateSplitTags(ctx context.Context, splitID string, change func(*domain.Split) error) (*LoadSplitResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	if err := change(split); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx))
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	return s.splitResponse(split), nil
+}
+
+// UpdateDocumentMetadata updates document metadata
+func (s *SplitService) UpdateDocumentMetadata(ctx context.Context, id string, req UpdateDocumentMetadataRequest) (*DocumentResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	// Get split ID for the document
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if splitID == "" {
+		return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+	}
+
+	// Load split aggregate
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	// Convert request to domain metadata
+	metadata := domain.DocumentMetadata{
+		Name:             req.Name,
+		Classification:   req.Classification,
+		ShortDescription: req.ShortDescription,
+	}
+	if req.Filename != nil {
+		filename, err := s.sanitizeFilename(*req.Filename)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Filename = &filename
+	}
+
+	// Update document metadata using domain logic
+	if err := split.UpdateDocumentMetadata(id, metadata); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx), id)
+
+	// Save the aggregate
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	// Find the updated document
+	for _, doc := range split.Documents {
+		if doc.ID == id {
+			return s.documentResponse(&doc), nil
+		}
+	}
+
+	return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
+
+// ReviewDocument marks a document reviewed by the context's actor, or clears its
+// review when reviewed is false
+func (s *SplitService) ReviewDocument(ctx context.Context, id string, reviewed bool) (*DocumentResponse, error) {
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, domain.NewValidationError("reviewing a document requires an authenticated subject", nil)
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := split.ReviewDocument(id, reviewed, actor, now); err != nil {
+		return nil, err
+	}
+	split.Touch(now, actor, id)
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	for _, doc := range split.Documents {
+		if doc.ID == id {
+			return s.documentResponse(&doc), nil
+		}
+	}
+
+	return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
+
+// ReclassifyDocument changes a document's classification and records the change
+// in the classification history within the same transaction
+func (s *SplitService) ReclassifyDocument(ctx context.Context, id string, classification string) (*DocumentResponse, error) {
+	if s.allowedClassifications != nil {
+		if _, ok := s.allowedClassifications[classification]; !ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("classification %q is not allowed", classification), nil)
+		}
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if splitID == "" {
+		return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+	}
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	old, err := split.ReclassifyDocument(id, classification)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	split.Touch(now, ActorFromContext(ctx), id)
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+	if err := uow.SplitRepository().AddClassificationChange(ctx, &domain.ClassificationChange{
+		ID:                uuid.NewString(),
+		DocumentID:        id,
+		OldClassification: old,
+		NewClassification: classification,
+		ChangedAt:         now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	for _, doc := range split.Documents {
+		if doc.ID == id {
+			return s.documentResponse(&doc), nil
+		}
+	}
+
+	return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
+
+// MoveDocumentToSplit moves a document with its pages from its split to the
+// target split. Both aggregates are saved in one transaction, so the document
+// is never in both or neither split.
+func (s *SplitService) MoveDocumentToSplit(ctx context.Context, docID, targetSplitID string) (*DocumentResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	sourceID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceID == "" {
+		return nil, domain.NewNotFoundError("document", docID, "document not found", nil)
+	}
+	if sourceID == targetSplitID {
+		return nil, domain.NewValidationError(fmt.Sprintf("document %s is already in split %s", docID, targetSplitID), nil)
+	}
+
+	source, err := uow.SplitRepository().Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, domain.NewNotFoundError("split", sourceID, "split not found", nil)
+	}
+	target, err := uow.SplitRepository().Get(ctx, targetSplitID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, domain.NewNotFoundError("split", targetSplitID, "split not found", nil)
+	}
+	if source.ClientID != target.ClientID {
+		return nil, domain.NewValidationError(fmt.Sprintf("document %s cannot move to split %s of another client", docID, targetSplitID), nil)
+	}
+	for _, id := range []string{source.ID, target.ID} {
+		if err := s.checkLock(ctx, uow, id); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := source.DetachDocument(docID)
+	if err != nil {
+		return nil, err
+	}
+	actor := ActorFromContext(ctx)
+	doc.LastModifiedBy = actor
+	if err := target.AttachDocument(doc); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	source.Touch(now, actor)
+	target.Touch(now, actor)
+
+	// The target goes first: once the rows point at it, saving the source no
+	// longer sees them as its own to delete
+	if err := uow.SplitRepository().Save(ctx, target); err != nil {
+		return nil, err
+	}
+	if err := uow.SplitRepository().Save(ctx, source); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(source.ID, target.ID)
+
+	return s.documentResponse(doc), nil
+}
+
+// MovePages moves pages between documents
+func (s *SplitService) MovePages(ctx context.Context, req MovePagesRequest) (*MovePagesResponse, error) {
+	if err := s.checkPageBatch(req.PageIDs); err != nil {
+		return nil, err
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, req.SplitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", req.SplitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	// Use domain logic to move pages
+	if err := split.MovePages(req.FromDocumentID, req.ToDocumentID, req.PageIDs); err != nil {
+		return nil, err
+	}
+
+	// Find the updated documents
+	var fromDoc, toDoc *domain.Document
+	for i := range split.Documents {
+		if split.Documents[i].ID == req.FromDocumentID {
+			fromDoc = &split.Documents[i]
+		}
+		if split.Documents[i].ID == req.ToDocumentID {
+			toDoc = &split.Documents[i]
+		}
+	}
+
+	if fromDoc == nil {
+		return nil, domain.NewNotFoundError("document", req.FromDocumentID, "source document not found", nil)
+	}
+	if toDoc == nil {
+		return nil, domain.NewNotFoundError("document", req.ToDocumentID, "target document not found", nil)
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx), fromDoc.ID, toDoc.ID)
+	if len(req.PageIDs) == 1 && !toDoc.HasPageOrder() {
+		// Fast path: the move is already validated, so persist only what changed. Pages
+		// joining an explicitly ordered document need their position saved too.
+		if err := s.persistPageMove(ctx, uow.SplitRepository(), split, req.PageIDs[0], fromDoc, toDoc); err != nil {
+			return nil, err
+		}
+	} else {
+		// Save the aggregate
+		if err := uow.SplitRepository().Save(ctx, split); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+	s.metrics.IncCounter(MetricPageMoves, nil)
+
+	return &MovePagesResponse{
+		FromDocument: s.documentResponse(fromDoc),
+		ToDocument:   s.documentResponse(toDoc),
+	}, nil
+}
+
+// SwapPages swaps the positions of two pages within a document
+func (s *SplitService) SwapPages(ctx context.Context, id string, req SwapPagesRequest) (*DocumentResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	if err := split.SwapPages(id, req.PageA, req.PageB); err != nil {
+		return nil, err
+	}
+	split.Touch(time.Now(), ActorFromContext(ctx), id)
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	for _, doc := range split.Documents {
+		if doc.ID == id {
+			return s.documentResponse(&doc), nil
+		}
+	}
+
+	return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
+
+// CountDocumentPages returns the number of pages of a document without loading the
+// split or the pages
+func (s *SplitService) CountDocumentPages(ctx context.Context, id string) (*PageCountResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if OnBehalfOfFromContext(ctx) != "" {
+		split, err := uow.SplitRepository().GetParts(ctx, splitID, domain.SplitParts{})
+		if err != nil {
+			return nil, err
+		}
+		if visibleSplit(ctx, split) == nil {
+			return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+		}
+	}
+
+	count, err := uow.SplitRepository().CountPagesByDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &PageCountResponse{Count: count}, nil
+}
+
+// persistPageMove writes a validated single-page move with targeted updates
+// instead of re-saving the whole aggregate
+func (s *SplitService) persistPageMove(ctx context.Context, repo domain.SplitRepository, split *domain.Split, pageID string, fromDoc, toDoc *domain.Document) error {
+	if err := repo.ReassignPage(ctx, pageID, &toDoc.ID); err != nil {
+		return err
+	}
+	if err := repo.TouchSplit(ctx, split.ID, split.UpdatedAt, split.LastModifiedBy); err != nil {
+		return err
+	}
+	if err := repo.UpdateDocumentPageRange(ctx, fromDoc); err != nil {
+		return err
+	}
+	return repo.UpdateDocumentPageRange(ctx, toDoc)
+}
+
+// CreateDocument creates a new document from the pages listed by ID or, when
+// req.PageRange is set, from the unassigned pages numbered within the range
+func (s *SplitService) CreateDocument(ctx context.Context, req CreateDocumentRequest) (*DocumentResponse, error) {
+	var pageRange domain.PageRange
+	if req.PageRange != "" {
+		if len(req.PageIDs) > 0 {
+			return nil, domain.NewValidationError("page_ids and page_range cannot both be given", nil)
+		}
+		var err error
+		if pageRange, err = domain.ParsePageRange(req.PageRange); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkPageBatch(req.PageIDs); err != nil {
+		return nil, err
+	}
+	filename, err := s.sanitizeFilename(req.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, req.SplitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", req.SplitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+	if req.PageRange != "" {
+		if req.PageIDs, err = split.UnassignedPageIDsInRange(pageRange); err != nil {
+			return nil, err
+		}
+		if err := s.checkPageBatch(req.PageIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Generate a new UUID for the document ID
+	docID := uuid.NewString()
+
+	// Collect pages by ID from split's unassigned pages
+	var pages []*domain.Page
+	remainingUnassigned := make([]*domain.Page, 0, len(split.UnassignedPages))
+	pageIDSet := make(map[string]struct{}, len(req.PageIDs))
+	for _, pid := range req.PageIDs {
+		pageIDSet[pid] = struct{}{}
+	}
+	for _, page := range split.UnassignedPages {
+		if _, ok := pageIDSet[page.ID]; ok {
+			pages = append(pages, page)
+			delete(pageIDSet, page.ID)
+		} else {
+			remainingUnassigned = append(remainingUnassigned, page)
+		}
+	}
+	// Whatever is left is assigned to a document or belongs to another split
+	if len(pageIDSet) > 0 {
+		var unknown []string
+		for _, pid := range req.PageIDs {
+			if _, ok := pageIDSet[pid]; ok {
+				unknown = append(unknown, pid)
+				delete(pageIDSet, pid)
+			}
+		}
+		return nil, domain.NewValidationError(fmt.Sprintf("pages not unassigned in split %s: %s", req.SplitID, strings.Join(unknown, ", ")), nil)
+	}
+	if len(pages) == 0 {
+		return nil, domain.NewValidationError("no valid pages specified for new document", nil)
+	}
+
+	// Remove assigned pages from unassigned list
+	split.UnassignedPages = remainingUnassigned
+
+	name := req.Name
+	if name == "" && s.documentNameTemplate != "" {
+		name = split.GenerateDocumentName(s.documentNameTemplate, req.Classification)
+	}
+
+	// Create document using domain logic
+	doc := &domain.Document{
+		ID:               docID,
+		SplitID:          req.SplitID,
+		Name:             name,
+		Classification:   req.Classification,
+		Filename:         filename,
+		ShortDescription: req.ShortDescription,
+		Pages:            pages,
+		LastModifiedBy:   ActorFromContext(ctx),
+	}
+
+	if err := split.AddDocument(doc); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), doc.LastModifiedBy)
+
+	// Save the aggregate
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+	s.metrics.IncCounter(MetricDocumentsCreated, nil)
+
+	return s.documentResponse(doc), nil
+}
+
+// DeleteDocument deletes a document
+func (s *SplitService) DeleteDocument(ctx context.Context, id string) error {
+	uow, factErr := s.uowFactory(ctx)
+	if factErr != nil {
+		return factErr
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := s.deleteDocument(ctx, uow, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return err
+	}
+	s.splitCache.invalidate(splitID)
+	s.metrics.IncCounter(MetricDocumentsDeleted, nil)
+	return nil
+}
+
+// DeleteDocuments deletes several documents in one transaction. Each document is
+// deleted under its own savepoint, so one that fails is rolled back and reported
+// while the others are still committed.
+func (s *SplitService) DeleteDocuments(ctx context.Context, documentIDs []string) (*DeleteDocumentsResponse, error) {
+	if len(documentIDs) == 0 {
+		return nil, domain.NewValidationError("document IDs are required", nil)
+	}
+	if len(documentIDs) > MaxDeleteDocuments {
+		return nil, domain.NewValidationError(fmt.Sprintf("too many document IDs: %d exceeds the limit of %d per request", len(documentIDs), MaxDeleteDocuments), nil)
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	resp := &DeleteDocumentsResponse{Results: make([]*DeleteDocumentResult, 0, len(documentIDs))}
+	touched := make(map[string]struct{})
+	seen := make(map[string]struct{}, len(documentIDs))
+	for _, id := range documentIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if err := uow.Savepoint(ctx, "delete_document"); err != nil {
+			return nil, err
+		}
+		result := &DeleteDocumentResult{DocumentID: id}
+		splitID, delErr := s.deleteDocument(ctx, uow, id)
+		if delErr != nil {
+			if err := uow.RollbackTo(ctx, "delete_document"); err != nil {
+				return nil, err
+			}
+			result.Outcome = DeleteOutcomeFailed
+			result.Error = delErr.Error()
+			resp.Failed++
+		} else {
+			if err := uow.Release(ctx, "delete_document"); err != nil {
+				return nil, err
+			}
+			result.Outcome = DeleteOutcomeDeleted
+			touched[splitID] = struct{}{}
+			resp.Deleted++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	for splitID := range touched {
+		s.splitCache.invalidate(splitID)
+	}
+	for i := 0; i < resp.Deleted; i++ {
+		s.metrics.IncCounter(MetricDocumentsDeleted, nil)
+	}
+	return resp, nil
+}
+
+// deleteDocument removes a document from its split within uow and returns the
+// split's ID; the caller commits
+func (s *SplitService) deleteDocument(ctx context.Context, uow ports.UnitOfWork, id string) (string, error) {
+	// Get split ID for the document
+	splitID, getByErr := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if getByErr != nil {
+		return "", getByErr
+	}
+	if splitID == "" {
+		return "", domain.NewNotFoundError("document", id, "document not found", nil)
+	}
+
+	// Load split aggregate
+	split, getErr := uow.SplitRepository().Get(ctx, splitID)
+	if getErr != nil {
+		return "", getErr
+	}
+	if split == nil {
+		return "", domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return "", err
+	}
+
+	// Delete document using domain logic
+	if remErr := split.RemoveDocument(id); remErr != nil {
+		return "", remErr
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx))
+
+	// Save the aggregate
+	if saveErr := uow.SplitRepository().Save(ctx, split); saveErr != nil {
+		return "", saveErr
+	}
+	return split.ID, nil
+}
+
+// DeletePages drops pages from a document and the split. Save removes the
+// page rows that are no longer part of the aggregate.
+func (s *SplitService) DeletePages(ctx context.Context, id string, req DeletePagesRequest) (*DocumentResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if splitID == "" {
+		return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+	}
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	if err := split.DeletePages(id, req.PageIDs); err != nil {
+		return nil, err
+	}
+
+	split.Touch(time.Now(), ActorFromContext(ctx), id)
+
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+
+	for _, doc := range split.Documents {
+		if doc.ID == id {
+			return s.documentResponse(&doc), nil
+		}
+	}
+
+	return nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
+
+// FinalizeSplit finalizes a split. Finalizing an already finalized split is a no-op
+// that reports the original finalization time with AlreadyFinalized set.
+func (s *SplitService) FinalizeSplit(ctx context.Context, id string) (*FinalizeSplitResponse, error) {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+	if split.Status == domain.SplitStatusFinalized {
+		// A retried finalize changes nothing, so it needs no lock and enqueues no event
+		return &FinalizeSplitResponse{
+			SplitID:          split.ID,
+			FinalizedAt:      split.FinalizedAt,
+			AlreadyFinalized: true,
+		}, nil
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return nil, err
+	}
+
+	// Refuse with everything blocking the finalize at once, not just the first problem
+	blockers := split.FinalizeBlockers()
+	blockers.Issues = append(blockers.Issues, s.policyIssues(split)...)
+	if !blockers.Empty() {
+		return nil, domain.NewValidationError("cannot finalize split", blockers)
+	}
+
+	// Finalize split using domain logic
+	now := time.Now()
+	if err := split.Finalize(now); err != nil {
+		return nil, err
+	}
+
+	split.Touch(now, ActorFromContext(ctx))
+
+	// Save the aggregate
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		return nil, err
+	}
+
+	// Enqueue the webhook in the same transaction so it is sent if and only if the finalize commits
+	if s.finalizeEvents {
+		payload, err := json.Marshal(domain.SplitFinalizedEvent{
+			Event:       domain.EventSplitFinalized,
+			SplitID:     split.ID,
+			ClientID:    split.ClientID,
+			FinalizedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := uow.OutboxRepository().Add(ctx, &domain.OutboxMessage{
+			ID:        uuid.NewString(),
+			EventType: domain.EventSplitFinalized,
+			Payload:   payload,
+			CreatedAt: now,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.splitCache.invalidate(split.ID)
+	s.metrics.IncCounter(MetricSplitsFinalized, nil)
+	return &FinalizeSplitResponse{
+		SplitID:     split.ID,
+		FinalizedAt: split.FinalizedAt,
+	}, nil
+}
+
+// FinalizeSplits finalizes each split in its own transaction, as FinalizeSplit does,
+// so that a split that cannot be finalized does not hold back the others. Every
+// split gets a result, in the order requested; repeated IDs are finalized once.
+func (s *SplitService) FinalizeSplits(ctx context.Context, splitIDs []string) (*FinalizeSplitsResponse, error) {
+	if len(splitIDs) == 0 {
+		return nil, domain.NewValidationError("split IDs are required", nil)
+	}
+	if len(splitIDs) > MaxFinalizeSplits {
+		return nil, domain.NewValidationError(fmt.Sprintf("too many split IDs: %d exceeds the limit of %d per request", len(splitIDs), MaxFinalizeSplits), nil)
+	}
+
+	resp := &FinalizeSplitsResponse{Results: make([]*FinalizeSplitResult, 0, len(splitIDs))}
+	seen := make(map[string]struct{}, len(splitIDs))
+	for _, id := range splitIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		result := &FinalizeSplitResult{SplitID: id}
+		finalized, err := s.FinalizeSplit(ctx, id)
+		switch {
+		case err != nil:
+			result.Outcome = FinalizeOutcomeFailed
+			result.Error = err.Error()
+			resp.Failed++
+		case finalized.AlreadyFinalized:
+			result.Outcome = FinalizeOutcomeAlreadyFinalized
+			result.FinalizedAt = finalized.FinalizedAt
+		default:
+			result.Outcome = FinalizeOutcomeFinalized
+			result.FinalizedAt = finalized.FinalizedAt
+			resp.Finalized++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// CheckFinalizeSplit reports every reason FinalizeSplit would refuse the split,
+// without changing it
+func (s *SplitService) CheckFinalizeSplit(ctx context.Context, id string) (*FinalizeCheckResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	split = visibleSplit(ctx, split)
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+
+	issues := split.FinalizeIssues()
+	if split.Status != domain.SplitStatusFinalized {
+		issues = append(issues, s.policyIssues(split)...)
+	}
+	resp := &FinalizeCheckResponse{
+		SplitID: split.ID,
+		Ready:   len(issues) == 0,
+		Issues:  make([]string, len(issues)),
+	}
+	for i, issue := range issues {
+		resp.Issues[i] = issue.Error()
+	}
+	return resp, nil
+}
+
+// DeleteSplit deletes a split with its documents and pages; finalized splits are refused
+func (s *SplitService) DeleteSplit(ctx context.Context, id string) error {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if split == nil {
+		return domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+	if err := s.checkLock(ctx, uow, split.ID); err != nil {
+		return err
+	}
+	if err := split.EnsureDeletable(); err != nil {
+		return err
+	}
+
+	if err := uow.SplitRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := uow.LockRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return err
+	}
+	s.splitCache.invalidate(id)
+	s.evictRenderings(split)
+	s.metrics.IncCounter(MetricSplitsDeleted, map[string]string{"force": "false"})
+	return nil
+}
+
+// ForceDeleteSplit deletes a split regardless of its status, e.g. for compliance
+// deletion requests, and records who did it in the audit log. Callers must
+// restrict it to admins.
+func (s *SplitService) ForceDeleteSplit(ctx context.Context, id string, actor string) error {
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if split == nil {
+		return domain.NewNotFoundError("split", id, "split not found", nil)
+	}
+
+	if err := uow.SplitRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := uow.LockRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := uow.AuditRepository().Add(ctx, &domain.AuditEntry{
+		ID:         uuid.NewString(),
+		Actor:      actor,
+		Action:     domain.AuditActionForceDeleteSplit,
+		Resource:   "split",
+		ResourceID: id,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return err
+	}
+	s.splitCache.invalidate(id)
+	s.evictRenderings(split)
+	s.metrics.IncCounter(MetricSplitsDeleted, map[string]string{"force": "true"})
+	return nil
+}
+
+// LockSplit acquires the lock on a split for the context's actor, or extends it
+// if the actor already holds it. A lock held by someone else is refused until it expires.
+func (s *SplitService) LockSplit(ctx context.Context, splitID string) (*SplitLockResponse, error) {
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, domain.NewValidationError("locking a split requires an authenticated subject", nil)
+	}
+
+	uow, err := s.uowFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
+	}
+
+	now := time.Now()
+	existing, err := uow.LockRepository().Get(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.BlocksHolder(actor, now) {
+		return nil, lockedError(existing)
+	}
+
+	lock := &domain.SplitLock{SplitID: splitID, Holder: actor, ExpiresAt: now.Add(s.lockTTL)}
+	if err := uow.LockRepository().Put(ctx, lock); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &SplitLockResponse{SplitID: lock.SplitID, Holder: lock.Holder, ExpiresAt: lock.ExpiresAt}, nil
+}
+
+// UnlockSplit releases the context actor's lock on a split. Releasing a split
+// that is not locked, or whose lock expired, succeeds.
+func (s *SplitService) UnlockSplit(ctx context.Context, splitID string) error {
+	uow, err := s.uowFactory(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer uow.Rollback(ctx)
 
-	split, err := uow.SplitRepository().Get(ctx, id)
+	split, err := uow.SplitRepository().Get(ctx, splitID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if split == nil {
-		return nil, domain.ErrNotFound
+		return domain.NewNotFoundError("split", splitID, "split not found", nil)
 	}
-
-	// Convert domain documents to response documents
-	documents := make([]*DocumentResponse, len(split.Documents))
-	for i, doc := range split.Documents {
-		documents[i] = convertDocumentToResponse(&doc)
+	if err := s.checkLock(ctx, uow, splitID); err != nil {
+		return err
 	}
 
-	// Convert unassigned pages to response pages
-	unassignedPages := make([]*PageResponse, len(split.UnassignedPages))
-	for i, page := range split.UnassignedPages {
-		unassignedPages[i] = convertPageToResponse(page)
+	if err := uow.LockRepository().Delete(ctx, splitID); err != nil {
+		return err
 	}
-
-	return &LoadSplitResponse{
-		ID:              split.ID,
-		ClientID:        split.ClientID,
-		Status:          split.Status,
-		Documents:       documents,
-		UnassignedPages: unassignedPages,
-	}, nil
+	return uow.Commit(ctx)
 }
 
-// UpdateDocumentMetadata updates document metadata
-func (s *SplitService) UpdateDocumentMetadata(ctx context.Context, id string, req UpdateDocumentMetadataRequest) (*DocumentResponse, error) {
-	uow, err := s.uowFactory()
+// ExportSplitJSON returns the split in the ingestion format NewSplit consumes
+func (s *SplitService) ExportSplitJSON(ctx context.Context, id string) ([]byte, error) {
+	uow, err := s.readUoW(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer uow.Rollback(ctx)
 
-	// Get split ID for the document
-	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	split, err := uow.SplitRepository().Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if splitID == "" {
-		return nil, domain.ErrNotFound
+	split = visibleSplit(ctx, split)
+	if split == nil {
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
 	}
 
-	// Load split aggregate
-	split, err := uow.SplitRepository().Get(ctx, splitID)
+	return []byte(split.ToIngestionJSON()), nil
+}
+
+// ExportSplitCSV returns a CSV spreadsheet of the split's documents in split order: a
+// header row, one row per document with its name, classification, page range and page
+// count, and a totals row. Fields are quoted as RFC 4180 requires, and text that a
+// spreadsheet would run as a formula is prefixed with a single quote.
+func (s *SplitService) ExportSplitCSV(ctx context.Context, id string) ([]byte, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
+
+	split, err := uow.SplitRepository().Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	split = visibleSplit(ctx, split)
 	if split == nil {
-		return nil, domain.ErrNotFound
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
 	}
 
-	// Convert request to domain metadata
-	metadata := domain.DocumentMetadata{
-		Name:             req.Name,
-		Classification:   req.Classification,
-		ShortDescription: req.ShortDescription,
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.UseCRLF = true
+	cw.Write([]string{"name", "classification", "page_range", "page_count"})
+	total := 0
+	for _, doc := range split.Documents {
+		total += len(doc.Pages)
+		cw.Write([]string{
+			csvText(doc.Name),
+			csvText(doc.Classification),
+			pageRange(doc.StartPageNumber(), doc.EndPageNumber()),
+			strconv.Itoa(len(doc.Pages)),
+		})
 	}
+	cw.Write([]string{"Total", "", "", strconv.Itoa(total)})
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, fmt.Errorf("error writing CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
 
-	// Update document metadata using domain logic
-	if err := split.UpdateDocumentMetadata(id, metadata); err != nil {
-		return nil, err
+// csvText neutralizes a cell that a spreadsheet would evaluate as a formula by
+// prefixing it with a single quote
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
 	}
+	return s
+}
 
-	// Save the aggregate
-	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+// DownloadDocument downloads a document
+func (s *SplitService) DownloadDocument(ctx context.Context, id string) (*DownloadDocumentResponse, error) {
+	return s.downloadDocument(ctx, "", id)
+}
+
+// DownloadSplitDocument downloads a document of a known split, skipping the
+// lookup of the document's split
+func (s *SplitService) DownloadSplitDocument(ctx context.Context, splitID, id string) (*DownloadDocumentResponse, error) {
+	return s.downloadDocument(ctx, splitID, id)
+}
+
+// downloadDocument renders a document; splitID may be empty if it is not known
+func (s *SplitService) downloadDocument(ctx context.Context, splitID, id string) (*DownloadDocumentResponse, error) {
+	start := time.Now()
+	defer func() { s.metrics.ObserveDuration(OperationDownloadDocument, time.Since(start), nil) }()
+
+	split, doc, err := s.loadDownloadDocument(ctx, splitID, id)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := uow.Commit(ctx); err != nil {
+	// Download document using render service
+	resp, err := s.renderSvc.RenderDocument(ctx, ports.RenderDocumentRequest{
+		Document: doc,
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Find the updated document
-	for _, doc := range split.Documents {
-		if doc.ID == id {
-			return convertDocumentToResponse(&doc), nil
-		}
+	return &DownloadDocumentResponse{
+		Data:        resp.Data,
+		Filename:    s.downloadFilename(doc.Filename),
+		ContentType: "application/pdf",
+		ETag:        `"` + documentContentHash(doc) + `"`,
+		ModifiedAt:  split.UpdatedAt,
+	}, nil
+}
+
+// EstimatedPageBytes is the assumed download size of a page, a scanned page
+// embedded as a compressed image, when the document has not been rendered yet
+const EstimatedPageBytes = 100 << 10
+
+// cachedRenderer is a render service that can return a rendering it already holds
+type cachedRenderer interface {
+	Cached(doc *domain.Document) (*ports.RenderDocumentResponse, bool)
+}
+
+// evictingRenderer is a render service that can drop the renderings it holds
+type evictingRenderer interface {
+	Evict(docIDs ...string)
+}
+
+// evictRenderings drops the cached renderings of a deleted split's documents, so
+// none of their content outlives the split in memory
+func (s *SplitService) evictRenderings(split *domain.Split) {
+	cache, ok := s.renderSvc.(evictingRenderer)
+	if !ok {
+		return
+	}
+	docIDs := make([]string, len(split.Documents))
+	for i, doc := range split.Documents {
+		docIDs[i] = doc.ID
 	}
+	cache.Evict(docIDs...)
+}
 
-	return nil, domain.ErrNotFound
+// DocumentDownloadInfo describes what DownloadDocument would return without
+// rendering the document. The size is exact if the rendering is cached and
+// estimated from the page count otherwise.
+func (s *SplitService) DocumentDownloadInfo(ctx context.Context, id string) (*DocumentDownloadInfoResponse, error) {
+	return s.documentDownloadInfo(ctx, "", id)
 }
 
-// MovePages moves pages between documents
-func (s *SplitService) MovePages(ctx context.Context, req MovePagesRequest) (*MovePagesResponse, error) {
-	uow, err := s.uowFactory()
+// SplitDocumentDownloadInfo is DocumentDownloadInfo for a document of a known split
+func (s *SplitService) SplitDocumentDownloadInfo(ctx context.Context, splitID, id string) (*DocumentDownloadInfoResponse, error) {
+	return s.documentDownloadInfo(ctx, splitID, id)
+}
+
+func (s *SplitService) documentDownloadInfo(ctx context.Context, splitID, id string) (*DocumentDownloadInfoResponse, error) {
+	split, doc, err := s.loadDownloadDocument(ctx, splitID, id)
 	if err != nil {
 		return nil, err
 	}
+
+	info := &DocumentDownloadInfoResponse{
+		DocumentID:    doc.ID,
+		Filename:      s.downloadFilename(doc.Filename),
+		ContentType:   "application/pdf",
+		PageCount:     len(doc.Pages),
+		Size:          int64(max(len(doc.Pages), 1)) * EstimatedPageBytes,
+		SizeEstimated: true,
+		ETag:          `"` + documentContentHash(doc) + `"`,
+		ModifiedAt:    split.UpdatedAt,
+	}
+	if cache, ok := s.renderSvc.(cachedRenderer); ok {
+		if resp, ok := cache.Cached(doc); ok {
+			info.Size = int64(len(resp.Data))
+			info.SizeEstimated = false
+		}
+	}
+	return info, nil
+}
+
+// loadDownloadDocument loads a document to download with its split. If splitID
+// is empty the split is looked up from the document.
+func (s *SplitService) loadDownloadDocument(ctx context.Context, splitID, id string) (*domain.Split, *domain.Document, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer uow.Rollback(ctx)
 
-	split, err := uow.SplitRepository().Get(ctx, req.SplitID)
+	if splitID == "" {
+		// Get split ID for the document
+		splitID, err = uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if splitID == "" {
+			return nil, nil, domain.NewNotFoundError("document", id, "document not found", nil)
+		}
+	}
+
+	// Load split aggregate
+	split, err := uow.SplitRepository().Get(ctx, splitID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	split = visibleSplit(ctx, split)
 	if split == nil {
-		return nil, domain.ErrNotFound
+		return nil, nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
 	}
 
-	// Use domain logic to move pages
-	if err := split.MovePages(req.FromDocumentID, req.ToDocumentID, req.PageIDs); err != nil {
-		return nil, err
+	for i := range split.Documents {
+		if split.Documents[i].ID == id {
+			return split, &split.Documents[i], nil
+		}
 	}
+	return nil, nil, domain.NewNotFoundError("document", id, "document not found", nil)
+}
 
-	// Save the aggregate
-	if err := uow.SplitRepository().Save(ctx, split); err != nil {
-		return nil, err
-	}
+// PreviewSplit renders every document of a split, in order, into a single PDF with a
+// title page before each document. It works on draft splits and changes nothing.
+func (s *SplitService) PreviewSplit(ctx context.Context, id string) (*PreviewSplitResponse, error) {
+	start := time.Now()
+	defer func() { s.metrics.ObserveDuration(OperationPreviewSplit, time.Since(start), nil) }()
 
-	if err := uow.Commit(ctx); err != nil {
+	split, err := s.loadPreviewSplit(ctx, id)
+	if err != nil {
 		return nil, err
 	}
+	if len(split.Documents) == 0 {
+		return nil, domain.NewValidationError("split has no documents to preview", nil)
+	}
 
-	// Find the updated documents
-	var fromDoc, toDoc *domain.Document
-	for _, doc := range split.Documents {
-		if doc.ID == req.FromDocumentID {
-			fromDoc = &doc
+	sections := make([]previewSection, len(split.Documents))
+	for i := range split.Documents {
+		doc := &split.Documents[i]
+		resp, err := s.renderSvc.RenderDocument(ctx, ports.RenderDocumentRequest{Document: doc})
+		if err != nil {
+			return nil, err
 		}
-		if doc.ID == req.ToDocumentID {
-			toDoc = &doc
+		sections[i] = previewSection{
+			Title: doc.Name,
+			Lines: []string{
+				fmt.Sprintf("Document %d of %d", i+1, len(split.Documents)),
+				"Classification: " + doc.Classification,
+				fmt.Sprintf("Pages: %d", len(doc.Pages)),
+			},
+			PDF: resp.Data,
 		}
 	}
 
-	if fromDoc == nil || toDoc == nil {
-		return nil, domain.ErrNotFound
+	data, err := buildPreviewPDF(sections)
+	if err != nil {
+		return nil, domain.NewInternalError("failed to build preview", err)
 	}
 
-	return &MovePagesResponse{
-		FromDocument: convertDocumentToResponse(fromDoc),
-		ToDocument:   convertDocumentToResponse(toDoc),
+	return &PreviewSplitResponse{
+		Filename:    fmt.Sprintf("split-%s-preview.pdf", split.ID),
+		ContentType: "application/pdf",
+		Data:        data,
 	}, nil
 }
 
-// CreateDocument creates a new document
-func (s *SplitService) CreateDocument(ctx context.Context, req CreateDocumentRequest) (*DocumentResponse, error) {
-	uow, err := s.uowFactory()
+// loadPreviewSplit loads the split to preview. The transaction ends before the
+// documents are rendered, which may wait for a render slot.
+func (s *SplitService) loadPreviewSplit(ctx context.Context, id string) (*domain.Split, error) {
+	uow, err := s.readUoW(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer uow.Rollback(ctx)
 
-	split, err := uow.SplitRepository().Get(ctx, req.SplitID)
+	split, err := uow.SplitRepository().Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	split = visibleSplit(ctx, split)
 	if split == nil {
-		return nil, domain.ErrNotFound
+		return nil, domain.NewNotFoundError("split", id, "split not found", nil)
 	}
+	return split, nil
+}
 
-	// Generate a new UUID for the document ID
-	docID := uuid.NewString()
+// GetPageContent opens the stored content of a single page
+func (s *SplitService) GetPageContent(ctx context.Context, pageID string) (*PageContentResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback(ctx)
 
-	// Collect pages by ID from split's unassigned pages
-	var pages []*domain.Page
-	remainingUnassigned := make([]*domain.Page, 0, len(split.UnassignedPages))
-	pageIDSet := make(map[string]struct{}, len(req.PageIDs))
-	for _, pid := range req.PageIDs {
-		pageIDSet[pid] = struct{}{}
+	page, err := uow.SplitRepository().GetPage(ctx, pageID)
+	if err != nil {
+		return nil, err
 	}
-	for _, page := range split.UnassignedPages {
-		if _, ok := pageIDSet[page.ID]; ok {
-			pages = append(pages, page)
-		} else {
-			remainingUnassigned = append(remainingUnassigned, page)
-		}
+	if page == nil {
+		return nil, domain.NewNotFoundError("page", pageID, "page not found", nil)
 	}
-	if len(pages) == 0 {
-		return nil, domain.NewValidationError("no valid pages specified for new document", nil)
+	if OnBehalfOfFromContext(ctx) != "" {
+		split, err := uow.SplitRepository().GetParts(ctx, page.SplitID, domain.SplitParts{})
+		if err != nil {
+			return nil, err
+		}
+		if visibleSplit(ctx, split) == nil {
+			return nil, domain.NewNotFoundError("page", pageID, "page not found", nil)
+		}
 	}
 
-	// Remove assigned pages from unassigned list
-	split.UnassignedPages = remainingUnassigned
+	content, err := s.blobStore.Open(ctx, page.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBlobUnavailable, err)
+	}
 
-	// Create document using domain logic
-	doc := &domain.Document{
-		ID:               docID,
-		SplitID:          req.SplitID,
-		Name:             req.Name,
-		Classification:   req.Classification,
-		Filename:         req.Filename,
-		ShortDescription: req.ShortDescription,
-		Pages:            pages,
+	contentType := mime.TypeByExtension(path.Ext(page.URL))
+	if contentType == "" {
+		contentType = "application/octet-stream"
 	}
 
-	if err := split.AddDocument(doc); err != nil {
+	return &PageContentResponse{
+		ContentType: contentType,
+		Content:     content,
+	}, nil
+}
+
+// ClientStats returns aggregate counts over all splits of a client.
+// A client without splits gets all-zero counts rather than a not-found error.
+func (s *SplitService) ClientStats(ctx context.Context, clientID string) (*ClientStatsResponse, error) {
+	if err := checkClientScope(ctx, clientID); err != nil {
 		return nil, err
 	}
 
-	// Save the aggregate
-	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer uow.Rollback(ctx)
 
-	if err := uow.Commit(ctx); err != nil {
+	stats, err := uow.SplitRepository().GetClientStats(ctx, clientID)
+	if err != nil {
 		return nil, err
 	}
 
-	return convertDocumentToResponse(doc), nil
+	return &ClientStatsResponse{
+		ClientID:        clientID,
+		TotalSplits:     stats.TotalSplits,
+		DraftSplits:     stats.DraftSplits,
+		FinalizedSplits: stats.FinalizedSplits,
+		TotalDocuments:  stats.TotalDocuments,
+		TotalPages:      stats.TotalPages,
+	}, nil
 }
 
-// DeleteDocument deletes a document
-func (s *SplitService) DeleteDocument(ctx context.Context, id string) error {
-	uow, factErr := s.uowFactory()
-	if factErr != nil {
-		return factErr
+// ListClients returns one page of the clients that own splits, with their split counts
+func (s *SplitService) ListClients(ctx context.Context, req ListClientsRequest) (*ListClientsResponse, error) {
+	uow, err := s.readUoW(ctx)
+	if err != nil {
+		return nil, err
 	}
 	defer uow.Rollback(ctx)
 
-	// Get split ID for the document
-	splitID, getByErr := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
-	if getByErr != nil {
-		return getByErr
-	}
-	if splitID == "" {
-		return domain.ErrNotFound
+	var clients []domain.ClientSummary
+	if scoped := OnBehalfOfFromContext(ctx); scoped != "" {
+		// Only the scoped client is listed, if it has any splits
+		stats, err := uow.SplitRepository().GetClientStats(ctx, scoped)
+		if err != nil {
+			return nil, err
+		}
+		if stats.TotalSplits > 0 && req.Offset == 0 && req.Limit != 0 {
+			clients = []domain.ClientSummary{{ClientID: scoped, SplitCount: stats.TotalSplits}}
+		}
+	} else {
+		clients, err = uow.SplitRepository().ListClients(ctx, req.Limit, req.Offset)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Load split aggregate
-	split, getErr := uow.SplitRepository().Get(ctx, splitID)
-	if getErr != nil {
-		return getErr
+	resp := &ListClientsResponse{
+		Clients: make([]*ClientSummaryResponse, len(clients)),
+		Limit:   req.Limit,
+		Offset:  req.Offset,
 	}
-	if split == nil {
-		return domain.ErrNotFound
+	for i, c := range clients {
+		resp.Clients[i] = &ClientSummaryResponse{ClientID: c.ClientID, SplitCount: c.SplitCount}
 	}
+	return resp, nil
+}
 
-	// Delete document using domain logic
-	if remErr := split.RemoveDocument(id); remErr != nil {
-		return remErr
+// ListClientPages returns a page of a client's pages whose document has the
+// requested classification, across all of the client's splits
+func (s *SplitService) ListClientPages(ctx context.Context, req ListClientPagesRequest) (*ListClientPagesResponse, error) {
+	if req.Classification == "" {
+		return nil, domain.NewValidationError("classification is required", nil)
 	}
-
-	// Save the aggregate
-	if saveErr := uow.SplitRepository().Save(ctx, split); saveErr != nil {
-		return saveErr
+	if err := checkClientScope(ctx, req.ClientID); err != nil {
+		return nil, err
 	}
 
-	return uow.Commit(ctx)
-}
-
-// FinalizeSplit finalizes a split
-func (s *SplitService) FinalizeSplit(ctx context.Context, id string) error {
-	uow, err := s.uowFactory()
+	uow, err := s.readUoW(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer uow.Rollback(ctx)
 
-	split, err := uow.SplitRepository().Get(ctx, id)
+	pages, err := uow.SplitRepository().ListClientPagesByClassification(ctx, req.ClientID, req.Classification, req.Limit, req.Offset)
 	if err != nil {
-		return err
-	}
-	if split == nil {
-		return domain.ErrNotFound
+		return nil, err
 	}
 
-	// Finalize split using domain logic
-	if err := split.Finalize(time.Now()); err != nil {
-		return err
+	resp := &ListClientPagesResponse{
+		ClientID:       req.ClientID,
+		Classification: req.Classification,
+		Pages:          make([]*ClientPageResponse, len(pages)),
+		Limit:          req.Limit,
+		Offset:         req.Offset,
 	}
-
-	// Save the aggregate
-	if err := uow.SplitRepository().Save(ctx, split); err != nil {
-		return err
+	for i, p := range pages {
+		resp.Pages[i] = &ClientPageResponse{
+			PageID:     p.PageID,
+			PageNumber: strconv.Itoa(p.PageNumber),
+			URL:        resolvePageURL(s.pageURLBase, p.URL),
+			DocumentID: p.DocumentID,
+			SplitID:    p.SplitID,
+			SplitName:  p.SplitName,
+		}
 	}
-
-	return uow.Commit(ctx)
+	return resp, nil
 }
 
-// DownloadDocument downloads a document
-func (s *SplitService) DownloadDocument(ctx context.Context, id string) (*DownloadDocumentResponse, error) {
-	uow, err := s.uowFactory()
+// ListClientSplits lists a client's splits without their documents, newest first,
+// optionally only those with a tag
+func (s *SplitService) ListClientSplits(ctx context.Context, req ListClientSplitsRequest) (*ListClientSplitsResponse, error) {
+	if err := checkClientScope(ctx, req.ClientID); err != nil {
+		return nil, err
+	}
+	if req.Tag != "" {
+		tag, err := domain.NormalizeTag(req.Tag)
+		if err != nil {
+			return nil, err
+		}
+		req.Tag = tag
+	}
+
+	uow, err := s.readUoW(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer uow.Rollback(ctx)
 
-	// Get split ID for the document
-	splitID, err := uow.SplitRepository().GetSplitIDByDocumentID(ctx, id)
+	splits, err := uow.SplitRepository().ListClientSplits(ctx, req.ClientID, req.Tag, req.Limit, req.Offset)
 	if err != nil {
 		return nil, err
 	}
-	if splitID == "" {
-		return nil, domain.ErrNotFound
+
+	resp := &ListClientSplitsResponse{
+		ClientID: req.ClientID,
+		Tag:      req.Tag,
+		Splits:   make([]*SplitSummaryResponse, len(splits)),
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	for i, split := range splits {
+		resp.Splits[i] = &SplitSummaryResponse{
+			ID:             split.ID,
+			Name:           split.Name,
+			Status:         split.Status,
+			Tags:           splitTags(split),
+			CreatedAt:      split.CreatedAt.UTC(),
+			UpdatedAt:      split.UpdatedAt.UTC(),
+			LastModifiedBy: split.LastModifiedBy,
+			FinalizedAt:    split.FinalizedAt,
+		}
 	}
+	return resp, nil
+}
 
-	// Load split aggregate
-	split, err := uow.SplitRepository().Get(ctx, splitID)
+// ListAllPages returns every page of a split with the document it is assigned to,
+// or null, sorted by page number. Unlike LoadSplit it is flat rather than nested
+// under documents.
+func (s *SplitService) ListAllPages(ctx context.Context, splitID string) (*ListSplitPagesResponse, error) {
+	uow, err := s.readUoW(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if split == nil {
-		return nil, domain.ErrNotFound
-	}
+	defer uow.Rollback(ctx)
 
-	// Find the document
-	var doc *domain.Document
-	for _, d := range split.Documents {
-		if d.ID == id {
-			doc = &d
-			break
-		}
+	repo := uow.SplitRepository()
+	split, err := repo.GetParts(ctx, splitID, domain.SplitParts{})
+	if err != nil {
+		return nil, err
 	}
-
-	if doc == nil {
-		return nil, domain.ErrNotFound
+	if visibleSplit(ctx, split) == nil {
+		return nil, domain.NewNotFoundError("split", splitID, "split not found", nil)
 	}
 
-	// Download document using render service
-	resp, err := s.renderSvc.RenderDocument(ctx, ports.RenderDocumentRequest{
-		Document: doc,
-	})
+	pages, err := repo.ListSplitPages(ctx, splitID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DownloadDocumentResponse{
-		Data:        resp.Data,
-		Filename:    doc.Filename,
-		ContentType: "application/pdf",
-	}, nil
+	resp := &ListSplitPagesResponse{
+		SplitID: splitID,
+		Pages:   make([]*SplitPageResponse, len(pages)),
+	}
+	for i, p := range pages {
+		resp.Pages[i] = &SplitPageResponse{
+			ID:         p.ID,
+			PageNumber: strconv.Itoa(p.PageNumber),
+			URL:        resolvePageURL(s.pageURLBase, p.URL),
+			DocumentID: p.DocumentID,
+		}
+		if p.DocumentID != nil {
+			resp.AssignedCount++
+		} else {
+			resp.UnassignedCount++
+		}
+	}
+	return resp, nil
 }
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"accounting/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+// seedBenchmarkSplit stores a split with two documents of pagesPerDoc pages each
+func seedBenchmarkSplit(b *testing.B, service *SplitService, pagesPerDoc int) {
+	seedBenchmarkSplitDocs(b, service, 2, pagesPerDoc)
+}
+
+// seedBenchmarkSplitDocs stores a split with docs documents of pagesPerDoc pages each
+func seedBenchmarkSplitDocs(b *testing.B, service *SplitService, docs, pagesPerDoc int) {
+	ctx := context.Background()
+	uow, err := service.uowFactory(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{ID: "bench-split", ClientID: "bench-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now}
+	for d := 0; d < docs; d++ {
+		docID := fmt.Sprintf("doc%d", d+1)
+		doc := domain.Document{ID: docID, SplitID: split.ID, Name: docID, SortOrder: d}
+		for p := 0; p < pagesPerDoc; p++ {
+			n := d*pagesPerDoc + p + 1
+			doc.Pages = append(doc.Pages, &domain.Page{
+				ID:         fmt.Sprintf("page%d", n),
+				SplitID:    split.ID,
+				DocumentID: stringPtr(docID),
+				PageNumber: n,
+				URL:        fmt.Sprintf("page_%d.png", n),
+			})
+		}
+		split.Documents = append(split.Documents, doc)
+	}
+	if err := uow.SplitRepository().Save(ctx, split); err != nil {
+		b.Fatal(err)
+	}
+	if err := uow.Commit(ctx); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkMovePages_SinglePage measures the single-page fast path (targeted UPDATEs)
+func BenchmarkMovePages_SinglePage(b *testing.B) {
+	db, uowFactory := setupTestDB(b)
+	defer db.Close()
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	seedBenchmarkSplit(b, service, 200)
+	ctx := context.Background()
+
+	from, to := "doc1", "doc2"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := service.MovePages(ctx, MovePagesRequest{SplitID: "bench-split", FromDocumentID: from, ToDocumentID: to, PageIDs: []string{"page1"}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		from, to = to, from
+	}
+}
+
+// BenchmarkMovePages_FullSave measures the same move persisted by re-saving the whole aggregate
+func BenchmarkMovePages_FullSave(b *testing.B) {
+	db, uowFactory := setupTestDB(b)
+	defer db.Close()
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	seedBenchmarkSplit(b, service, 200)
+	ctx := context.Background()
+
+	from, to := "doc1", "doc2"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		uow, err := uowFactory(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		split, err := uow.SplitRepository().Get(ctx, "bench-split")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := split.MovePages(from, to, []string{"page1"}); err != nil {
+			b.Fatal(err)
+		}
+		if err := uow.SplitRepository().Save(ctx, split); err != nil {
+			b.Fatal(err)
+		}
+		if err := uow.Commit(ctx); err != nil {
+			b.Fatal(err)
+		}
+		from, to = to, from
+	}
+}
+
+// seedBenchmarkSplits stores count splits of two documents with pagesPerDoc pages each
+// and returns their IDs
+func seedBenchmarkSplits(b *testing.B, service *SplitService, count, pagesPerDoc int) []string {
+	ctx := context.Background()
+	uow, err := service.uowFactory(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("bench-split-%d", i)
+		split := &domain.Split{ID: ids[i], ClientID: "bench-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now}
+		for d := 0; d < 2; d++ {
+			docID := fmt.Sprintf("%s-doc%d", ids[i], d)
+			doc := domain.Document{ID: docID, SplitID: split.ID, Name: docID}
+			for p := 0; p < pagesPerDoc; p++ {
+				n := d*pagesPerDoc + p + 1
+				doc.Pages = append(doc.Pages, &domain.Page{
+					ID:         fmt.Sprintf("%s-page%d", ids[i], n),
+					SplitID:    split.ID,
+					DocumentID: stringPtr(docID),
+					PageNumber: n,
+					URL:        fmt.Sprintf("page_%d.png", n),
+				})
+			}
+			split.Documents = append(split.Documents, doc)
+		}
+		if err := uow.SplitRepository().Save(ctx, split); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := uow.Commit(ctx); err != nil {
+		b.Fatal(err)
+	}
+	return ids
+}
+
+// BenchmarkGetSplits_GetMany measures loading 20 splits with a single GetMany call
+func BenchmarkGetSplits_GetMany(b *testing.B) {
+	db, uowFactory := setupTestDB(b)
+	defer db.Close()
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ids := seedBenchmarkSplits(b, service, 20, 10)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		uow, err := uowFactory(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := uow.SplitRepository().GetMany(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+		uow.Rollback(ctx)
+	}
+}
+
+// BenchmarkGetSplits_Sequential measures loading the same 20 splits with one Get call each
+func BenchmarkGetSplits_Sequential(b *testing.B) {
+	db, uowFactory := setupTestDB(b)
+	defer db.Close()
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ids := seedBenchmarkSplits(b, service, 20, 10)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		uow, err := uowFactory(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, id := range ids {
+			if _, err := uow.SplitRepository().Get(ctx, id); err != nil {
+				b.Fatal(err)
+			}
+		}
+		uow.Rollback(ctx)
+	}
+}
+
+// heapPeakWriter discards what is written while recording the largest heap seen
+// at a write. Benchmarks using it run with a low GC target so the heap tracks the
+// live data closely.
+type heapPeakWriter struct {
+	peak uint64
+}
+
+func (w *heapPeakWriter) Write(p []byte) (int, error) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	w.peak = max(w.peak, stats.HeapAlloc)
+	return len(p), nil
+}
+
+// benchmarkLoadSplit runs load on a 5000-page split (500 documents of 10 pages)
+// and reports the peak heap seen by its writes
+func benchmarkLoadSplit(b *testing.B, load func(ctx context.Context, service *SplitService, w io.Writer) error) {
+	db, uowFactory := setupTestDB(b)
+	defer db.Close()
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	seedBenchmarkSplitDocs(b, service, 500, 10)
+	ctx := context.Background()
+
+	defer debug.SetGCPercent(debug.SetGCPercent(5))
+	runtime.GC()
+	w := &heapPeakWriter{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := load(ctx, service, w); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(w.peak), "peak-heap-B")
+}
+
+// BenchmarkLoadSplit_Buffered measures building and encoding the whole response at once
+func BenchmarkLoadSplit_Buffered(b *testing.B) {
+	benchmarkLoadSplit(b, func(ctx context.Context, service *SplitService, w io.Writer) error {
+		resp, err := service.LoadSplit(ctx, "bench-split")
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// BenchmarkLoadSplit_Streamed measures streaming the same split one document at a time
+func BenchmarkLoadSplit_Streamed(b *testing.B) {
+	benchmarkLoadSplit(b, func(ctx context.Context, service *SplitService, w io.Writer) error {
+		return service.StreamSplit(ctx, "bench-split", w)
+	})
+}
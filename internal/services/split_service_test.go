@@ -4,8 +4,14 @@ import (
 	"accounting/internal/domain"
 	"accounting/internal/domain/ports"
 	"accounting/internal/infrastructure/db/uow"
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +31,37 @@ func (m *mockRenderService) RenderDocument(ctx context.Context, req ports.Render
 	}, nil
 }
 
-func setupTestDB(t *testing.T) (*sql.DB, func() (ports.UnitOfWork, error)) {
+// mockBlobStore implements ports.BlobStore for testing
+type mockBlobStore struct {
+	blobs map[string]string
+}
+
+func (m *mockBlobStore) Open(ctx context.Context, url string) (io.ReadCloser, error) {
+	data, ok := m.blobs[url]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func (m *mockBlobStore) Put(ctx context.Context, url string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if m.blobs == nil {
+		m.blobs = make(map[string]string)
+	}
+	m.blobs[url] = string(data)
+	return nil
+}
+
+func (m *mockBlobStore) Delete(ctx context.Context, url string) error {
+	delete(m.blobs, url)
+	return nil
+}
+
+func setupTestDB(t testing.TB) (*sql.DB, func(ctx context.Context) (ports.UnitOfWork, error)) {
 	db, err := sql.Open("sqlite3", ":memory:")
 	require.NoError(t, err)
 
@@ -34,9 +70,12 @@ func setupTestDB(t *testing.T) (*sql.DB, func() (ports.UnitOfWork, error)) {
 		CREATE TABLE splits (
 			id TEXT PRIMARY KEY,
 			client_id TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
 			status TEXT NOT NULL,
 			created_at TIMESTAMP NOT NULL,
-			updated_at TIMESTAMP NOT NULL
+			updated_at TIMESTAMP NOT NULL,
+			last_modified_by TEXT NOT NULL DEFAULT '',
+			finalized_at TIMESTAMP
 		);
 		CREATE TABLE documents (
 			id TEXT PRIMARY KEY,
@@ -47,6 +86,12 @@ func setupTestDB(t *testing.T) (*sql.DB, func() (ports.UnitOfWork, error)) {
 			short_description TEXT,
 			start_page TEXT,
 			end_page TEXT,
+			start_page_number INTEGER NOT NULL DEFAULT 0,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			reviewed INTEGER NOT NULL DEFAULT 0,
+			reviewed_by TEXT NOT NULL DEFAULT '',
+			reviewed_at TIMESTAMP,
+			last_modified_by TEXT NOT NULL DEFAULT '',
 			FOREIGN KEY (split_id) REFERENCES splits(id)
 		);
 		CREATE TABLE pages (
@@ -55,15 +100,50 @@ func setupTestDB(t *testing.T) (*sql.DB, func() (ports.UnitOfWork, error)) {
 			document_id TEXT,
 			page_number TEXT NOT NULL,
 			url TEXT NOT NULL,
+			sort_order INTEGER NOT NULL DEFAULT 0,
 			FOREIGN KEY (split_id) REFERENCES splits(id),
 			FOREIGN KEY (document_id) REFERENCES documents(id)
 		);
+		CREATE TABLE split_tags (
+			split_id TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (split_id, tag)
+		);
+		CREATE TABLE classification_history (
+			id TEXT PRIMARY KEY,
+			document_id TEXT NOT NULL,
+			old_classification TEXT NOT NULL,
+			new_classification TEXT NOT NULL,
+			changed_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE audit_log (
+			id TEXT PRIMARY KEY,
+			actor TEXT NOT NULL,
+			action TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			resource_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE split_locks (
+			split_id TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE outbox (
+			id TEXT PRIMARY KEY,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			sent_at TIMESTAMP,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT
+		);
 	`)
 	require.NoError(t, err)
 
-	uowFactory := func() (ports.UnitOfWork, error) {
+	uowFactory := func(ctx context.Context) (ports.UnitOfWork, error) {
 		uow := uow.NewUnitOfWorkSQL(db)
-		if err := uow.Begin(); err != nil {
+		if err := uow.BeginTx(ctx, nil); err != nil {
 			return nil, err
 		}
 		return uow, nil
@@ -72,20 +152,30 @@ func setupTestDB(t *testing.T) (*sql.DB, func() (ports.UnitOfWork, error)) {
 	return db, uowFactory
 }
 
+// assertNotFoundResource checks that err is a not-found error for the given resource
+func assertNotFoundResource(t *testing.T, err error, resource, id string) {
+	t.Helper()
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, resource, domainErr.Resource)
+	assert.Equal(t, id, domainErr.ResourceID)
+}
+
 func TestSplitService_LoadSplit(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
 	// Test loading non-existent split
 	_, err := service.LoadSplit(ctx, "non-existent")
 	assert.Error(t, err)
-	assert.Equal(t, domain.ErrNotFound, err)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assertNotFoundResource(t, err, "split", "non-existent")
 
 	// Create test split
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -110,15 +200,73 @@ func TestSplitService_LoadSplit(t *testing.T) {
 	assert.Equal(t, domain.SplitStatusDraft, response.Status)
 }
 
+func TestConvertSplitToResponse_PagesConvertedAlike(t *testing.T) {
+	docID := "doc1"
+	assigned := &domain.Page{ID: "page12", SplitID: "s1", DocumentID: &docID, PageNumber: 12, URL: "page_12.png"}
+	unassigned := &domain.Page{ID: "page12", SplitID: "s1", PageNumber: 12, URL: "page_12.png"}
+	resp := convertSplitToResponse(&domain.Split{
+		ID:              "s1",
+		Documents:       []domain.Document{{ID: docID, SplitID: "s1", Pages: []*domain.Page{assigned}}},
+		UnassignedPages: []*domain.Page{unassigned},
+	})
+
+	want := &PageResponse{ID: "page12", PageNumber: "12", Number: 12, URL: "page_12.png"}
+	assert.Equal(t, want, resp.Documents[0].Pages[0])
+	assert.Equal(t, want, resp.UnassignedPages[0])
+
+	data, err := json.Marshal(resp.UnassignedPages[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"page12","page_number":"12","number":12,"url":"page_12.png"}`, string(data))
+}
+
+func TestConvertDocumentToResponse_PageRange(t *testing.T) {
+	docID := "doc1"
+	page := func(n int) *domain.Page {
+		return &domain.Page{ID: fmt.Sprintf("page%d", n), DocumentID: &docID, PageNumber: n}
+	}
+	tests := []struct {
+		name       string
+		pages      []*domain.Page
+		start      int
+		end        int
+		pageRange  string
+		contiguous bool
+	}{
+		{name: "range", pages: []*domain.Page{page(3), page(4), page(5), page(6), page(7)}, start: 3, end: 7, pageRange: "3-7", contiguous: true},
+		{name: "unsorted with gap", pages: []*domain.Page{page(9), page(2)}, start: 2, end: 9, pageRange: "2-9"},
+		{name: "single page", pages: []*domain.Page{page(3)}, start: 3, end: 3, pageRange: "3", contiguous: true},
+		{name: "no pages", contiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := convertDocumentToResponse(&domain.Document{ID: docID, Pages: tt.pages})
+			assert.Equal(t, tt.start, resp.StartPageNumber)
+			assert.Equal(t, tt.end, resp.EndPageNumber)
+			assert.Equal(t, tt.pageRange, resp.PageRange)
+			assert.Equal(t, tt.contiguous, resp.Contiguous)
+
+			data, err := json.Marshal(resp)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), fmt.Sprintf(`"contiguous":%t`, tt.contiguous))
+			if tt.pageRange == "" {
+				assert.NotContains(t, string(data), "page_range")
+			} else {
+				assert.Contains(t, string(data), fmt.Sprintf(`"page_range":%q`, tt.pageRange))
+			}
+		})
+	}
+}
+
 func TestSplitService_UpdateDocumentMetadata(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
 	// Create test split with document
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -164,15 +312,187 @@ func TestSplitService_UpdateDocumentMetadata(t *testing.T) {
 	assert.Equal(t, "Updated Description", response.ShortDescription)
 }
 
+func TestSplitService_LastModifiedBy(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 2, URL: "page_2.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Pages: []*domain.Page{
+				{ID: "page3", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 3, URL: "page_3.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// A metadata change records its subject on the split and the changed document only
+	name := "Renamed"
+	doc, err := service.UpdateDocumentMetadata(WithActor(ctx, "alice"), "doc1", UpdateDocumentMetadataRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", doc.LastModifiedBy)
+
+	loaded, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", loaded.LastModifiedBy)
+	assert.Equal(t, "alice", loaded.Documents[0].LastModifiedBy)
+	assert.Empty(t, loaded.Documents[1].LastModifiedBy)
+
+	// A later change by someone else takes over, including the single page move fast path
+	_, err = service.MovePages(WithActor(ctx, "bob"), MovePagesRequest{SplitID: "split1", FromDocumentID: "doc1", ToDocumentID: "doc2", PageIDs: []string{"page2"}})
+	require.NoError(t, err)
+
+	loaded, err = service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", loaded.LastModifiedBy)
+	assert.Equal(t, "bob", loaded.Documents[0].LastModifiedBy)
+	assert.Equal(t, "bob", loaded.Documents[1].LastModifiedBy)
+}
+
+func TestSplitService_ReclassifyDocument(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{
+				ID:             "doc1",
+				SplitID:        "test-split",
+				Name:           "Doc",
+				Classification: "Invoice",
+				Filename:       "test.pdf",
+				StartPage:      "1",
+				EndPage:        "1",
+			},
+		},
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	historyCount := func() int {
+		var n int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM classification_history`).Scan(&n))
+		return n
+	}
+
+	response, err := service.ReclassifyDocument(ctx, "doc1", "W-2")
+	require.NoError(t, err)
+	assert.Equal(t, "W-2", response.Classification)
+
+	var oldClass, newClass string
+	err = db.QueryRow(`SELECT old_classification, new_classification FROM classification_history WHERE document_id = ?`, "doc1").Scan(&oldClass, &newClass)
+	require.NoError(t, err)
+	assert.Equal(t, "Invoice", oldClass)
+	assert.Equal(t, "W-2", newClass)
+
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "W-2", loaded.Documents[0].Classification)
+
+	// Classifications outside the whitelist are rejected without touching the history
+	service.SetAllowedClassifications([]string{"W-2", "1099"})
+	_, err = service.ReclassifyDocument(ctx, "doc1", "Invoice")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Equal(t, 1, historyCount())
+
+	_, err = service.ReclassifyDocument(ctx, "missing", "1099")
+	assertNotFoundResource(t, err, "document", "missing")
+	assert.Equal(t, 1, historyCount())
+
+	// The history goes with the document
+	require.NoError(t, service.DeleteDocument(ctx, "doc1"))
+	assert.Equal(t, 0, historyCount())
+}
+
+func TestSplitService_DeletePages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{
+				ID:             "doc1",
+				SplitID:        "test-split",
+				Name:           "Doc",
+				Classification: "Invoice",
+				Filename:       "test.pdf",
+				Pages: []*domain.Page{
+					{ID: "page1", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+					{ID: "page2", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 2, URL: "page_2.png"},
+					{ID: "page3", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 3, URL: "page_3.png"},
+				},
+			},
+		},
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	response, err := service.DeletePages(ctx, "doc1", DeletePagesRequest{PageIDs: []string{"page2"}})
+	require.NoError(t, err)
+	require.Len(t, response.Pages, 2)
+	assert.Equal(t, "page1", response.Pages[0].ID)
+	assert.Equal(t, "page3", response.Pages[1].ID)
+
+	// The page row is gone rather than unassigned
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM pages WHERE id = ?`, "page2").Scan(&n))
+	assert.Equal(t, 0, n)
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.UnassignedPages)
+	assert.Len(t, loaded.Documents[0].Pages, 2)
+
+	_, err = service.DeletePages(ctx, "doc1", DeletePagesRequest{PageIDs: []string{"page2"}})
+	assertNotFoundResource(t, err, "page", "page2")
+}
+
 func TestSplitService_MovePages(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
 	// Create test split with two documents and pages
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -241,17 +561,36 @@ func TestSplitService_MovePages(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, response.FromDocument.Pages, 0)
 	assert.Len(t, response.ToDocument.Pages, 2)
+
+	// Verify the single-page fast path persisted the move and the page ranges
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	for _, doc := range loaded.Documents {
+		switch doc.ID {
+		case "doc1":
+			assert.Empty(t, doc.Pages)
+			assert.Equal(t, "", doc.StartPage)
+		case "doc2":
+			assert.Len(t, doc.Pages, 2)
+			assert.Equal(t, "http://test.com/1", doc.StartPage)
+			assert.Equal(t, "http://test.com/2", doc.EndPage)
+		}
+	}
+
+	// Moving the same page again is rejected by domain validation
+	_, err = service.MovePages(ctx, req)
+	assert.Error(t, err)
 }
 
 func TestSplitService_CreateDocument(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
 	// Create test split
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -294,15 +633,185 @@ func TestSplitService_CreateDocument(t *testing.T) {
 	assert.Len(t, response.Pages, 1)
 }
 
+func TestSplitService_ListAllPages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	doc1, doc2 := "doc1", "doc2"
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: doc1, SplitID: "test-split", Name: "W-2", Pages: []*domain.Page{
+				{ID: "page2", SplitID: "test-split", DocumentID: &doc1, PageNumber: 2, URL: "page_2.png"},
+				{ID: "page10", SplitID: "test-split", DocumentID: &doc1, PageNumber: 10, URL: "page_10.png"},
+			}},
+			{ID: doc2, SplitID: "test-split", Name: "1099", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: &doc2, PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+		UnassignedPages: []*domain.Page{
+			{ID: "page3", SplitID: "test-split", PageNumber: 3, URL: "page_3.png"},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.ListAllPages(ctx, "test-split")
+	require.NoError(t, err)
+
+	// Every page is listed once, in page order, with its assignment
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assigned := 0
+	for _, doc := range loaded.Documents {
+		assigned += len(doc.Pages)
+	}
+	assert.Equal(t, assigned, resp.AssignedCount)
+	assert.Equal(t, len(loaded.UnassignedPages), resp.UnassignedCount)
+	require.Len(t, resp.Pages, assigned+len(loaded.UnassignedPages))
+
+	var ids []string
+	for _, p := range resp.Pages {
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{"page1", "page2", "page3", "page10"}, ids)
+	assert.Equal(t, &doc2, resp.Pages[0].DocumentID)
+	assert.Nil(t, resp.Pages[2].DocumentID)
+
+	_, err = service.ListAllPages(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.ListAllPages(WithOnBehalfOf(ctx, "other-client"), "test-split")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_CreateDocumentRejectsForeignPages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for _, splitID := range []string{"test-split", "other-split"} {
+		require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+			ID:        splitID,
+			ClientID:  "test-client",
+			Status:    domain.SplitStatusDraft,
+			CreatedAt: now,
+			UpdatedAt: now,
+			UnassignedPages: []*domain.Page{
+				{ID: splitID + "-page1", SplitID: splitID, PageNumber: 1, URL: "page_1.png"},
+			},
+		}))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID:  "test-split",
+		Name:     "New Document",
+		Filename: "new.pdf",
+		PageIDs:  []string{"test-split-page1", "other-split-page1", "missing", "other-split-page1"},
+	})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "pages not unassigned in split test-split: other-split-page1, missing")
+
+	// Nothing was created; the valid page is still unassigned
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Documents)
+	assert.Len(t, loaded.UnassignedPages, 1)
+}
+
+func TestSplitService_CreateDocumentFromPageRange(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	var pages []*domain.Page
+	for n := 1; n <= 8; n++ {
+		pages = append(pages, &domain.Page{ID: fmt.Sprintf("page%d", n), SplitID: "test-split", PageNumber: n, URL: fmt.Sprintf("page_%d.png", n)})
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		UnassignedPages: pages,
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	assertValidation := func(err error, msg string) {
+		t.Helper()
+		var domainErr *domain.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	doc, err := service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "W2", Classification: "W-2", Filename: "w2.pdf", PageRange: "3-7",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "3-7", doc.PageRange)
+	require.Len(t, doc.Pages, 5)
+	assert.Equal(t, "page3", doc.Pages[0].ID)
+	assert.Equal(t, "page7", doc.Pages[4].ID)
+
+	// Pages 5-7 are now assigned and there is no page 9
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "1099", Classification: "1099", Filename: "1099.pdf", PageRange: "7-9",
+	})
+	assertValidation(err, "page 7 of range 7-9 is not an unassigned page of split test-split")
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "1099", Classification: "1099", Filename: "1099.pdf", PageRange: "8-9",
+	})
+	assertValidation(err, "page 9 of range 8-9 is not an unassigned page of split test-split")
+
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "1099", Classification: "1099", Filename: "1099.pdf", PageRange: "2-1",
+	})
+	assertValidation(err, "the range must be ascending")
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "1099", Classification: "1099", Filename: "1099.pdf", PageRange: "1-2", PageIDs: []string{"page8"},
+	})
+	assertValidation(err, "page_ids and page_range cannot both be given")
+
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Len(t, loaded.Documents, 1)
+	assert.Len(t, loaded.UnassignedPages, 3)
+}
+
 func TestSplitService_DeleteDocument(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
 	// Create test split with document
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -354,11 +863,12 @@ func TestSplitService_FinalizeSplit(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	service.SetFinalizeEvents(true)
 	ctx := context.Background()
 
 	// Create test split
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
 
@@ -397,61 +907,2238 @@ func TestSplitService_FinalizeSplit(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test finalizing split
-	err = service.FinalizeSplit(ctx, "test-split")
+	resp, err := service.FinalizeSplit(ctx, "test-split")
 	require.NoError(t, err)
+	assert.False(t, resp.AlreadyFinalized)
+	require.NotNil(t, resp.FinalizedAt)
 
 	// Verify split is finalized
 	loadedSplit, err := service.LoadSplit(ctx, "test-split")
 	require.NoError(t, err)
 	assert.Equal(t, domain.SplitStatusFinalized, loadedSplit.Status)
+
+	// Verify exactly one webhook event was enqueued
+	var count int
+	var eventType, payload string
+	err = db.QueryRow(`SELECT COUNT(*), MAX(event_type), MAX(payload) FROM outbox`).Scan(&count, &eventType, &payload)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, domain.EventSplitFinalized, eventType)
+	var event domain.SplitFinalizedEvent
+	require.NoError(t, json.Unmarshal([]byte(payload), &event))
+	assert.Equal(t, "test-split", event.SplitID)
+	assert.Equal(t, "test-client", event.ClientID)
+
+	// A retried finalize succeeds with the original time and does not enqueue another event
+	retry, err := service.FinalizeSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.True(t, retry.AlreadyFinalized)
+	require.NotNil(t, retry.FinalizedAt)
+	assert.True(t, resp.FinalizedAt.Equal(*retry.FinalizedAt))
+	err = db.QueryRow(`SELECT COUNT(*) FROM outbox`).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
 }
 
-func TestSplitService_DownloadDocument(t *testing.T) {
+func TestSplitService_FinalizeSplitWithoutEvents(t *testing.T) {
 	db, uowFactory := setupTestDB(t)
 	defer db.Close()
 
-	service := NewSplitService(uowFactory, &mockRenderService{})
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
 	ctx := context.Background()
 
-	// Create test split with document
-	uow, err := uowFactory()
+	uow, err := uowFactory(ctx)
 	require.NoError(t, err)
 	defer uow.Rollback(ctx)
+	now := time.Now()
+	docID := "doc1"
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "test-split", ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: docID, SplitID: "test-split", Name: "W-2", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// With no webhook to deliver them, finalizing queues no events
+	_, err = service.FinalizeSplit(ctx, "test-split")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM outbox`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestSplitService_FinalizeSplitConflict(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
 
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
 	now := time.Now()
-	split := &domain.Split{
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
 		ID:        "test-split",
 		ClientID:  "test-client",
-		Status:    domain.SplitStatusDraft,
+		Status:    domain.SplitStatus("archived"),
 		CreatedAt: now,
 		UpdatedAt: now,
-		Documents: []domain.Document{
-			{
-				ID:               "doc1",
-				SplitID:          "test-split",
-				Name:             "Test Document",
-				Classification:   "Test Class",
-				Filename:         "test.pdf",
-				ShortDescription: "Test Description",
-				StartPage:        "1",
-				EndPage:          "2",
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// A split moved to another status is a genuine conflict, not a benign retry
+	_, err = service.FinalizeSplit(ctx, "test-split")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorConflict, domainErr.Kind)
+	assert.Contains(t, err.Error(), "cannot finalize split in status archived")
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM outbox`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestSplitService_FinalizeSplits(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	newSplit := func(id string, status domain.SplitStatus, unassigned bool) *domain.Split {
+		docID := id + "-doc"
+		split := &domain.Split{
+			ID: id, ClientID: "client1", Status: status, CreatedAt: now, UpdatedAt: now,
+			Documents: []domain.Document{
+				{ID: docID, SplitID: id, Name: "W-2", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+					{ID: id + "-page1", SplitID: id, DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+				}},
 			},
-		},
+		}
+		if unassigned {
+			split.UnassignedPages = []*domain.Page{{ID: id + "-page2", SplitID: id, PageNumber: 2, URL: "page_2.png"}}
+		}
+		if status == domain.SplitStatusFinalized {
+			split.FinalizedAt = &now
+		}
+		return split
 	}
-	err = uow.SplitRepository().Save(ctx, split)
-	require.NoError(t, err)
-	err = uow.Commit(ctx)
-	require.NoError(t, err)
+	for _, split := range []*domain.Split{
+		newSplit("ready1", domain.SplitStatusDraft, false),
+		newSplit("unassigned", domain.SplitStatusDraft, true),
+		newSplit("done", domain.SplitStatusFinalized, false),
+		newSplit("ready2", domain.SplitStatusDraft, false),
+	} {
+		require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	}
+	require.NoError(t, uow.Commit(ctx))
 
-	// Test downloading document
-	response, err := service.DownloadDocument(ctx, "doc1")
+	resp, err := service.FinalizeSplits(ctx, []string{"ready1", "unassigned", "done", "missing", "ready2", "ready1"})
 	require.NoError(t, err)
-	assert.Equal(t, "test.pdf", response.Filename)
-	assert.Equal(t, "application/pdf", response.ContentType)
-	assert.Equal(t, []byte("test data"), response.Data)
+	assert.Equal(t, 2, resp.Finalized)
+	assert.Equal(t, 2, resp.Failed)
+
+	outcomes := make(map[string]string)
+	var ids []string
+	for _, result := range resp.Results {
+		outcomes[result.SplitID] = result.Outcome
+		ids = append(ids, result.SplitID)
+	}
+	assert.Equal(t, []string{"ready1", "unassigned", "done", "missing", "ready2"}, ids)
+	assert.Equal(t, map[string]string{
+		"ready1":     FinalizeOutcomeFinalized,
+		"unassigned": FinalizeOutcomeFailed,
+		"done":       FinalizeOutcomeAlreadyFinalized,
+		"missing":    FinalizeOutcomeFailed,
+		"ready2":     FinalizeOutcomeFinalized,
+	}, outcomes)
+	assert.Contains(t, resp.Results[1].Error, "unassigned pages: unassigned-page2")
+	assert.Contains(t, resp.Results[3].Error, "not found")
+
+	// The failure did not roll back the others
+	for id, status := range map[string]domain.SplitStatus{
+		"ready1":     domain.SplitStatusFinalized,
+		"unassigned": domain.SplitStatusDraft,
+		"ready2":     domain.SplitStatusFinalized,
+	} {
+		loaded, err := service.LoadSplit(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, status, loaded.Status, id)
+	}
+
+	_, err = service.FinalizeSplits(ctx, make([]string, MaxFinalizeSplits+1))
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
 }
 
-// Helper function to create string pointer
-func stringPtr(s string) *string {
-	return &s
+func TestSplitService_DeleteDocuments(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	newSplit := func(id string, status domain.SplitStatus, docIDs ...string) *domain.Split {
+		split := &domain.Split{ID: id, ClientID: "client1", Status: status, CreatedAt: now, UpdatedAt: now}
+		for i, docID := range docIDs {
+			docID := docID
+			split.Documents = append(split.Documents, domain.Document{
+				ID: docID, SplitID: id, Name: "W-2", Classification: "W-2", Filename: docID + ".pdf",
+				Pages: []*domain.Page{{ID: docID + "-page", SplitID: id, DocumentID: &docID, PageNumber: i + 1, URL: "page.png"}},
+			})
+		}
+		if status == domain.SplitStatusFinalized {
+			split.FinalizedAt = &now
+		}
+		return split
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, newSplit("open", domain.SplitStatusDraft, "doc1", "doc2")))
+	require.NoError(t, uow.SplitRepository().Save(ctx, newSplit("done", domain.SplitStatusFinalized, "doc3")))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.DeleteDocuments(ctx, []string{"doc1", "doc3", "missing", "doc2", "doc1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Deleted)
+	assert.Equal(t, 2, resp.Failed)
+
+	outcomes := make(map[string]string)
+	var ids []string
+	for _, result := range resp.Results {
+		outcomes[result.DocumentID] = result.Outcome
+		ids = append(ids, result.DocumentID)
+	}
+	assert.Equal(t, []string{"doc1", "doc3", "missing", "doc2"}, ids)
+	assert.Equal(t, map[string]string{
+		"doc1":    DeleteOutcomeDeleted,
+		"doc3":    DeleteOutcomeFailed,
+		"missing": DeleteOutcomeFailed,
+		"doc2":    DeleteOutcomeDeleted,
+	}, outcomes)
+	assert.Contains(t, resp.Results[1].Error, "finalized")
+	assert.Contains(t, resp.Results[2].Error, "not found")
+
+	// The failures were rolled back to their savepoints and the others committed
+	open, err := service.LoadSplit(ctx, "open")
+	require.NoError(t, err)
+	assert.Empty(t, open.Documents)
+	assert.Len(t, open.UnassignedPages, 2)
+	done, err := service.LoadSplit(ctx, "done")
+	require.NoError(t, err)
+	assert.Len(t, done.Documents, 1)
+
+	_, err = service.DeleteDocuments(ctx, make([]string, MaxDeleteDocuments+1))
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+}
+
+func TestSplitService_FilenamePolicy(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	service.SetFilenamePolicy(domain.FilenamePolicyReplace)
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	docID := "legacy"
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			// Stored before the policy applied
+			{ID: docID, SplitID: "test-split", Name: "Legacy", Classification: "W-2", Filename: "../w2\".pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: &docID, PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+		UnassignedPages: []*domain.Page{
+			{ID: "page2", SplitID: "test-split", PageNumber: 2, URL: "page_2.png"},
+			{ID: "page3", SplitID: "test-split", PageNumber: 3, URL: "page_3.png"},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	doc, err := service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "Passwd", Classification: "W-2", Filename: "../../etc/passwd", PageIDs: []string{"page2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "passwd", doc.Filename)
+
+	filename := "report\n.pdf"
+	doc, err = service.UpdateDocumentMetadata(ctx, doc.ID, UpdateDocumentMetadataRequest{Filename: &filename})
+	require.NoError(t, err)
+	assert.Equal(t, "report_.pdf", doc.Filename)
+
+	download, err := service.DownloadDocument(ctx, docID)
+	require.NoError(t, err)
+	assert.Equal(t, "w2_.pdf", download.Filename)
+
+	// Reject refuses unsafe names on create and update, but stored ones still download
+	service.SetFilenamePolicy(domain.FilenamePolicyReject)
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "Passwd", Classification: "W-2", Filename: "../../etc/passwd", PageIDs: []string{"page3"},
+	})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	_, err = service.UpdateDocumentMetadata(ctx, doc.ID, UpdateDocumentMetadataRequest{Filename: &filename})
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	download, err = service.DownloadDocument(ctx, docID)
+	require.NoError(t, err)
+	assert.Equal(t, "w2_.pdf", download.Filename)
+}
+
+func TestSplitService_DownloadDocument(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	// Create test split with document
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{
+				ID:               "doc1",
+				SplitID:          "test-split",
+				Name:             "Test Document",
+				Classification:   "Test Class",
+				Filename:         "test.pdf",
+				ShortDescription: "Test Description",
+				StartPage:        "1",
+				EndPage:          "2",
+			},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Test downloading document
+	response, err := service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, "test.pdf", response.Filename)
+	assert.Equal(t, "application/pdf", response.ContentType)
+	assert.Equal(t, []byte("test data"), response.Data)
+	assert.True(t, split.UpdatedAt.Equal(response.ModifiedAt))
+}
+
+// Helper function to create string pointer
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestSplitService_ReorderDocuments(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	// Create test split with two documents
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{
+				ID:             "doc1",
+				SplitID:        "test-split",
+				Name:           "Document 1",
+				Classification: "Class 1",
+				Filename:       "doc1.pdf",
+				StartPage:      "page_1.png",
+				EndPage:        "page_1.png",
+			},
+			{
+				ID:             "doc2",
+				SplitID:        "test-split",
+				Name:           "Document 2",
+				Classification: "Class 2",
+				Filename:       "doc2.pdf",
+				StartPage:      "page_2.png",
+				EndPage:        "page_2.png",
+			},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Test reordering documents
+	response, err := service.ReorderDocuments(ctx, "test-split", ReorderDocumentsRequest{
+		DocumentIDs: []string{"doc2", "doc1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Documents, 2)
+	assert.Equal(t, "doc2", response.Documents[0].ID)
+
+	// Verify the order is persisted
+	loadedSplit, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	require.Len(t, loadedSplit.Documents, 2)
+	assert.Equal(t, "doc2", loadedSplit.Documents[0].ID)
+	assert.Equal(t, "doc1", loadedSplit.Documents[1].ID)
+
+	// Test reordering non-existent split
+	_, err = service.ReorderDocuments(ctx, "non-existent", ReorderDocumentsRequest{DocumentIDs: []string{"doc1"}})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assertNotFoundResource(t, err, "split", "non-existent")
+}
+
+func TestSplitService_RenameSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	// A split saved without a name loads with an empty one
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Name)
+
+	response, err := service.RenameSplit(ctx, "test-split", "Smith household")
+	require.NoError(t, err)
+	assert.Equal(t, "Smith household", response.Name)
+
+	loaded, err = service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "Smith household", loaded.Name)
+
+	_, err = service.RenameSplit(ctx, "test-split", strings.Repeat("a", domain.MaxSplitNameLength+1))
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+
+	_, err = service.RenameSplit(ctx, "non-existent", "name")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assertNotFoundResource(t, err, "split", "non-existent")
+}
+
+func TestSplitService_SplitTags(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for i, id := range []string{"split-a", "split-b"} {
+		created := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+			ID:        id,
+			ClientID:  "test-client",
+			Status:    domain.SplitStatusDraft,
+			CreatedAt: created,
+			UpdatedAt: created,
+		}))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	// Untagged splits load with an empty list
+	loaded, err := service.LoadSplit(ctx, "split-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, loaded.Tags)
+
+	// Tagging normalizes the tags and shows in later loads
+	response, err := service.AddSplitTags(ctx, "split-a", []string{"Urgent", " tax-2024 "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tax-2024", "urgent"}, response.Tags)
+	_, err = service.AddSplitTags(ctx, "split-b", []string{"tax-2024"})
+	require.NoError(t, err)
+	loaded, err = service.LoadSplit(ctx, "split-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tax-2024", "urgent"}, loaded.Tags)
+
+	// Filtering by tag, which is normalized like the tags
+	list, err := service.ListClientSplits(ctx, ListClientSplitsRequest{ClientID: "test-client", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Splits, 2)
+	assert.Equal(t, "split-b", list.Splits[0].ID)
+	assert.Equal(t, []string{"tax-2024"}, list.Splits[0].Tags)
+	list, err = service.ListClientSplits(ctx, ListClientSplitsRequest{ClientID: "test-client", Tag: "URGENT", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "urgent", list.Tag)
+	require.Len(t, list.Splits, 1)
+	assert.Equal(t, "split-a", list.Splits[0].ID)
+
+	// Untagging
+	response, err = service.RemoveSplitTags(ctx, "split-a", []string{"urgent"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tax-2024"}, response.Tags)
+	list, err = service.ListClientSplits(ctx, ListClientSplitsRequest{ClientID: "test-client", Tag: "urgent", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, list.Splits)
+
+	// Invalid tags and unknown splits
+	_, err = service.AddSplitTags(ctx, "split-a", []string{""})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	_, err = service.AddSplitTags(ctx, "non-existent", []string{"urgent"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Reads scoped to another client see none of its splits
+	_, err = service.ListClientSplits(WithOnBehalfOf(ctx, "other-client"), ListClientSplitsRequest{ClientID: "test-client", Limit: 10})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_SwapPages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "W-2"},
+			{ID: "doc2", SplitID: "test-split", Name: "Invoice", Pages: []*domain.Page{
+				{ID: "page5", SplitID: "test-split", DocumentID: stringPtr("doc2"), PageNumber: 5, URL: "page_5.png"},
+				{ID: "page6", SplitID: "test-split", DocumentID: stringPtr("doc2"), PageNumber: 6, URL: "page_6.png"},
+			}},
+		},
+	}
+	for n := 1; n <= 4; n++ {
+		split.Documents[0].Pages = append(split.Documents[0].Pages, &domain.Page{
+			ID: fmt.Sprintf("page%d", n), SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: n, URL: fmt.Sprintf("page_%d.png", n),
+		})
+	}
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	pageIDs := func(doc *DocumentResponse) []string {
+		ids := make([]string, len(doc.Pages))
+		for i, p := range doc.Pages {
+			ids[i] = p.ID
+		}
+		return ids
+	}
+
+	// The two pages trade places and the others keep theirs
+	resp, err := service.SwapPages(ctx, "doc1", SwapPagesRequest{PageA: "page2", PageB: "page4"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page1", "page4", "page3", "page2"}, pageIDs(resp))
+
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page1", "page4", "page3", "page2"}, pageIDs(loaded.Documents[0]))
+	assert.Equal(t, []string{"page5", "page6"}, pageIDs(loaded.Documents[1]))
+
+	// A page moved into the reordered document is placed last
+	moved, err := service.MovePages(ctx, MovePagesRequest{SplitID: "test-split", FromDocumentID: "doc2", ToDocumentID: "doc1", PageIDs: []string{"page5"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page1", "page4", "page3", "page2", "page5"}, pageIDs(moved.ToDocument))
+	loaded, err = service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"page1", "page4", "page3", "page2", "page5"}, pageIDs(loaded.Documents[0]))
+
+	// Both pages must belong to the document
+	_, err = service.SwapPages(ctx, "doc1", SwapPagesRequest{PageA: "page1", PageB: "page6"})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+
+	_, err = service.SwapPages(ctx, "missing", SwapPagesRequest{PageA: "page1", PageB: "page2"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Finalized splits are refused
+	_, err = db.Exec("UPDATE splits SET status = ? WHERE id = ?", domain.SplitStatusFinalized, "test-split")
+	require.NoError(t, err)
+	_, err = service.SwapPages(ctx, "doc1", SwapPagesRequest{PageA: "page1", PageB: "page2"})
+	assert.ErrorIs(t, err, domain.ErrSplitFinalized)
+}
+
+func TestSplitService_CountDocumentPages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+				{ID: "page2", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 2, URL: "page_2.png"},
+				{ID: "page3", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 3, URL: "page_3.png"},
+			}},
+			{ID: "doc2", SplitID: "test-split", Name: "Invoice"},
+		},
+		UnassignedPages: []*domain.Page{
+			{ID: "page4", SplitID: "test-split", PageNumber: 4, URL: "page_4.png"},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.CountDocumentPages(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Count)
+
+	resp, err = service.CountDocumentPages(ctx, "doc2")
+	require.NoError(t, err)
+	assert.Zero(t, resp.Count)
+
+	_, err = service.CountDocumentPages(ctx, "missing")
+	assertNotFoundResource(t, err, "document", "missing")
+
+	// Documents of other clients are hidden when acting on behalf of a client
+	_, err = service.CountDocumentPages(WithOnBehalfOf(ctx, "other-client"), "doc1")
+	assertNotFoundResource(t, err, "document", "doc1")
+}
+
+func TestSplitService_CheckConsistency(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "test-split", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// Seed rows a buggy deletion could leave behind
+	_, err = db.Exec(`
+		INSERT INTO pages (id, split_id, document_id, page_number, url) VALUES
+			('page2', 'test-split', 'deleted-doc', 2, 'page_2.png'),
+			('page3', 'deleted-split', NULL, 1, 'page_1.png');
+		INSERT INTO documents (id, split_id, name) VALUES ('doc2', 'deleted-split', 'Invoice');
+	`)
+	require.NoError(t, err)
+
+	// Checking reports the orphans without changing anything
+	report, err := service.CheckConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"doc2"}, report.OrphanedDocumentIDs)
+	assert.Equal(t, []string{"page3"}, report.OrphanedPageIDs)
+	assert.Equal(t, []string{"page2"}, report.UnlinkedPageIDs)
+	assert.Equal(t, 3, report.IssuesFound)
+	assert.Zero(t, report.IssuesFixed)
+	report, err = service.CheckConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.IssuesFound)
+
+	// Repairing deletes the rows of the missing split and unassigns the unlinked page
+	report, err = service.CheckConsistency(WithActor(ctx, "admin"), true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.IssuesFound)
+	assert.Equal(t, 3, report.IssuesFixed)
+
+	loaded, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	require.Len(t, loaded.UnassignedPages, 1)
+	assert.Equal(t, "page2", loaded.UnassignedPages[0].ID)
+	require.Len(t, loaded.Documents, 1)
+	assert.Len(t, loaded.Documents[0].Pages, 1)
+
+	report, err = service.CheckConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Zero(t, report.IssuesFound)
+	assert.Equal(t, []string{}, report.OrphanedPageIDs)
+
+	var actor string
+	require.NoError(t, db.QueryRow("SELECT actor FROM audit_log WHERE action = ?", domain.AuditActionRepairOrphans).Scan(&actor))
+	assert.Equal(t, "admin", actor)
+}
+
+func TestSplitService_GetPageContent(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	blobStore := &mockBlobStore{blobs: map[string]string{"page_1.png": "png data"}}
+	service := NewSplitService(uowFactory, &mockRenderService{}, blobStore)
+	ctx := context.Background()
+
+	// Create test split with pages
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UnassignedPages: []*domain.Page{
+			{ID: "page1", SplitID: "test-split", PageNumber: 1, URL: "page_1.png"},
+			{ID: "page2", SplitID: "test-split", PageNumber: 2, URL: "page_2.png"},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Test fetching page content
+	response, err := service.GetPageContent(ctx, "page1")
+	require.NoError(t, err)
+	defer response.Content.Close()
+	assert.Equal(t, "image/png", response.ContentType)
+	data, err := io.ReadAll(response.Content)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png data"), data)
+
+	// Test unknown page
+	_, err = service.GetPageContent(ctx, "non-existent")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assertNotFoundResource(t, err, "page", "non-existent")
+
+	// Test missing blob
+	_, err = service.GetPageContent(ctx, "page2")
+	assert.ErrorIs(t, err, ErrBlobUnavailable)
+}
+
+func TestSplitService_ClientStats(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UnassignedPages: []*domain.Page{
+			{ID: "page1", SplitID: "test-split", PageNumber: 1, URL: "page_1.png"},
+			{ID: "page2", SplitID: "test-split", PageNumber: 2, URL: "page_2.png"},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	response, err := service.ClientStats(ctx, "test-client")
+	require.NoError(t, err)
+	assert.Equal(t, &ClientStatsResponse{
+		ClientID:    "test-client",
+		TotalSplits: 1,
+		DraftSplits: 1,
+		TotalPages:  2,
+	}, response)
+
+	// Unknown clients get zero counts
+	response, err = service.ClientStats(ctx, "unknown-client")
+	require.NoError(t, err)
+	assert.Equal(t, &ClientStatsResponse{ClientID: "unknown-client"}, response)
+}
+
+func TestSplitService_LoadSplitUnassignedWarning(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UnassignedPages: []*domain.Page{
+			{ID: "page1", SplitID: "test-split", PageNumber: 1, URL: "page_1.png"},
+			{ID: "page2", SplitID: "test-split", PageNumber: 2, URL: "page_2.png"},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Disabled by default
+	response, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.False(t, response.UnassignedWarning)
+
+	// At the limit there is no warning
+	service.SetMaxUnassignedPages(2)
+	response, err = service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.False(t, response.UnassignedWarning)
+
+	// Above the limit the warning is set, but the split still loads
+	service.SetMaxUnassignedPages(1)
+	response, err = service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.True(t, response.UnassignedWarning)
+	assert.Len(t, response.UnassignedPages, 2)
+}
+
+func TestSplitService_DocumentNotFound(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	_, err := service.DownloadDocument(ctx, "non-existent")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assertNotFoundResource(t, err, "document", "non-existent")
+
+	err = service.DeleteDocument(ctx, "non-existent")
+	assertNotFoundResource(t, err, "document", "non-existent")
+}
+
+func TestSplitService_ListClients(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	// No splits yields an empty list rather than null
+	response, err := service.ListClients(ctx, ListClientsRequest{Limit: 10})
+	require.NoError(t, err)
+	assert.NotNil(t, response.Clients)
+	assert.Empty(t, response.Clients)
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for _, id := range []string{"split1", "split2"} {
+		split := &domain.Split{ID: id, ClientID: "test-client", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	response, err = service.ListClients(ctx, ListClientsRequest{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []*ClientSummaryResponse{{ClientID: "test-client", SplitCount: 2}}, response.Clients)
+}
+
+// panickingSplitRepository panics on Get to simulate a bug in a repository method
+type panickingSplitRepository struct {
+	domain.SplitRepository
+}
+
+func (r panickingSplitRepository) Get(ctx context.Context, id string) (*domain.Split, error) {
+	panic("repository failure")
+}
+
+// panickingUnitOfWork hands out a panicking split repository
+type panickingUnitOfWork struct {
+	ports.UnitOfWork
+}
+
+func (u panickingUnitOfWork) SplitRepository() domain.SplitRepository {
+	return panickingSplitRepository{u.UnitOfWork.SplitRepository()}
+}
+
+func TestSplitService_PanicRollsBackTransaction(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+	// With a single connection, a transaction left open would block the next request
+	db.SetMaxOpenConns(1)
+
+	panicking := true
+	factory := func(ctx context.Context) (ports.UnitOfWork, error) {
+		u, err := uowFactory(ctx)
+		if err != nil || !panicking {
+			return u, err
+		}
+		return panickingUnitOfWork{u}, nil
+	}
+	service := NewSplitService(factory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	assert.Panics(t, func() {
+		service.LoadSplit(ctx, "test-split")
+	})
+
+	panicking = false
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.LoadSplit(ctx, "test-split")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	case <-time.After(2 * time.Second):
+		t.Fatal("next request blocked by a transaction left open by the panic")
+	}
+}
+
+func TestSplitService_LoadSplitTimestamps(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: created,
+		UpdatedAt: created,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "test-split", Name: "Document 1"},
+		},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	// Timestamps round-trip
+	response, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.True(t, created.Equal(response.CreatedAt), "created_at %v", response.CreatedAt)
+	assert.True(t, created.Equal(response.UpdatedAt), "updated_at %v", response.UpdatedAt)
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"created_at":"2024-03-01T10:00:00Z"`)
+
+	// A mutation bumps updated_at but not created_at
+	before := time.Now()
+	newName := "Renamed"
+	_, err = service.UpdateDocumentMetadata(ctx, "doc1", UpdateDocumentMetadataRequest{Name: &newName})
+	require.NoError(t, err)
+
+	response, err = service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.True(t, created.Equal(response.CreatedAt))
+	assert.False(t, response.UpdatedAt.Before(before.Truncate(time.Microsecond)), "updated_at %v not after %v", response.UpdatedAt, before)
+}
+
+func TestSplitService_LoadSplitOrdering(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	page := func(n int, docID *string) *domain.Page {
+		return &domain.Page{ID: fmt.Sprintf("page%d", n), SplitID: "test-split", DocumentID: docID, PageNumber: n, URL: fmt.Sprintf("page_%d.png", n)}
+	}
+	now := time.Now()
+	// Page numbers chosen so that lexicographic order ("10" < "2" < "9") differs from numeric order
+	split := &domain.Split{
+		ID:        "test-split",
+		ClientID:  "test-client",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc-late", SplitID: "test-split", Name: "Late", Pages: []*domain.Page{page(10, stringPtr("doc-late")), page(11, stringPtr("doc-late"))}},
+			{ID: "doc-early", SplitID: "test-split", Name: "Early", Pages: []*domain.Page{page(9, stringPtr("doc-early")), page(2, stringPtr("doc-early"))}},
+		},
+		UnassignedPages: []*domain.Page{page(12, nil), page(3, nil)},
+	}
+	err = uow.SplitRepository().Save(ctx, split)
+	require.NoError(t, err)
+	err = uow.Commit(ctx)
+	require.NoError(t, err)
+
+	response, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+
+	// Documents are ordered by first page number
+	require.Len(t, response.Documents, 2)
+	assert.Equal(t, "doc-early", response.Documents[0].ID)
+	assert.Equal(t, "doc-late", response.Documents[1].ID)
+
+	// Pages within a document and unassigned pages are ordered by page number
+	pageNumbers := func(pages []*PageResponse) []string {
+		numbers := make([]string, len(pages))
+		for i, p := range pages {
+			numbers[i] = p.PageNumber
+		}
+		return numbers
+	}
+	assert.Equal(t, []string{"2", "9"}, pageNumbers(response.Documents[0].Pages))
+	assert.Equal(t, []string{"10", "11"}, pageNumbers(response.Documents[1].Pages))
+	assert.Equal(t, []string{"3", "12"}, pageNumbers(response.UnassignedPages))
+}
+
+func TestSplitService_DeleteSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+
+	now := time.Now()
+	for _, s := range []struct {
+		id     string
+		status domain.SplitStatus
+	}{{"draft-split", domain.SplitStatusDraft}, {"final-split", domain.SplitStatusFinalized}} {
+		split := &domain.Split{
+			ID:        s.id,
+			ClientID:  "test-client",
+			Status:    s.status,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Documents: []domain.Document{
+				{ID: s.id + "-doc", SplitID: s.id, Name: "Doc", Pages: []*domain.Page{
+					{ID: s.id + "-page", SplitID: s.id, DocumentID: stringPtr(s.id + "-doc"), PageNumber: 1, URL: "page_1.png"},
+				}},
+			},
+		}
+		require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	count := func(query string, args ...any) int {
+		var n int
+		require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+		return n
+	}
+
+	// Draft splits can be deleted normally
+	require.NoError(t, service.DeleteSplit(ctx, "draft-split"))
+	_, err = service.LoadSplit(ctx, "draft-split")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Finalized splits are refused without force
+	err = service.DeleteSplit(ctx, "final-split")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorConflict, domainErr.Kind)
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM audit_log`))
+
+	// Force delete removes the split, its documents, pages and classification
+	// history, and is audited
+	_, err = db.Exec(`INSERT INTO classification_history (id, document_id, old_classification, new_classification, changed_at)
+		VALUES ('h1', 'final-split-doc', 'Invoice', 'W-2', ?), ('h2', 'other-doc', 'Invoice', 'W-2', ?)`, now, now)
+	require.NoError(t, err)
+	require.NoError(t, service.ForceDeleteSplit(ctx, "final-split", "admin"))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM splits WHERE id = ?`, "final-split"))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM documents WHERE split_id = ?`, "final-split"))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM pages WHERE split_id = ?`, "final-split"))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM classification_history WHERE document_id = ?`, "final-split-doc"))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM classification_history`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM audit_log WHERE actor = ? AND action = ? AND resource_id = ?`,
+		"admin", domain.AuditActionForceDeleteSplit, "final-split"))
+
+	// Unknown splits
+	err = service.ForceDeleteSplit(ctx, "final-split", "admin")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_CancelledContextDoesNotBegin(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := service.LoadSplit(ctx, "test-split")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSplitService_PageBatchLimit(t *testing.T) {
+	factory := func(ctx context.Context) (ports.UnitOfWork, error) {
+		t.Fatal("unit of work begun for an over-limit batch")
+		return nil, nil
+	}
+	service := NewSplitService(factory, &mockRenderService{}, &mockBlobStore{})
+	service.SetMaxPageBatch(2)
+	ctx := context.Background()
+
+	pageIDs := []string{"page1", "page2", "page3"}
+	_, err := service.MovePages(ctx, MovePagesRequest{SplitID: "test-split", FromDocumentID: "doc1", ToDocumentID: "doc2", PageIDs: pageIDs})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "limit of 2")
+
+	_, err = service.CreateDocument(ctx, CreateDocumentRequest{SplitID: "test-split", Name: "Doc", PageIDs: pageIDs})
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+}
+
+func TestSplitService_ExportSplitJSON(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	original, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_2.png", "page_1.png"]}
+		]
+	}`)
+	require.NoError(t, err)
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, original))
+	require.NoError(t, uow.Commit(ctx))
+
+	data, err := service.ExportSplitJSON(ctx, "test-split")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "short_description": "", "page_urls": ["page_1.png", "page_2.png"]}
+		]
+	}`, string(data))
+
+	_, err = service.ExportSplitJSON(ctx, "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+func TestSplitService_ExportSplitCSV(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	original, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_1.png", "page_2.png", "page_3.png"]},
+			{"id": "doc2", "classification": "Invoice", "file_name": "inv.pdf", "name": "Invoice \"ACME, Inc.\"", "page_urls": ["page_4.png"]},
+			{"id": "doc3", "classification": "@SUM(A1)", "file_name": "x.pdf", "name": "=HYPERLINK(\"http://evil.example\",\"x\")", "page_urls": ["page_5.png"]}
+		]
+	}`)
+	require.NoError(t, err)
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, original))
+	require.NoError(t, uow.Commit(ctx))
+
+	data, err := service.ExportSplitCSV(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "name,classification,page_range,page_count\r\n"+
+		"W2,W-2,1-3,3\r\n"+
+		"\"Invoice \"\"ACME, Inc.\"\"\",Invoice,4,1\r\n"+
+		"\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"x\"\")\",'@SUM(A1),5,1\r\n"+
+		"Total,,,5\r\n", string(data))
+
+	_, err = service.ExportSplitCSV(ctx, "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+// recordingMetrics records observed operations and counter increments
+type recordingMetrics struct {
+	operations []string
+	counters   []string
+}
+
+func (m *recordingMetrics) IncCounter(name string, labels map[string]string) {
+	if labels["force"] != "" {
+		name += ":force=" + labels["force"]
+	}
+	m.counters = append(m.counters, name)
+}
+
+func (m *recordingMetrics) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	m.operations = append(m.operations, name)
+}
+
+func TestSplitService_DownloadDocumentRecordsLatency(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	recorder := &recordingMetrics{}
+	service.SetMetricsRecorder(recorder)
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_1.png"]}]
+	}`)
+	require.NoError(t, err)
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	_, err = service.DownloadDocument(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{OperationDownloadDocument}, recorder.operations)
+}
+
+func TestSplitService_RecordsBusinessCounters(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	recorder := &recordingMetrics{}
+	service.SetMetricsRecorder(recorder)
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_1.png", "page_2.png"]}]
+	}`)
+	require.NoError(t, err)
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+	pageIDs := []string{split.Documents[0].Pages[0].ID, split.Documents[0].Pages[1].ID}
+
+	_, err = service.DeletePages(ctx, "doc1", DeletePagesRequest{PageIDs: []string{"missing"}})
+	require.Error(t, err)
+	assert.Empty(t, recorder.counters, "failed operations are not counted")
+
+	require.NoError(t, service.DeleteDocument(ctx, "doc1"))
+	doc, err := service.CreateDocument(ctx, CreateDocumentRequest{
+		SplitID: "test-split", Name: "W2", Classification: "W-2", Filename: "w2.pdf", PageIDs: pageIDs,
+	})
+	require.NoError(t, err)
+	_, err = service.FinalizeSplit(ctx, "test-split")
+	require.NoError(t, err)
+	require.NoError(t, service.ForceDeleteSplit(ctx, "test-split", "admin"))
+
+	assert.NotEmpty(t, doc.ID)
+	assert.Equal(t, []string{
+		MetricDocumentsDeleted,
+		MetricDocumentsCreated,
+		MetricSplitsFinalized,
+		MetricSplitsDeleted + ":force=true",
+	}, recorder.counters)
+}
+
+func TestSplitService_ListClientPages(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"name": "Smith household",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "1099", "file_name": "a.pdf", "name": "A", "page_urls": ["page_1.png", "page_2.png"]},
+			{"id": "doc2", "classification": "W-2", "file_name": "b.pdf", "name": "B", "page_urls": ["page_3.png"]}
+		]
+	}`)
+	require.NoError(t, err)
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.ListClientPages(ctx, ListClientPagesRequest{ClientID: "test-client", Classification: "1099", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "1099", resp.Classification)
+	require.Len(t, resp.Pages, 2)
+	assert.Equal(t, "1", resp.Pages[0].PageNumber)
+	assert.Equal(t, "doc1", resp.Pages[0].DocumentID)
+	assert.Equal(t, "test-split", resp.Pages[0].SplitID)
+	assert.Equal(t, "Smith household", resp.Pages[0].SplitName)
+
+	_, err = service.ListClientPages(ctx, ListClientPagesRequest{ClientID: "test-client", Limit: 10})
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+}
+
+func TestSplitService_CreateDocumentGeneratesNames(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	service.SetDocumentNameTemplate("{classification} ({n})")
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{"split_id": "test-split", "client_id": "test-client", "status": "draft", "documents": []}`)
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		page, err := domain.NewPage("test-split", fmt.Sprintf("page_%d.png", i))
+		require.NoError(t, err)
+		split.UnassignedPages = append(split.UnassignedPages, page)
+	}
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	defer uow.Rollback(ctx)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	create := func(name, pageID string) *DocumentResponse {
+		doc, err := service.CreateDocument(ctx, CreateDocumentRequest{
+			SplitID: "test-split", Name: name, Classification: "W-2", Filename: "w2.pdf", PageIDs: []string{pageID},
+		})
+		require.NoError(t, err)
+		return doc
+	}
+
+	first := create("", split.UnassignedPages[0].ID)
+	second := create("", split.UnassignedPages[1].ID)
+	explicit := create("Employer W-2", split.UnassignedPages[2].ID)
+	assert.Equal(t, "W-2 (1)", first.Name)
+	assert.Equal(t, "W-2 (2)", second.Name)
+	assert.Equal(t, "Employer W-2", explicit.Name)
+}
+
+func TestSplitService_SplitLocks(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+	alice := WithActor(ctx, "alice")
+	bob := WithActor(ctx, "bob")
+
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "split1",
+		ClientID:  "client1",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "Invoice", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "http://test.com/1"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	assertLocked := func(t *testing.T, err error) {
+		t.Helper()
+		var domainErr *domain.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.DomainErrorLocked, domainErr.Kind)
+	}
+
+	_, err = service.LockSplit(ctx, "split1")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+
+	_, err = service.LockSplit(alice, "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+
+	lock, err := service.LockSplit(alice, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", lock.Holder)
+	assert.WithinDuration(t, time.Now().Add(DefaultLockTTL), lock.ExpiresAt, time.Minute)
+
+	// The holder can extend the lock and keep editing; everyone else is refused
+	_, err = service.LockSplit(alice, "split1")
+	require.NoError(t, err)
+	_, err = service.LockSplit(bob, "split1")
+	assertLocked(t, err)
+	_, err = service.UpdateDocumentMetadata(bob, "doc1", UpdateDocumentMetadataRequest{Name: stringPtr("Receipt")})
+	assertLocked(t, err)
+	_, err = service.FinalizeSplit(bob, "split1")
+	assertLocked(t, err)
+	assertLocked(t, service.DeleteSplit(ctx, "split1"))
+	assertLocked(t, service.UnlockSplit(bob, "split1"))
+	_, err = service.UpdateDocumentMetadata(alice, "doc1", UpdateDocumentMetadataRequest{Name: stringPtr("Receipt")})
+	require.NoError(t, err)
+
+	require.NoError(t, service.UnlockSplit(alice, "split1"))
+	require.NoError(t, service.UnlockSplit(alice, "split1"))
+	_, err = service.LockSplit(bob, "split1")
+	require.NoError(t, err)
+
+	// Expired locks no longer block anyone
+	_, err = db.Exec("UPDATE split_locks SET expires_at = ? WHERE split_id = ?", time.Now().Add(-time.Second), "split1")
+	require.NoError(t, err)
+	_, err = service.UpdateDocumentMetadata(alice, "doc1", UpdateDocumentMetadataRequest{Name: stringPtr("Statement")})
+	require.NoError(t, err)
+	lock, err = service.LockSplit(alice, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", lock.Holder)
+
+	// Deleting the split drops its lock
+	require.NoError(t, service.DeleteSplit(alice, "split1"))
+	var locks int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM split_locks").Scan(&locks))
+	assert.Equal(t, 0, locks)
+}
+
+func TestSplitService_LoadSplit_UnassignedPageURLs(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{"split_id": "test-split", "client_id": "test-client", "status": "draft", "documents": []}`)
+	require.NoError(t, err)
+	for _, url := range []string{"page_1.png", "page_2.png"} {
+		page, err := domain.NewPage("test-split", url)
+		require.NoError(t, err)
+		split.UnassignedPages = append(split.UnassignedPages, page)
+	}
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	// Unassigned pages carry their URL so clients can show thumbnails
+	resp, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	require.Len(t, resp.UnassignedPages, 2)
+	assert.Equal(t, "page_1.png", resp.UnassignedPages[0].URL)
+	assert.Equal(t, 1, resp.UnassignedPages[0].Number)
+	assert.Equal(t, "page_2.png", resp.UnassignedPages[1].URL)
+	assert.Equal(t, 2, resp.UnassignedPages[1].Number)
+}
+
+func TestSplitService_ResolvesRelativePageURLs(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	require.NoError(t, service.SetPageURLBase("https://cdn.example.com/pages"))
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "pages": [{"url": "page_1.png", "page_number": 1}, {"url": "s3://bucket/scan.png", "page_number": 2}]}]
+	}`)
+	require.NoError(t, err)
+	unassigned, err := domain.NewPage("test-split", "page_3.png")
+	require.NoError(t, err)
+	split.UnassignedPages = append(split.UnassignedPages, unassigned)
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	require.Len(t, resp.Documents, 1)
+	require.Len(t, resp.Documents[0].Pages, 2)
+	assert.Equal(t, "https://cdn.example.com/pages/page_1.png", resp.Documents[0].Pages[0].URL)
+	assert.Equal(t, "s3://bucket/scan.png", resp.Documents[0].Pages[1].URL)
+	require.Len(t, resp.UnassignedPages, 1)
+	assert.Equal(t, "https://cdn.example.com/pages/page_3.png", resp.UnassignedPages[0].URL)
+
+	pages, err := service.ListClientPages(ctx, ListClientPagesRequest{ClientID: "test-client", Classification: "W-2", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, pages.Pages, 2)
+	assert.Equal(t, "https://cdn.example.com/pages/page_1.png", pages.Pages[0].URL)
+
+	// The stored URL keeps its relative form
+	var stored string
+	require.NoError(t, db.QueryRow("SELECT url FROM pages WHERE url LIKE '%page_1.png'").Scan(&stored))
+	assert.Equal(t, "page_1.png", stored)
+}
+
+// connRecordingRenderService records the database connections in use while it renders
+type connRecordingRenderService struct {
+	ports.RenderService
+	db    *sql.DB
+	inUse []int
+}
+
+func (r *connRecordingRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	r.inUse = append(r.inUse, r.db.Stats().InUse)
+	return r.RenderService.RenderDocument(ctx, req)
+}
+
+func TestSplitService_PreviewSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	renderer := &connRecordingRenderService{RenderService: NewRenderService(), db: db}
+	service := NewSplitService(uowFactory, renderer, &mockBlobStore{})
+	ctx := context.Background()
+
+	split, err := domain.NewSplit(`{
+		"split_id": "test-split",
+		"client_id": "test-client",
+		"status": "draft",
+		"documents": [
+			{"id": "doc1", "classification": "W-2", "file_name": "w2.pdf", "name": "W2", "page_urls": ["page_1.png"]},
+			{"id": "doc2", "classification": "1099", "file_name": "1099.pdf", "name": "1099", "page_urls": ["page_2.png"]}
+		]
+	}`)
+	require.NoError(t, err)
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+	before, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+
+	resp, err := service.PreviewSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, "split-test-split-preview.pdf", resp.Filename)
+	assert.Equal(t, "application/pdf", resp.ContentType)
+	pdf := string(resp.Data)
+	assert.Contains(t, pdf, "/Count 4")
+	assert.Contains(t, pdf, "(Classification: W-2) Tj")
+	assert.Less(t, strings.Index(pdf, "(w2.pdf) Tj"), strings.Index(pdf, "(1099.pdf) Tj"))
+
+	// Documents are rendered after the split is loaded, holding no connection
+	assert.Equal(t, []int{0, 0}, renderer.inUse)
+
+	// Previewing changes nothing
+	after, err := service.LoadSplit(ctx, "test-split")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	_, err = service.PreviewSplit(ctx, "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+func TestSplitService_CheckFinalizeSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID:        "split1",
+		ClientID:  "client1",
+		Status:    domain.SplitStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Pages: []*domain.Page{
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 2, URL: "page_2.png"},
+			}},
+		},
+		UnassignedPages: []*domain.Page{{ID: "page3", SplitID: "split1", PageNumber: 3, URL: "page_3.png"}},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.CheckFinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, []string{
+		"validation: cannot finalize split with unassigned pages",
+		"invalid document doc1 in split split1: validation: document name is required",
+		"invalid document doc2 in split split1: validation: document filename is required",
+	}, resp.Issues)
+
+	// Finalizing reports the same issues, grouped by page and document
+	_, err = service.FinalizeSplit(ctx, "split1")
+	var blockers *domain.FinalizeBlockers
+	require.ErrorAs(t, err, &blockers)
+	assert.Equal(t, []string{"page3"}, blockers.UnassignedPageIDs)
+	require.Len(t, blockers.InvalidDocuments, 2)
+	assert.Equal(t, "doc1", blockers.InvalidDocuments[0].DocumentID)
+	assert.Equal(t, "doc2", blockers.InvalidDocuments[1].DocumentID)
+	assert.Empty(t, blockers.Issues)
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+
+	// Neither changes anything
+	loaded, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SplitStatusDraft, loaded.Status)
+
+	_, err = service.CheckFinalizeSplit(ctx, "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+func TestSplitService_BatchGetSplits(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	for _, id := range []string{"split1", "split2"} {
+		require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+			ID:        id,
+			ClientID:  "client1",
+			Status:    domain.SplitStatusDraft,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Documents: []domain.Document{
+				{ID: id + "-doc", SplitID: id, Name: "W2", Classification: "W-2", Pages: []*domain.Page{
+					{ID: id + "-page", SplitID: id, DocumentID: stringPtr(id + "-doc"), PageNumber: 1, URL: "page_1.png"},
+				}},
+			},
+		}))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.BatchGetSplits(ctx, BatchGetSplitsRequest{IDs: []string{"split2", "missing", "split1", "missing"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Splits, 2)
+	assert.Equal(t, []string{"missing"}, resp.Missing)
+	for i, id := range []string{"split2", "split1"} {
+		want, err := service.LoadSplit(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.Splits[i])
+	}
+
+	for _, ids := range [][]string{nil, make([]string, MaxBatchGetSplits+1)} {
+		_, err = service.BatchGetSplits(ctx, BatchGetSplitsRequest{IDs: ids})
+		var domainErr *domain.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	}
+}
+
+func TestSplitService_DiffSplits(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	pages := func(splitID string, docID *string, numbers ...int) []*domain.Page {
+		var pages []*domain.Page
+		for _, n := range numbers {
+			pages = append(pages, &domain.Page{
+				ID:         fmt.Sprintf("%s-page%d", splitID, n),
+				SplitID:    splitID,
+				DocumentID: docID,
+				PageNumber: n,
+				URL:        fmt.Sprintf("page_%d.png", n),
+			})
+		}
+		return pages
+	}
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	// The AI split and the corrected one: page 3 moved to a new document, page 6 was
+	// assigned, page 7 is new and page 8 was dropped
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "ai", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "ai-w2", SplitID: "ai", Name: "W2", Classification: "W-2", SortOrder: 1, Pages: pages("ai", stringPtr("ai-w2"), 1, 2, 3)},
+			{ID: "ai-1099", SplitID: "ai", Name: "1099", Classification: "1099", SortOrder: 2, Pages: pages("ai", stringPtr("ai-1099"), 4, 5)},
+		},
+		UnassignedPages: pages("ai", nil, 6, 8),
+	}))
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "fixed", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "fixed-w2", SplitID: "fixed", Name: "W2", Classification: "W-2", SortOrder: 1, Pages: pages("fixed", stringPtr("fixed-w2"), 1, 2, 6)},
+			{ID: "fixed-k1", SplitID: "fixed", Name: "K-1", Classification: "K-1", SortOrder: 2, Pages: pages("fixed", stringPtr("fixed-k1"), 3)},
+			{ID: "fixed-1099", SplitID: "fixed", Name: "1099", Classification: "1099", SortOrder: 3, Pages: pages("fixed", stringPtr("fixed-1099"), 4, 5, 7)},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	diff, err := service.DiffSplits(ctx, "ai", "fixed")
+	require.NoError(t, err)
+	assert.Equal(t, "ai", diff.SplitA)
+	assert.Equal(t, "fixed", diff.SplitB)
+	assert.Equal(t, []*DiffDocument{{ID: "fixed-k1", Name: "K-1", Classification: "K-1", PageURLs: []string{"page_3.png"}}}, diff.DocumentsAdded)
+	assert.Empty(t, diff.DocumentsRemoved)
+	assert.Equal(t, []string{"page_7.png"}, diff.PagesAdded)
+	assert.Equal(t, []string{"page_8.png"}, diff.PagesRemoved)
+	assert.Equal(t, []*PageReassignment{
+		{URL: "page_6.png", FromDocumentID: nil, ToDocumentID: stringPtr("fixed-w2")},
+		{URL: "page_3.png", FromDocumentID: stringPtr("ai-w2"), ToDocumentID: stringPtr("fixed-k1")},
+	}, diff.PagesReassigned)
+
+	// The reverse diff mirrors it
+	diff, err = service.DiffSplits(ctx, "fixed", "ai")
+	require.NoError(t, err)
+	assert.Empty(t, diff.DocumentsAdded)
+	require.Len(t, diff.DocumentsRemoved, 1)
+	assert.Equal(t, "fixed-k1", diff.DocumentsRemoved[0].ID)
+	assert.Equal(t, []string{"page_8.png"}, diff.PagesAdded)
+	assert.Equal(t, []string{"page_7.png"}, diff.PagesRemoved)
+	assert.Len(t, diff.PagesReassigned, 2)
+
+	// A split does not differ from itself
+	diff, err = service.DiffSplits(ctx, "ai", "ai")
+	require.NoError(t, err)
+	assert.Empty(t, diff.DocumentsAdded)
+	assert.Empty(t, diff.DocumentsRemoved)
+	assert.Empty(t, diff.PagesAdded)
+	assert.Empty(t, diff.PagesRemoved)
+	assert.Empty(t, diff.PagesReassigned)
+
+	_, err = service.DiffSplits(ctx, "ai", "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+func TestSplitService_ReviewDocument(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+	alice := WithActor(ctx, "alice")
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Filename: "1099.pdf", Pages: []*domain.Page{
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 2, URL: "page_2.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// The reviewer comes from the context
+	_, err = service.ReviewDocument(ctx, "doc1", true)
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+
+	doc, err := service.ReviewDocument(alice, "doc1", true)
+	require.NoError(t, err)
+	assert.True(t, doc.Reviewed)
+	assert.Equal(t, "alice", doc.ReviewedBy)
+	assert.NotNil(t, doc.ReviewedAt)
+
+	loaded, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.True(t, loaded.Documents[0].Reviewed)
+	assert.False(t, loaded.Documents[1].Reviewed)
+
+	// Toggling it off clears the reviewer
+	doc, err = service.ReviewDocument(alice, "doc1", false)
+	require.NoError(t, err)
+	assert.False(t, doc.Reviewed)
+	assert.Empty(t, doc.ReviewedBy)
+	assert.Nil(t, doc.ReviewedAt)
+
+	_, err = service.ReviewDocument(alice, "missing", true)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSplitService_FinalizeRequiresReview(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	service.SetRequireReview(true)
+	ctx := WithActor(context.Background(), "alice")
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Filename: "1099.pdf", Pages: []*domain.Page{
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 2, URL: "page_2.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	_, err = service.ReviewDocument(ctx, "doc1", true)
+	require.NoError(t, err)
+
+	// Finalizing is refused while a document is unreviewed, and the dry run says why
+	_, err = service.FinalizeSplit(ctx, "split1")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "unreviewed documents: doc2")
+	check, err := service.CheckFinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.False(t, check.Ready)
+	assert.Equal(t, []string{"validation: cannot finalize split with unreviewed documents: doc2"}, check.Issues)
+
+	_, err = service.ReviewDocument(ctx, "doc2", true)
+	require.NoError(t, err)
+	check, err = service.CheckFinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.True(t, check.Ready)
+	_, err = service.FinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+}
+
+func TestSplitService_FinalizeRequiresClassification(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := WithActor(context.Background(), "alice")
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Filename: "w2.pdf", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "Scan", Classification: "unclassified", Filename: "scan.pdf", Pages: []*domain.Page{
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 2, URL: "page_2.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	// Off by default, the default classification passes
+	check, err := service.CheckFinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.True(t, check.Ready)
+
+	// Finalizing is refused while a document has the default classification
+	service.SetRequireClassification(true)
+	_, err = service.FinalizeSplit(ctx, "split1")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "unclassified documents: doc2")
+	check, err = service.CheckFinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.False(t, check.Ready)
+	assert.Equal(t, []string{"validation: cannot finalize split with unclassified documents: doc2"}, check.Issues)
+
+	_, err = service.ReclassifyDocument(ctx, "doc2", "1099")
+	require.NoError(t, err)
+	_, err = service.FinalizeSplit(ctx, "split1")
+	require.NoError(t, err)
+}
+
+func TestSplitService_LoadSplitParts(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+		},
+		UnassignedPages: []*domain.Page{{ID: "page2", SplitID: "split1", PageNumber: 2, URL: "page_2.png"}},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	full, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	require.NotNil(t, full.CompletionPercent)
+	assert.Equal(t, 50.0, *full.CompletionPercent)
+
+	// Both parts match a full load
+	resp, err := service.LoadSplitParts(ctx, LoadSplitRequest{ID: "split1", IncludeDocuments: true, IncludeUnassigned: true})
+	require.NoError(t, err)
+	assert.Equal(t, full, resp)
+
+	// Excluded parts are null in the response
+	resp, err = service.LoadSplitParts(ctx, LoadSplitRequest{ID: "split1", IncludeDocuments: true})
+	require.NoError(t, err)
+	assert.Equal(t, full.Documents, resp.Documents)
+	assert.Nil(t, resp.UnassignedPages)
+	assert.Nil(t, resp.CompletionPercent)
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"unassigned_pages":null`)
+	assert.Contains(t, string(data), `"completion_percent":null`)
+
+	resp, err = service.LoadSplitParts(ctx, LoadSplitRequest{ID: "split1", IncludeUnassigned: true})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Documents)
+	assert.Equal(t, full.UnassignedPages, resp.UnassignedPages)
+
+	_, err = service.LoadSplitParts(ctx, LoadSplitRequest{ID: "missing", IncludeDocuments: true})
+	assertNotFoundResource(t, err, "split", "missing")
+}
+
+func TestSplitService_OnBehalfOf(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	for _, split := range []*domain.Split{
+		{ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now},
+		{ID: "split2", ClientID: "client2", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now},
+	} {
+		require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	}
+	require.NoError(t, uow.Commit(ctx))
+
+	scoped := WithOnBehalfOf(ctx, "client1")
+
+	// Only the scoped client's splits are visible
+	_, err = service.LoadSplit(scoped, "split1")
+	require.NoError(t, err)
+	_, err = service.LoadSplit(scoped, "split2")
+	assertNotFoundResource(t, err, "split", "split2")
+	batch, err := service.BatchGetSplits(scoped, BatchGetSplitsRequest{IDs: []string{"split1", "split2"}})
+	require.NoError(t, err)
+	require.Len(t, batch.Splits, 1)
+	assert.Equal(t, "split1", batch.Splits[0].ID)
+	assert.Equal(t, []string{"split2"}, batch.Missing)
+	_, err = service.ClientStats(scoped, "client2")
+	assertNotFoundResource(t, err, "client", "client2")
+	clients, err := service.ListClients(scoped, ListClientsRequest{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, clients.Clients, 1)
+	assert.Equal(t, "client1", clients.Clients[0].ClientID)
+
+	// Unscoped reads see everything
+	_, err = service.LoadSplit(ctx, "split2")
+	require.NoError(t, err)
+
+	// Impersonation is audited
+	require.NoError(t, service.RecordImpersonation(ctx, "admin", "client1"))
+	var actor, resourceID string
+	require.NoError(t, db.QueryRow(`SELECT actor, resource_id FROM audit_log WHERE action = ?`, domain.AuditActionImpersonateClient).Scan(&actor, &resourceID))
+	assert.Equal(t, "admin", actor)
+	assert.Equal(t, "client1", resourceID)
+}
+
+func TestSplitService_MoveDocumentToSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+				{ID: "page2", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 2, URL: "page_2.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Pages: []*domain.Page{
+				{ID: "page3", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 3, URL: "page_3.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split2", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc3", SplitID: "split2", Name: "Invoice", Classification: "Invoice", Pages: []*domain.Page{
+				{ID: "page4", SplitID: "split2", DocumentID: stringPtr("doc3"), PageNumber: 1, URL: "other_1.png"},
+			}},
+		},
+	}))
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split3", ClientID: "client1", Status: domain.SplitStatusFinalized, CreatedAt: now, UpdatedAt: now,
+	}))
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split4", ClientID: "client2", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	documentIDs := func(splitID string) []string {
+		loaded, err := service.LoadSplit(ctx, splitID)
+		require.NoError(t, err)
+		var ids []string
+		for _, doc := range loaded.Documents {
+			ids = append(ids, doc.ID)
+		}
+		return ids
+	}
+
+	// The document and its pages change splits together; page 1 is taken in split2,
+	// so the moved pages continue after its last page
+	doc, err := service.MoveDocumentToSplit(ctx, "doc1", "split2")
+	require.NoError(t, err)
+	assert.Equal(t, "split2", doc.SplitID)
+	require.Len(t, doc.Pages, 2)
+	assert.Equal(t, 2, doc.Pages[0].Number)
+	assert.Equal(t, 3, doc.Pages[1].Number)
+	assert.Equal(t, []string{"doc2"}, documentIDs("split1"))
+	assert.ElementsMatch(t, []string{"doc1", "doc3"}, documentIDs("split2"))
+	for pageID, number := range map[string]int{"page1": 2, "page2": 3} {
+		var splitID, documentID string
+		var pageNumber int
+		require.NoError(t, db.QueryRow("SELECT split_id, document_id, CAST(page_number AS INTEGER) FROM pages WHERE id = ?", pageID).Scan(&splitID, &documentID, &pageNumber))
+		assert.Equal(t, "split2", splitID)
+		assert.Equal(t, "doc1", documentID)
+		assert.Equal(t, number, pageNumber)
+	}
+
+	// Finalized splits, unknown targets and the document's own split are rejected
+	_, err = service.MoveDocumentToSplit(ctx, "doc2", "split3")
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorConflict, domainErr.Kind)
+	_, err = service.MoveDocumentToSplit(ctx, "doc2", "missing")
+	assertNotFoundResource(t, err, "split", "missing")
+	_, err = service.MoveDocumentToSplit(ctx, "missing", "split2")
+	assertNotFoundResource(t, err, "document", "missing")
+	_, err = service.MoveDocumentToSplit(ctx, "doc2", "split1")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Equal(t, []string{"doc2"}, documentIDs("split1"))
+
+	// Documents never move to another client's split
+	_, err = service.MoveDocumentToSplit(ctx, "doc2", "split4")
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.DomainErrorValidation, domainErr.Kind)
+	assert.Contains(t, err.Error(), "another client")
+	assert.Equal(t, []string{"doc2"}, documentIDs("split1"))
+	assert.Empty(t, documentIDs("split4"))
+
+	// A failure after the target is partly written rolls back both splits
+	_, err = db.Exec(`CREATE TRIGGER fail_page_move BEFORE UPDATE OF split_id ON pages
+		WHEN NEW.id = 'page3' BEGIN SELECT RAISE(ABORT, 'page write failed'); END`)
+	require.NoError(t, err)
+	_, err = service.MoveDocumentToSplit(ctx, "doc2", "split2")
+	require.Error(t, err)
+	assert.Equal(t, []string{"doc2"}, documentIDs("split1"))
+	assert.ElementsMatch(t, []string{"doc1", "doc3"}, documentIDs("split2"))
+	var splitID string
+	require.NoError(t, db.QueryRow("SELECT split_id FROM documents WHERE id = 'doc2'").Scan(&splitID))
+	assert.Equal(t, "split1", splitID)
+}
+
+func TestSplitService_LoadSplitClassificationCounts(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	split := &domain.Split{ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now}
+	for i, classification := range []string{"W-2", "Invoice", "W-2", "", "Invoice", "W-2"} {
+		docID := fmt.Sprintf("doc%d", i+1)
+		split.Documents = append(split.Documents, domain.Document{
+			ID: docID, SplitID: "split1", Name: docID, Classification: classification,
+			Pages: []*domain.Page{{ID: fmt.Sprintf("page%d", i+1), SplitID: "split1", DocumentID: stringPtr(docID), PageNumber: i + 1, URL: fmt.Sprintf("page_%d.png", i+1)}},
+		})
+	}
+	split.UnassignedPages = []*domain.Page{
+		{ID: "page7", SplitID: "split1", PageNumber: 7, URL: "page_7.png"},
+		{ID: "page8", SplitID: "split1", PageNumber: 8, URL: "page_8.png"},
+	}
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, split))
+	require.NoError(t, uow.Commit(ctx))
+
+	resp, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"W-2": 3, "Invoice": 2, "unclassified": 1}, resp.ClassificationCounts)
+	require.NotNil(t, resp.UnassignedPageCount)
+	assert.Equal(t, 2, *resp.UnassignedPageCount)
+
+	// Counts of excluded parts are null
+	resp, err = service.LoadSplitParts(ctx, LoadSplitRequest{ID: "split1", IncludeUnassigned: true})
+	require.NoError(t, err)
+	assert.Nil(t, resp.ClassificationCounts)
+	assert.Equal(t, 2, *resp.UnassignedPageCount)
+	resp, err = service.LoadSplitParts(ctx, LoadSplitRequest{ID: "split1", IncludeDocuments: true})
+	require.NoError(t, err)
+	assert.Len(t, resp.ClassificationCounts, 3)
+	assert.Nil(t, resp.UnassignedPageCount)
+}
+
+func TestSplitService_StreamSplit(t *testing.T) {
+	db, uowFactory := setupTestDB(t)
+	defer db.Close()
+
+	service := NewSplitService(uowFactory, &mockRenderService{}, &mockBlobStore{})
+	ctx := context.Background()
+
+	now := time.Now()
+	uow, err := uowFactory(ctx)
+	require.NoError(t, err)
+	require.NoError(t, uow.SplitRepository().Save(ctx, &domain.Split{
+		ID: "split1", ClientID: "client1", Status: domain.SplitStatusDraft, CreatedAt: now, UpdatedAt: now,
+		Documents: []domain.Document{
+			{ID: "doc1", SplitID: "split1", Name: "W2", Classification: "W-2", Pages: []*domain.Page{
+				{ID: "page1", SplitID: "split1", DocumentID: stringPtr("doc1"), PageNumber: 1, URL: "page_1.png"},
+			}},
+			{ID: "doc2", SplitID: "split1", Name: "1099", Classification: "1099", Pages: []*domain.Page{
+				{ID: "page3", SplitID: "split1", DocumentID: stringPtr("doc2"), PageNumber: 3, URL: "page_3.png"},
+			}},
+		},
+		UnassignedPages: []*domain.Page{{ID: "page2", SplitID: "split1", PageNumber: 2, URL: "page_2.png"}},
+	}))
+	require.NoError(t, uow.Commit(ctx))
+
+	full, err := service.LoadSplit(ctx, "split1")
+	require.NoError(t, err)
+	expected, err := json.Marshal(full)
+	require.NoError(t, err)
+
+	// The streamed JSON holds the same object as the buffered response
+	var buf bytes.Buffer
+	require.NoError(t, service.StreamSplit(ctx, "split1", &buf))
+	assert.JSONEq(t, string(expected), buf.String())
+
+	// A missing split is reported before anything is written
+	buf.Reset()
+	err = service.StreamSplit(ctx, "missing", &buf)
+	assertNotFoundResource(t, err, "split", "missing")
+	assert.Zero(t, buf.Len())
 }
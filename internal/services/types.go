@@ -3,35 +3,98 @@ package services
 import (
 	"accounting/internal/domain"
 	"context"
+	"errors"
+	"io"
+	"time"
 )
 
-// PageResponse represents a page in the API
+// PageResponse represents a page in the API. PageNumber is the page number as a
+// string, kept for existing clients; Number is the same value as a JSON number.
 type PageResponse struct {
 	ID         string `json:"id"`
 	PageNumber string `json:"page_number"`
+	Number     int    `json:"number"`
 	URL        string `json:"url"`
 }
 
 // DocumentResponse represents a document in the API
 type DocumentResponse struct {
-	ID               string          `json:"id"`
-	SplitID          string          `json:"split_id"`
-	Name             string          `json:"name"`
-	Classification   string          `json:"classification"`
-	Filename         string          `json:"filename"`
-	ShortDescription string          `json:"short_description"`
-	StartPage        string          `json:"start_page"`
-	EndPage          string          `json:"end_page"`
-	Pages            []*PageResponse `json:"pages"`
-}
-
-// LoadSplitResponse represents a split in the API
+	ID               string `json:"id"`
+	SplitID          string `json:"split_id"`
+	Name             string `json:"name"`
+	Classification   string `json:"classification"`
+	Filename         string `json:"filename"`
+	ShortDescription string `json:"short_description"`
+	StartPage        string `json:"start_page"`
+	EndPage          string `json:"end_page"`
+	// StartPageNumber, EndPageNumber and PageRange ("3-7", or "3" for one page) span the
+	// lowest to the highest page number; they are omitted for documents without pages
+	StartPageNumber int    `json:"start_page_number,omitempty"`
+	EndPageNumber   int    `json:"end_page_number,omitempty"`
+	PageRange       string `json:"page_range,omitempty"`
+	// Contiguous is false when page numbers are missing between the lowest and the
+	// highest; documents with one page or none are contiguous
+	Contiguous bool       `json:"contiguous"`
+	SortOrder  int        `json:"sort_order"`
+	Reviewed   bool       `json:"reviewed"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	// LastModifiedBy is the subject who last changed the document, if recorded
+	LastModifiedBy string          `json:"last_modified_by,omitempty"`
+	Pages          []*PageResponse `json:"pages"`
+}
+
+// LoadSplitResponse represents a split in the API.
+// Documents are ordered by their custom sort order, then by first page number;
+// pages within a document and unassigned pages are ordered by page number.
 type LoadSplitResponse struct {
 	ID              string              `json:"id"`
 	ClientID        string              `json:"client_id"`
+	Name            string              `json:"name,omitempty"`
 	Status          domain.SplitStatus  `json:"status"`
 	Documents       []*DocumentResponse `json:"documents"`
 	UnassignedPages []*PageResponse     `json:"unassigned_pages"`
+	// ClassificationCounts is the number of documents per classification, with
+	// documents without one under "unclassified"
+	ClassificationCounts map[string]int `json:"classification_counts"`
+	UnassignedPageCount  *int           `json:"unassigned_page_count"`
+	// CompletionPercent is the share of pages assigned to a document, from 0 to 100
+	CompletionPercent *float64  `json:"completion_percent"`
+	Tags              []string  `json:"tags"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	// LastModifiedBy is the subject who last changed the split, if recorded
+	LastModifiedBy string `json:"last_modified_by,omitempty"`
+	// UnassignedWarning is set when the split has more unassigned pages than the configured limit
+	UnassignedWarning bool `json:"unassigned_warning,omitempty"`
+}
+
+// UpdateSplitRequest represents a request to update a split; name is currently the only field
+type UpdateSplitRequest struct {
+	Name *string `json:"name"`
+}
+
+// LoadSplitRequest represents a request to load a split with only some of its parts
+type LoadSplitRequest struct {
+	ID                string
+	IncludeDocuments  bool
+	IncludeUnassigned bool
+}
+
+// BatchGetSplitsRequest represents a request to load several splits at once
+type BatchGetSplitsRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// IngestSplitResponse identifies the split created from an upload
+type IngestSplitResponse struct {
+	SplitID string `json:"split_id"`
+}
+
+// BatchGetSplitsResponse holds the splits found, in the order requested, and the IDs not found
+type BatchGetSplitsResponse struct {
+	Splits  []*LoadSplitResponse `json:"splits"`
+	Missing []string             `json:"missing"`
 }
 
 // UpdateDocumentMetadataRequest represents a request to update document metadata
@@ -39,6 +102,22 @@ type UpdateDocumentMetadataRequest struct {
 	Name             *string `json:"name,omitempty"`
 	Classification   *string `json:"classification,omitempty"`
 	ShortDescription *string `json:"short_description,omitempty"`
+	Filename         *string `json:"filename,omitempty"`
+}
+
+// ReclassifyDocumentRequest represents a request to change a document's classification
+type ReclassifyDocumentRequest struct {
+	Classification string `json:"classification"`
+}
+
+// MoveDocumentToSplitRequest represents a request to move a document to another split
+type MoveDocumentToSplitRequest struct {
+	SplitID string `json:"split_id"`
+}
+
+// DeletePagesRequest represents a request to drop pages from a document
+type DeletePagesRequest struct {
+	PageIDs []string `json:"page_ids"`
 }
 
 // MovePagesRequest represents a request to move pages between documents
@@ -63,6 +142,25 @@ type CreateDocumentRequest struct {
 	Filename         string   `json:"filename"`
 	ShortDescription string   `json:"short_description"`
 	PageIDs          []string `json:"page_ids"`
+	// PageRange selects the split's unassigned pages by page number, e.g. "3-7",
+	// instead of listing PageIDs
+	PageRange string `json:"page_range,omitempty"`
+}
+
+// ReorderDocumentsRequest represents a request to set the order of documents in a split
+type ReorderDocumentsRequest struct {
+	DocumentIDs []string `json:"document_ids"`
+}
+
+// SwapPagesRequest represents a request to swap the positions of two pages in a document
+type SwapPagesRequest struct {
+	PageA string `json:"page_a"`
+	PageB string `json:"page_b"`
+}
+
+// PageCountResponse holds the number of pages of a document
+type PageCountResponse struct {
+	Count int `json:"count"`
 }
 
 // DeleteDocumentRequest represents a request to delete a document
@@ -75,18 +173,312 @@ type DownloadDocumentResponse struct {
 	Filename    string `json:"filename"`
 	ContentType string `json:"content_type"`
 	Data        []byte `json:"data"`
+	ETag        string `json:"etag"`
+	// ModifiedAt is when the document's split last changed, served as Last-Modified
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// DocumentDownloadInfoResponse describes a document's download without its bytes
+type DocumentDownloadInfoResponse struct {
+	DocumentID  string `json:"document_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	PageCount   int    `json:"page_count"`
+	// Size is the byte size of the download; SizeEstimated is set when the document
+	// has not been rendered yet and Size is estimated from its page count
+	Size          int64     `json:"size"`
+	SizeEstimated bool      `json:"size_estimated"`
+	ETag          string    `json:"etag"`
+	ModifiedAt    time.Time `json:"modified_at"`
+}
+
+// SplitDiff reports how split B differs from split A, e.g. a corrected split from
+// the one first produced. Pages are matched by URL, since page IDs differ across splits.
+type SplitDiff struct {
+	SplitA           string              `json:"split_a"`
+	SplitB           string              `json:"split_b"`
+	DocumentsAdded   []*DiffDocument     `json:"documents_added"`
+	DocumentsRemoved []*DiffDocument     `json:"documents_removed"`
+	PagesAdded       []string            `json:"pages_added"`
+	PagesRemoved     []string            `json:"pages_removed"`
+	PagesReassigned  []*PageReassignment `json:"pages_reassigned"`
+}
+
+// DiffDocument identifies a document without a counterpart in the other split
+type DiffDocument struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Classification string   `json:"classification"`
+	PageURLs       []string `json:"page_urls"`
+}
+
+// PageReassignment is a page present in both splits under documents that do not
+// correspond. A nil document ID means the page is unassigned in that split.
+type PageReassignment struct {
+	URL            string  `json:"url"`
+	FromDocumentID *string `json:"from_document_id"`
+	ToDocumentID   *string `json:"to_document_id"`
+}
+
+// FinalizeSplitResponse reports the outcome of finalizing a split
+type FinalizeSplitResponse struct {
+	SplitID          string     `json:"split_id"`
+	FinalizedAt      *time.Time `json:"finalized_at,omitempty"`
+	AlreadyFinalized bool       `json:"already_finalized"`
+}
+
+// FinalizeSplitsRequest represents a request to finalize several splits at once
+type FinalizeSplitsRequest struct {
+	SplitIDs []string `json:"split_ids"`
+}
+
+// Outcomes of finalizing one split of a batch
+const (
+	FinalizeOutcomeFinalized        = "finalized"
+	FinalizeOutcomeAlreadyFinalized = "already_finalized"
+	FinalizeOutcomeFailed           = "failed"
+)
+
+// FinalizeSplitResult reports the outcome of finalizing one split of a batch, with
+// the reason when it failed
+type FinalizeSplitResult struct {
+	SplitID     string     `json:"split_id"`
+	Outcome     string     `json:"outcome"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// FinalizeSplitsResponse reports the outcome for each split of a batch, in the order requested
+type FinalizeSplitsResponse struct {
+	Results   []*FinalizeSplitResult `json:"results"`
+	Finalized int                    `json:"finalized"`
+	Failed    int                    `json:"failed"`
+}
+
+// DeleteDocumentsRequest represents a request to delete several documents at once
+type DeleteDocumentsRequest struct {
+	DocumentIDs []string `json:"document_ids"`
+}
+
+// Outcomes of deleting one document of a batch
+const (
+	DeleteOutcomeDeleted = "deleted"
+	DeleteOutcomeFailed  = "failed"
+)
+
+// DeleteDocumentResult reports the outcome of deleting one document of a batch, with
+// the reason when it failed
+type DeleteDocumentResult struct {
+	DocumentID string `json:"document_id"`
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DeleteDocumentsResponse reports the outcome for each document of a batch, in the order requested
+type DeleteDocumentsResponse struct {
+	Results []*DeleteDocumentResult `json:"results"`
+	Deleted int                     `json:"deleted"`
+	Failed  int                     `json:"failed"`
+}
+
+// FinalizeCheckResponse reports whether a split can be finalized and every issue preventing it
+type FinalizeCheckResponse struct {
+	SplitID string   `json:"split_id"`
+	Ready   bool     `json:"ready"`
+	Issues  []string `json:"issues"`
+}
+
+// FinalizeBlockedResponse lists every issue refusing a finalize, grouped so a client
+// can highlight each unassigned page and invalid document
+type FinalizeBlockedResponse struct {
+	Error             string                     `json:"error"`
+	UnassignedPageIDs []string                   `json:"unassigned_page_ids"`
+	InvalidDocuments  []*InvalidDocumentResponse `json:"invalid_documents"`
+	Issues            []string                   `json:"issues"`
+}
+
+// InvalidDocumentResponse is a document blocking a finalize and the reasons why
+type InvalidDocumentResponse struct {
+	DocumentID string   `json:"document_id"`
+	Reasons    []string `json:"reasons"`
+}
+
+// PreviewSplitResponse represents the combined preview PDF of a split
+type PreviewSplitResponse struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// PageContentResponse represents the raw content of a single page.
+// The caller is responsible for closing Content.
+type PageContentResponse struct {
+	ContentType string
+	Content     io.ReadCloser
+}
+
+// ClientStatsResponse represents aggregate counts for a client in the API
+type ClientStatsResponse struct {
+	ClientID        string `json:"client_id"`
+	TotalSplits     int    `json:"total_splits"`
+	DraftSplits     int    `json:"draft_splits"`
+	FinalizedSplits int    `json:"finalized_splits"`
+	TotalDocuments  int    `json:"total_documents"`
+	TotalPages      int    `json:"total_pages"`
+}
+
+// ListClientsRequest represents a page of the client list
+type ListClientsRequest struct {
+	Limit  int
+	Offset int
+}
+
+// ClientSummaryResponse represents a client and its split count in the API
+type ClientSummaryResponse struct {
+	ClientID   string `json:"client_id"`
+	SplitCount int    `json:"split_count"`
+}
+
+// ListClientsResponse represents a page of clients in the API
+type ListClientsResponse struct {
+	Clients []*ClientSummaryResponse `json:"clients"`
+	Limit   int                      `json:"limit"`
+	Offset  int                      `json:"offset"`
+}
+
+// SplitTagsRequest represents a request to add or remove tags of a split
+type SplitTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// ListClientSplitsRequest represents a page of a client's splits, optionally only those with a tag
+type ListClientSplitsRequest struct {
+	ClientID string
+	Tag      string
+	Limit    int
+	Offset   int
+}
+
+// SplitSummaryResponse represents a split without its documents and pages in the API
+type SplitSummaryResponse struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name,omitempty"`
+	Status    domain.SplitStatus `json:"status"`
+	Tags      []string           `json:"tags"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	// LastModifiedBy is the subject who last changed the split, if recorded
+	LastModifiedBy string     `json:"last_modified_by,omitempty"`
+	FinalizedAt    *time.Time `json:"finalized_at,omitempty"`
+}
+
+// ListClientSplitsResponse represents a page of a client's splits in the API
+type ListClientSplitsResponse struct {
+	ClientID string                  `json:"client_id"`
+	Tag      string                  `json:"tag,omitempty"`
+	Splits   []*SplitSummaryResponse `json:"splits"`
+	Limit    int                     `json:"limit"`
+	Offset   int                     `json:"offset"`
+}
+
+// ListClientPagesRequest represents a page of a client's pages with a given document classification
+type ListClientPagesRequest struct {
+	ClientID       string
+	Classification string
+	Limit          int
+	Offset         int
+}
+
+// ClientPageResponse represents a page reference with its document and split in the API
+type ClientPageResponse struct {
+	PageID     string `json:"page_id"`
+	PageNumber string `json:"page_number"`
+	URL        string `json:"url"`
+	DocumentID string `json:"document_id"`
+	SplitID    string `json:"split_id"`
+	SplitName  string `json:"split_name,omitempty"`
+}
+
+// ListClientPagesResponse represents a page of a client's pages in the API
+type ListClientPagesResponse struct {
+	ClientID       string                `json:"client_id"`
+	Classification string                `json:"classification"`
+	Pages          []*ClientPageResponse `json:"pages"`
+	Limit          int                   `json:"limit"`
+	Offset         int                   `json:"offset"`
+}
+
+// SplitPageResponse represents a page of a split with the document it is assigned to, if any
+type SplitPageResponse struct {
+	ID         string  `json:"id"`
+	PageNumber string  `json:"page_number"`
+	URL        string  `json:"url"`
+	DocumentID *string `json:"document_id"`
+}
+
+// ListSplitPagesResponse represents every page of a split in the API, flat and in page order
+type ListSplitPagesResponse struct {
+	SplitID         string               `json:"split_id"`
+	Pages           []*SplitPageResponse `json:"pages"`
+	AssignedCount   int                  `json:"assigned_count"`
+	UnassignedCount int                  `json:"unassigned_count"`
+}
+
+// SplitLockResponse represents the lock on a split in the API
+type SplitLockResponse struct {
+	SplitID   string    `json:"split_id"`
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // SplitServiceInterface defines the interface for split operations (for handler and tests)
 type SplitServiceInterface interface {
 	LoadSplit(ctx context.Context, id string) (*LoadSplitResponse, error)
+	LoadSplitParts(ctx context.Context, req LoadSplitRequest) (*LoadSplitResponse, error)
+	StreamSplit(ctx context.Context, id string, w io.Writer) error
+	BatchGetSplits(ctx context.Context, req BatchGetSplitsRequest) (*BatchGetSplitsResponse, error)
+	DiffSplits(ctx context.Context, aID, bID string) (*SplitDiff, error)
+	ExportSplitJSON(ctx context.Context, splitID string) ([]byte, error)
+	ExportSplitCSV(ctx context.Context, splitID string) ([]byte, error)
 	UpdateDocumentMetadata(ctx context.Context, documentID string, req UpdateDocumentMetadataRequest) (*DocumentResponse, error)
+	ReclassifyDocument(ctx context.Context, documentID string, classification string) (*DocumentResponse, error)
+	ReviewDocument(ctx context.Context, documentID string, reviewed bool) (*DocumentResponse, error)
+	MoveDocumentToSplit(ctx context.Context, documentID, targetSplitID string) (*DocumentResponse, error)
 	MovePages(ctx context.Context, req MovePagesRequest) (*MovePagesResponse, error)
 	CreateDocument(ctx context.Context, req CreateDocumentRequest) (*DocumentResponse, error)
 	DeleteDocument(ctx context.Context, documentID string) error
-	FinalizeSplit(ctx context.Context, splitID string) error
+	DeleteDocuments(ctx context.Context, documentIDs []string) (*DeleteDocumentsResponse, error)
+	DeletePages(ctx context.Context, documentID string, req DeletePagesRequest) (*DocumentResponse, error)
+	FinalizeSplit(ctx context.Context, splitID string) (*FinalizeSplitResponse, error)
 	DownloadDocument(ctx context.Context, documentID string) (*DownloadDocumentResponse, error)
+	DocumentDownloadInfo(ctx context.Context, documentID string) (*DocumentDownloadInfoResponse, error)
+	DownloadSplitDocument(ctx context.Context, splitID, documentID string) (*DownloadDocumentResponse, error)
+	SplitDocumentDownloadInfo(ctx context.Context, splitID, documentID string) (*DocumentDownloadInfoResponse, error)
+	ReorderDocuments(ctx context.Context, splitID string, req ReorderDocumentsRequest) (*LoadSplitResponse, error)
+	SwapPages(ctx context.Context, documentID string, req SwapPagesRequest) (*DocumentResponse, error)
+	CountDocumentPages(ctx context.Context, documentID string) (*PageCountResponse, error)
+	RenameSplit(ctx context.Context, splitID, name string) (*LoadSplitResponse, error)
+	AddSplitTags(ctx context.Context, splitID string, tags []string) (*LoadSplitResponse, error)
+	RemoveSplitTags(ctx context.Context, splitID string, tags []string) (*LoadSplitResponse, error)
+	GetPageContent(ctx context.Context, pageID string) (*PageContentResponse, error)
+	ClientStats(ctx context.Context, clientID string) (*ClientStatsResponse, error)
+	ListClients(ctx context.Context, req ListClientsRequest) (*ListClientsResponse, error)
+	ListClientPages(ctx context.Context, req ListClientPagesRequest) (*ListClientPagesResponse, error)
+	ListClientSplits(ctx context.Context, req ListClientSplitsRequest) (*ListClientSplitsResponse, error)
+	CheckConsistency(ctx context.Context, repair bool) (*ConsistencyCheckResponse, error)
+	ListAllPages(ctx context.Context, splitID string) (*ListSplitPagesResponse, error)
+	DeleteSplit(ctx context.Context, splitID string) error
+	ForceDeleteSplit(ctx context.Context, splitID string, actor string) error
+	RecordImpersonation(ctx context.Context, actor, clientID string) error
+	LockSplit(ctx context.Context, splitID string) (*SplitLockResponse, error)
+	UnlockSplit(ctx context.Context, splitID string) error
+	PreviewSplit(ctx context.Context, splitID string) (*PreviewSplitResponse, error)
+	CheckFinalizeSplit(ctx context.Context, splitID string) (*FinalizeCheckResponse, error)
+	FinalizeSplits(ctx context.Context, splitIDs []string) (*FinalizeSplitsResponse, error)
 }
 
 // ErrNotFound is returned when a requested resource is not found
 var ErrNotFound = domain.ErrNotFound
+
+// ErrBlobUnavailable is returned when stored page content cannot be fetched
+var ErrBlobUnavailable = errors.New("blob unavailable")
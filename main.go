@@ -4,8 +4,13 @@ import (
 	"accounting/internal/auth"
 	"accounting/internal/config"
 	"accounting/internal/httpapi"
+	"accounting/internal/infrastructure/blob"
 	"accounting/internal/infrastructure/db/migrations"
+	"accounting/internal/infrastructure/db/repositories/outbox"
+	"accounting/internal/infrastructure/db/repositories/users"
 	"accounting/internal/infrastructure/db/uow"
+	appmetrics "accounting/internal/infrastructure/metrics"
+	"accounting/internal/infrastructure/webhook"
 	"compress/gzip"
 	"context"
 	"database/sql"
@@ -13,30 +18,33 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"accounting/internal/domain"
 	"accounting/internal/domain/ports"
 	"accounting/internal/services"
 
+	"github.com/andybalholm/brotli"
 	_ "github.com/mattn/go-sqlite3"
 	"golang.org/x/time/rate"
 )
 
 const (
-	maxRequestSize  = 1 << 20 // 1MB
-	readTimeout     = 5 * time.Second
-	writeTimeout    = 10 * time.Second
-	idleTimeout     = 120 * time.Second
-	shutdownTimeout = 10 * time.Second
-	// Rate limiting
-	requestsPerSecond = 100
-	burstSize         = 200
+	maxRequestSize = 1 << 20 // 1MB
+	readTimeout    = 5 * time.Second
+	writeTimeout   = 10 * time.Second
+	idleTimeout    = 120 * time.Second
 )
 
 // JWTVerifierAdapter adapts auth.JWTMinter to httpapi.TokenVerifier
@@ -60,6 +68,7 @@ type metrics struct {
 	responseSize      atomic.Int64
 	activeConnections atomic.Int32
 	rateLimitHits     atomic.Int64
+	slowRequests      atomic.Int64
 }
 
 func (m *metrics) incrementRequests() {
@@ -79,14 +88,15 @@ func (m *metrics) getStats() map[string]interface{} {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return map[string]interface{}{
-		"uptime_seconds":     time.Since(m.startTime).Seconds(),
-		"requests_total":     m.requestsTotal,
-		"errors_total":       m.errorsTotal,
-		"last_error":         m.lastError,
-		"avg_duration_ms":    float64(m.requestDuration.Load()) / float64(m.requestsTotal),
-		"total_response_mb":  float64(m.responseSize.Load()) / (1024 * 1024),
-		"active_connections": m.activeConnections.Load(),
-		"rate_limit_hits":    m.rateLimitHits.Load(),
+		"uptime_seconds":      time.Since(m.startTime).Seconds(),
+		"requests_total":      m.requestsTotal,
+		"errors_total":        m.errorsTotal,
+		"last_error":          m.lastError,
+		"avg_duration_ms":     float64(m.requestDuration.Load()) / float64(m.requestsTotal),
+		"total_response_mb":   float64(m.responseSize.Load()) / (1024 * 1024),
+		"active_connections":  m.activeConnections.Load(),
+		"rate_limit_hits":     m.rateLimitHits.Load(),
+		"slow_requests_total": m.slowRequests.Load(),
 	}
 }
 
@@ -106,6 +116,8 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 
 func (w *gzipResponseWriter) WriteHeader(status int) {
 	w.status = status
+	// The length set by the handler is that of the uncompressed body
+	w.Header().Del("Content-Length")
 	w.ResponseWriter.WriteHeader(status)
 }
 
@@ -122,42 +134,210 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	a, err := newApp(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Start server in a goroutine
+	server := a.server
+	go func() {
+		log.Printf("Server starting on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// Create shutdown context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	// Attempt graceful shutdown
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	a.close(ctx)
+
+	log.Println("Server exiting")
+}
+
+// app is the wired application: the HTTP server and handler and the resources behind them
+type app struct {
+	server      *http.Server
+	handler     http.Handler
+	db          *sql.DB
+	uowRegistry *uow.Registry
+	// readDB serves read-only units of work: the read replica when one is configured,
+	// else a pool of its own on the primary
+	readDB       *sql.DB
+	readRegistry *uow.Registry
+	dispatcher   *webhook.Dispatcher
+	limiter      *rate.Limiter
+	renders      *services.LimitedRenderService
+	jwtMinter    *auth.JWTMinter
+	// consistency repairs orphaned rows in the background when an interval is configured
+	consistency *consistencyChecker
+	// shutdownTimeout bounds the graceful shutdown of the server and the workers
+	shutdownTimeout time.Duration
+}
+
+// appOptions holds dependencies that can be replaced, e.g. by tests
+type appOptions struct {
+	renderSvc ports.RenderService
+}
+
+// appOption customizes newApp
+type appOption func(*appOptions)
+
+// withRenderService replaces the PDF render service, e.g. with a fast fake in tests.
+// The render cache is not applied to an injected service; the concurrent render limit is.
+func withRenderService(renderSvc ports.RenderService) appOption {
+	return func(o *appOptions) {
+		o.renderSvc = renderSvc
+	}
+}
+
+// newApp opens the database, applies migrations and wires services, handlers and
+// middleware. Background workers are started; close stops them and closes the database.
+func newApp(cfg *config.Config, opts ...appOption) (*app, error) {
+	var o appOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Initialize SQLite database
 	db, err := sql.Open("sqlite3", cfg.DatabasePath)
 	if err != nil {
-		log.Fatalf("Failed to open database: %v", err)
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetime) * time.Second)
+	a := &app{db: db, shutdownTimeout: time.Duration(cfg.ShutdownTimeout) * time.Second}
+
+	// Write-ahead logging lets the read pool read while a write is being committed
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable write-ahead logging: %w", err)
 	}
-	defer db.Close()
 
 	// Apply migrations
 	if err := migrations.ApplyMigrations(db); err != nil {
-		log.Fatalf("Failed to apply migrations: %v", err)
+		db.Close()
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	// Create unit of work factory; the registry rolls back transactions that are never finished
+	a.uowRegistry = uow.NewRegistry(db, time.Duration(cfg.TxMaxAge)*time.Second)
+	a.uowRegistry.SetVerifySaves(cfg.VerifySplitSaves)
+	uowFactory := func(ctx context.Context) (ports.UnitOfWork, error) {
+		u, err := a.uowRegistry.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+
+	// Read-only units of work go to the read replica when one is configured, else to a
+	// pool of their own on the primary, so long reads (previews, streamed exports) never
+	// wait for the capped write connections. Neither pool is capped, as reads do not
+	// contend. The replica's schema is kept by replication, so no migrations are applied to it.
+	readPath := cfg.ReplicaDatabasePath
+	if readPath == "" {
+		readPath = cfg.DatabasePath
+	}
+	a.readDB, err = sql.Open("sqlite3", readPath)
+	if err == nil {
+		a.readDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		a.readDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetime) * time.Second)
+		err = a.readDB.Ping()
+	}
+	if err != nil {
+		a.closeDatabases()
+		return nil, fmt.Errorf("failed to open read database: %w", err)
+	}
+	a.readRegistry = uow.NewRegistry(a.readDB, time.Duration(cfg.TxMaxAge)*time.Second)
+	readUoWFactory := func(ctx context.Context) (ports.UnitOfWork, error) {
+		u, err := a.readRegistry.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		return u, nil
 	}
 
-	// Create unit of work factory
-	uowFactory := func() (ports.UnitOfWork, error) {
-		uow := uow.NewUnitOfWorkSQL(db)
-		if err := uow.Begin(); err != nil {
+	// Create render service; the cache sits in front of the concurrency limit so cache
+	// hits never wait for a render slot
+	blobStore := blob.NewFileBlobStore(cfg.BlobRoot)
+	renderer := o.renderSvc
+	if renderer == nil {
+		renderer, err = services.NewRenderer(cfg.Renderer, blobStore)
+		if err != nil {
+			a.closeDatabases()
 			return nil, err
 		}
-		return uow, nil
+	}
+	a.renders = services.NewLimitedRenderService(renderer, cfg.MaxConcurrentRenders, cfg.RenderQueueSize)
+	var renderSvc ports.RenderService = a.renders
+	if o.renderSvc == nil {
+		renderSvc = services.NewCachingRenderService(a.renders, cfg.RenderCacheSize)
 	}
 
-	// Create render service
-	renderSvc := services.NewRenderService()
+	// Business counters and latency histograms recorded by the services, exposed on /metrics
+	recorder := appmetrics.NewRecorder()
 
 	// Create split service
-	splitSvc := services.NewSplitService(uowFactory, renderSvc)
+	splitSvc := services.NewSplitService(uowFactory, renderSvc, blobStore)
+	splitSvc.SetReadUnitOfWorkFactory(readUoWFactory)
+	splitSvc.SetMaxUnassignedPages(cfg.MaxUnassignedPages)
+	splitSvc.SetMetricsRecorder(recorder)
+	splitSvc.SetFinalizeEvents(cfg.FinalizeWebhookURL != "")
+	splitSvc.SetMaxPageBatch(cfg.MaxPageBatch)
+	splitSvc.SetDocumentNameTemplate(cfg.DocumentNameTemplate)
+	splitSvc.SetLockTTL(time.Duration(cfg.SplitLockTTL) * time.Second)
+	splitSvc.SetRequireReview(cfg.RequireReviewBeforeFinalize)
+	splitSvc.SetRequireClassification(cfg.RequireClassificationOnFinalize)
+	splitSvc.SetAllowedClassifications(cfg.AllowedClassifications)
+	splitSvc.SetFilenamePolicy(domain.FilenamePolicy(cfg.FilenamePolicy))
+	splitSvc.SetSplitCache(cfg.SplitCacheSize, time.Duration(cfg.SplitCacheTTL)*time.Second)
+	if err := splitSvc.SetPageURLBase(cfg.PageURLBase); err != nil {
+		a.closeDatabases()
+		return nil, err
+	}
 
-	// Create JWT minter with users from config
+	// Create JWT minter with users from the database and config (config wins on conflicts)
+	userRepo := users.NewUserRepositorySQL(db)
+	storedUsers, err := userRepo.List(context.Background())
+	if err != nil {
+		a.closeDatabases()
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
 	configUsers := cfg.GetUsersMap()
-	users := make(map[string]auth.User, len(configUsers))
+	authUsers := make(map[string]auth.User, len(storedUsers)+len(configUsers))
+	for _, u := range storedUsers {
+		authUsers[u.Username] = u
+	}
 	for k, v := range configUsers {
-		users[k] = auth.User{Username: v.Username, Password: v.Password}
+		authUsers[k] = auth.User{Username: v.Username, Password: v.Password, PasswordHash: v.PasswordHash}
+	}
+	var jwtMinter *auth.JWTMinter
+	if len(cfg.JWTKeys) > 0 {
+		keys := make([]auth.SigningKey, len(cfg.JWTKeys))
+		for i, k := range cfg.JWTKeys {
+			keys[i] = auth.SigningKey{ID: k.ID, Secret: []byte(k.Secret)}
+		}
+		jwtMinter, err = auth.NewJWTMinterWithKeys(authUsers, keys, cfg.JWTActiveKeyID)
+	} else {
+		jwtMinter, err = auth.NewJWTMinter(authUsers)
 	}
-	jwtMinter, err := auth.NewJWTMinter(users)
 	if err != nil {
-		log.Fatalf("Failed to create JWT minter: %v", err)
+		a.closeDatabases()
+		return nil, fmt.Errorf("failed to create JWT minter: %w", err)
 	}
 
 	// Create token verifier adapter
@@ -165,86 +345,250 @@ func main() {
 
 	// Create split handler
 	splitHandler := httpapi.NewSplitHandler(splitSvc, tokenVerifier)
+	splitHandler.SetAdmins(cfg.AdminUsers)
+	ingestSvc := services.NewIngestionService(uowFactory, blobStore)
+	ingestSvc.SetMaxPages(cfg.MaxPagesPerSplit)
+	splitHandler.SetIngestionService(ingestSvc)
 
 	// Initialize metrics
 	metrics := &metrics{
 		startTime: time.Now(),
 	}
 
+	// Parse trusted proxies used to resolve client IPs
+	trustedProxies, err := httpapi.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		a.closeDatabases()
+		return nil, fmt.Errorf("failed to parse trusted proxies: %w", err)
+	}
+
+	// Lock out repeated failed logins per username and client IP
+	jwtMinter.SetLoginLockout(cfg.LoginMaxFailures, time.Duration(cfg.LoginFailureWindow)*time.Second)
+	jwtMinter.SetClientIPFunc(func(r *http.Request) string {
+		return httpapi.ClientIP(r, trustedProxies)
+	})
+
 	// Create rate limiter
-	limiter := rate.NewLimiter(rate.Limit(requestsPerSecond), burstSize)
+	a.limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), cfg.BurstSize)
 
 	// Create router
 	mux := http.NewServeMux()
 
 	// Register auth routes
 	jwtMinter.Mount(mux)
+	auth.NewUsersHandler(jwtMinter, userRepo, cfg.AdminUsers).Mount(mux)
 
 	// Register split routes
+	mux.HandleFunc("POST /splits/ingest", splitHandler.IngestSplitHandler)
+	mux.HandleFunc("POST /splits/batch-get", splitHandler.BatchGetSplitsHandler)
+	mux.HandleFunc("POST /splits/finalize-batch", splitHandler.FinalizeSplitsHandler)
 	mux.HandleFunc("GET /splits/{id}", splitHandler.LoadSplitHandler)
+	mux.HandleFunc("PATCH /splits/{id}", splitHandler.UpdateSplitHandler)
+	mux.HandleFunc("DELETE /splits/{id}", splitHandler.DeleteSplitHandler)
+	mux.HandleFunc("GET /splits/{id}/export.json", splitHandler.ExportSplitJSONHandler)
+	mux.HandleFunc("GET /splits/{id}/export.csv", splitHandler.ExportSplitCSVHandler)
+	mux.HandleFunc("GET /splits/{id}/diff/{other}", splitHandler.DiffSplitsHandler)
+	mux.HandleFunc("GET /splits/{id}/preview", splitHandler.PreviewSplitHandler)
+	mux.HandleFunc("GET /splits/{id}/pages", splitHandler.ListSplitPagesHandler)
 	mux.HandleFunc("POST /splits/{id}/finalize", splitHandler.FinalizeSplitHandler)
+	mux.HandleFunc("POST /splits/{id}/lock", splitHandler.LockSplitHandler)
+	mux.HandleFunc("DELETE /splits/{id}/lock", splitHandler.UnlockSplitHandler)
+	mux.HandleFunc("POST /splits/{id}/tags", splitHandler.AddSplitTagsHandler)
+	mux.HandleFunc("DELETE /splits/{id}/tags", splitHandler.RemoveSplitTagsHandler)
+	mux.HandleFunc("POST /splits/{id}/documents/reorder", splitHandler.ReorderDocumentsHandler)
+	mux.HandleFunc("GET /splits/{id}/documents/{docId}/download", splitHandler.DownloadSplitDocumentHandler)
 	mux.HandleFunc("POST /documents", splitHandler.CreateDocumentHandler)
 	mux.HandleFunc("PATCH /documents/{id}", splitHandler.UpdateDocumentMetadataHandler)
+	mux.HandleFunc("POST /documents/batch-delete", splitHandler.DeleteDocumentsHandler)
 	mux.HandleFunc("DELETE /documents/{id}", splitHandler.DeleteDocumentHandler)
+	mux.HandleFunc("DELETE /documents/{id}/pages", splitHandler.DeletePagesHandler)
+	mux.HandleFunc("POST /documents/{id}/pages/swap", splitHandler.SwapPagesHandler)
+	mux.HandleFunc("GET /documents/{id}/pages/count", splitHandler.DocumentPageCountHandler)
+	mux.HandleFunc("POST /documents/{id}/reclassify", splitHandler.ReclassifyDocumentHandler)
+	mux.HandleFunc("POST /documents/{id}/move-to-split", splitHandler.MoveDocumentToSplitHandler)
+	mux.HandleFunc("POST /documents/{id}/review", splitHandler.ReviewDocumentHandler)
+	mux.HandleFunc("DELETE /documents/{id}/review", splitHandler.UnreviewDocumentHandler)
 	mux.HandleFunc("GET /documents/{id}/download", splitHandler.DownloadDocumentHandler)
 	mux.HandleFunc("POST /pages/move", splitHandler.MovePagesHandler)
+	mux.HandleFunc("GET /pages/{id}/content", splitHandler.PageContentHandler)
+	mux.HandleFunc("GET /clients", splitHandler.ListClientsHandler)
+	mux.HandleFunc("GET /clients/{id}/stats", splitHandler.ClientStatsHandler)
+	mux.HandleFunc("GET /clients/{id}/pages", splitHandler.ListClientPagesHandler)
+	mux.HandleFunc("GET /clients/{id}/splits", splitHandler.ListClientSplitsHandler)
+	mux.HandleFunc("POST /admin/consistency-check", splitHandler.ConsistencyCheckHandler)
 
 	// Register metrics endpoint
 	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		stats := metrics.getStats()
+		stats["domain_errors_total"] = splitHandler.DomainErrorCounts()
+		stats["latency_seconds"] = recorder.Latency.Snapshot()
+		stats["counters"] = recorder.Counters()
+		stats["renders_in_flight"] = a.renders.InFlight()
+		stats["renders_waiting"] = a.renders.Waiting()
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(metrics.getStats())
+		json.NewEncoder(w).Encode(stats)
 	})
 
 	// Create middleware chain
-	handler := chain(
+	middlewares := []func(http.Handler) http.Handler{
 		recoveryMiddleware,
-		loggingMiddleware,
+		loggingMiddleware(trustedProxies),
 		requestIDMiddleware,
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		middlewares = append(middlewares, corsMiddleware(cfg.CORSAllowedOrigins, time.Duration(cfg.CORSMaxAge)*time.Second))
+	}
+	middlewares = append(middlewares,
 		metricsMiddleware(metrics),
-		rateLimitMiddleware(limiter, metrics),
-		compressionMiddleware,
-	)(mux)
-
-	// Create server
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      handler,
+		slowRequestMiddleware(time.Duration(cfg.SlowRequestThreshold)*time.Millisecond, metrics),
+		rateLimitMiddleware(a.limiter, metrics, trustedProxies),
+	)
+	if cfg.RequireJSONContentType {
+		middlewares = append(middlewares, httpapi.RequireJSONContentType)
+	}
+	if cfg.StrictQueryParams {
+		middlewares = append(middlewares, httpapi.RejectUnknownQueryParams(mux))
+	}
+	middlewares = append(middlewares, splitHandler.OnBehalfOf)
+	if !cfg.DisableCompression {
+		middlewares = append(middlewares, compressionMiddleware(cfg.CompressionEncodings))
+	}
+	// Innermost so handlers see the writer that asks for indented JSON
+	middlewares = append(middlewares, httpapi.PrettyJSON)
+	a.handler = chain(middlewares...)(httpapi.JSONRouteErrors(mux))
+
+	// Create server; an empty host binds all interfaces
+	a.server = &http.Server{
+		Addr:         net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Handler:      a.handler,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		log.Printf("Server starting on :%d", cfg.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+	// Start the transaction sweeper
+	if cfg.TxMaxAge > 0 {
+		a.uowRegistry.Start(time.Duration(cfg.TxMaxAge) * time.Second / 2)
+		a.readRegistry.Start(time.Duration(cfg.TxMaxAge) * time.Second / 2)
+	}
+
+	// Start the sweeper expiring old failed-login records and lockouts
+	a.jwtMinter = jwtMinter
+	a.jwtMinter.StartLoginLockoutSweep(
+		time.Duration(cfg.LoginLockoutSweepInterval)*time.Second,
+		time.Duration(cfg.LoginLockoutRetention)*time.Second,
+	)
+
+	// Start the background repair of orphaned documents and pages
+	if cfg.ConsistencyCheckInterval > 0 {
+		a.consistency = newConsistencyChecker(splitSvc)
+		a.consistency.start(time.Duration(cfg.ConsistencyCheckInterval) * time.Second)
+	}
+
+	// Start the outbox dispatcher delivering finalize webhooks
+	if cfg.FinalizeWebhookURL != "" {
+		a.dispatcher = webhook.NewDispatcher(
+			outbox.NewOutboxRepositorySQL(db),
+			cfg.FinalizeWebhookURL,
+			time.Duration(cfg.OutboxPollInterval)*time.Second,
+			cfg.OutboxMaxAttempts,
+		)
+		a.dispatcher.Start()
+	}
+
+	return a, nil
+}
+
+// close stops background workers and closes the databases
+func (a *app) close(ctx context.Context) {
+	// Stop the outbox dispatcher; undelivered events are retried on next start
+	if a.dispatcher != nil {
+		if err := a.dispatcher.Stop(ctx); err != nil {
+			log.Printf("Outbox dispatcher did not stop cleanly: %v", err)
 		}
-	}()
+	}
+	if a.consistency != nil {
+		a.consistency.stopChecks()
+	}
+	if a.jwtMinter != nil {
+		a.jwtMinter.StopLoginLockoutSweep()
+	}
+	a.uowRegistry.Stop()
+	a.readRegistry.Stop()
+	a.closeDatabases()
+}
 
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+// consistencyChecker periodically finds and repairs orphaned documents and pages
+type consistencyChecker struct {
+	svc services.SplitServiceInterface
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
-	defer cancel()
+	stop chan struct{}
+	done chan struct{}
+}
 
-	// Attempt graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+func newConsistencyChecker(svc services.SplitServiceInterface) *consistencyChecker {
+	return &consistencyChecker{svc: svc}
+}
+
+// check repairs the orphans found and logs them, if any
+func (c *consistencyChecker) check() {
+	ctx := services.WithActor(context.Background(), "system")
+	report, err := c.svc.CheckConsistency(ctx, true)
+	if err != nil {
+		log.Printf("Consistency check failed: %v", err)
+		return
 	}
+	if report.IssuesFound > 0 {
+		log.Printf("Consistency check repaired %d of %d issues: %d orphaned documents, %d orphaned pages, %d unlinked pages",
+			report.IssuesFixed, report.IssuesFound, len(report.OrphanedDocumentIDs),
+			len(report.OrphanedPageIDs), len(report.UnlinkedPageIDs))
+	}
+}
 
-	log.Println("Server exiting")
+// start runs the check every interval until stopChecks is called
+func (c *consistencyChecker) start(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.check()
+			}
+		}
+	}()
+}
+
+// stopChecks stops the checks started by start, waiting for a running one to finish
+func (c *consistencyChecker) stopChecks() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	c.stop = nil
+}
+
+// closeDatabases closes the primary database and the read replica, if any
+func (a *app) closeDatabases() {
+	a.db.Close()
+	if a.readDB != nil {
+		a.readDB.Close()
+	}
 }
 
 // rateLimitMiddleware implements rate limiting
-func rateLimitMiddleware(limiter *rate.Limiter, metrics *metrics) func(http.Handler) http.Handler {
+func rateLimitMiddleware(limiter *rate.Limiter, metrics *metrics, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if !limiter.Allow() {
 				metrics.rateLimitHits.Add(1)
+				log.Printf("rate limit exceeded, client_ip: %s, path: %s", httpapi.ClientIP(r, trustedProxies), r.URL.Path)
 				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 				return
 			}
@@ -253,37 +597,103 @@ func rateLimitMiddleware(limiter *rate.Limiter, metrics *metrics) func(http.Hand
 	}
 }
 
-// compressionMiddleware adds gzip compression
-func compressionMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !shouldCompress(r) {
-			next.ServeHTTP(w, r)
-			return
-		}
+// Response encodings supported by compressionMiddleware
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
 
-		gz := gzip.NewWriter(w)
-		defer gz.Close()
+// defaultCompressionEncodings are offered when none are configured: Brotli first,
+// as it compresses large JSON such as LoadSplit responses better than gzip
+var defaultCompressionEncodings = []string{encodingBrotli, encodingGzip}
 
-		gzw := &gzipResponseWriter{
-			Writer:         gz,
-			ResponseWriter: w,
-		}
+// compressionMiddleware compresses responses with the encoding negotiated from
+// Accept-Encoding among encodings, which are in order of preference
+func compressionMiddleware(encodings []string) func(http.Handler) http.Handler {
+	if len(encodings) == 0 {
+		encodings = defaultCompressionEncodings
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !shouldCompress(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		w.Header().Set("Content-Encoding", "gzip")
-		next.ServeHTTP(gzw, r)
-	})
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), encodings)
+			var cw io.WriteCloser
+			switch encoding {
+			case encodingBrotli:
+				cw = brotli.NewWriter(w)
+			case encodingGzip:
+				cw = gzip.NewWriter(w)
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+			defer cw.Close()
+
+			gzw := &gzipResponseWriter{
+				Writer:         cw,
+				ResponseWriter: w,
+			}
+
+			w.Header().Set("Content-Encoding", encoding)
+			next.ServeHTTP(gzw, r)
+		})
+	}
 }
 
-// shouldCompress determines if the response should be compressed
+// shouldCompress determines if the response may be compressed
 func shouldCompress(r *http.Request) bool {
 	// Skip compression for small responses
 	if r.ContentLength > 0 && r.ContentLength < 1024 {
 		return false
 	}
 
-	// Check if client accepts gzip
-	acceptEncoding := r.Header.Get("Accept-Encoding")
-	return acceptEncoding != "" && acceptEncoding != "identity"
+	// Answers to HEAD keep the Content-Length of the identity body
+	if r.Method == http.MethodHead {
+		return false
+	}
+
+	// Partial responses must be byte ranges of the identity body
+	return r.Header.Get("Range") == ""
+}
+
+// negotiateEncoding returns the encoding among supported with the highest quality
+// in acceptEncoding, preferring the earlier one in supported on a tie, or "" if the
+// client accepts none of them and should get the identity body
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		qualities[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supported {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
 }
 
 // metricsMiddleware tracks request metrics
@@ -325,17 +735,48 @@ func chain(middlewares ...func(http.Handler) http.Handler) func(http.Handler) ht
 }
 
 // loggingMiddleware logs information about each request using structured logging
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		ww := newResponseWriter(w)
-		next.ServeHTTP(ww, r)
-		duration := time.Since(start)
+func loggingMiddleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := newResponseWriter(w)
+			next.ServeHTTP(ww, r)
+			duration := time.Since(start)
 
-		log.Printf("request completed, method: %s, path: %s, status: %d, duration: %v, request_id: %s",
-			r.Method, r.URL.Path, ww.status, duration, r.Context().Value("request_id"),
-		)
-	})
+			log.Printf("request completed, method: %s, path: %s, status: %d, duration: %v, client_ip: %s, request_id: %s",
+				r.Method, r.URL.Path, ww.status, duration, httpapi.ClientIP(r, trustedProxies), r.Context().Value("request_id"),
+			)
+		})
+	}
+}
+
+// slowRequestMiddleware logs a warning for, and counts, each request taking longer
+// than threshold; a non-positive threshold disables it
+func slowRequestMiddleware(threshold time.Duration, m *metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			duration := time.Since(start)
+			if duration <= threshold {
+				return
+			}
+
+			m.slowRequests.Add(1)
+			// The mux sets the pattern on the request it routes; inner middlewares that
+			// copy the request hide it, so fall back to the path
+			pattern := r.Pattern
+			if pattern == "" {
+				pattern = r.Method + " " + r.URL.Path
+			}
+			log.Printf("WARN slow request, method: %s, pattern: %s, duration: %v, threshold: %v, request_id: %s",
+				r.Method, pattern, duration, threshold, r.Context().Value("request_id"),
+			)
+		})
+	}
 }
 
 // recoveryMiddleware recovers from panics and returns 500
@@ -351,6 +792,48 @@ func recoveryMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// corsAllowedMethods and corsExposedHeaders are announced to browsers calling the API cross-origin
+const (
+	corsAllowedMethods = "GET, HEAD, POST, PATCH, DELETE"
+	corsExposedHeaders = "Accept-Ranges, Content-Disposition, Content-Range, ETag, Location, Retry-After, X-Estimated-Content-Length"
+)
+
+// corsMiddleware lets browsers on the allowed origins ("*" for any) call the API and
+// answers their preflight requests, which they may cache for maxAge. Requests from
+// other origins are served without CORS headers, so browsers block them.
+func corsMiddleware(origins []string, maxAge time.Duration) func(http.Handler) http.Handler {
+	allowAny := slices.Contains(origins, "*")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" || (!allowAny && !slices.Contains(origins, origin)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if allowAny {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			// Preflight requests are answered here instead of by the routes
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // requestIDMiddleware adds a unique request ID to each request
 func requestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,526 @@
+package main
+
+import (
+	"accounting/internal/client"
+	"accounting/internal/config"
+	"accounting/internal/domain/ports"
+	"accounting/internal/infrastructure/db/migrations"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// fakeRenderService returns fixed bytes instead of rendering a PDF
+type fakeRenderService struct{}
+
+func (f *fakeRenderService) RenderDocument(ctx context.Context, req ports.RenderDocumentRequest) (*ports.RenderDocumentResponse, error) {
+	return &ports.RenderDocumentResponse{
+		Filename:    req.Document.Filename,
+		ContentType: "application/pdf",
+		Data:        []byte("fake pdf " + req.Document.ID),
+	}, nil
+}
+
+// testConfig returns a valid configuration with its database and blobs in a temp dir
+func testConfig(t *testing.T) *config.Config {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Port:                   8080,
+		Host:                   "localhost",
+		ShutdownTimeout:        10,
+		RequestsPerSecond:      100,
+		BurstSize:              200,
+		DatabasePath:           filepath.Join(dir, "accounting.db"),
+		DBMaxOpenConns:         1,
+		DBMaxIdleConns:         1,
+		BlobRoot:               dir,
+		OutboxPollInterval:     5,
+		RequireJSONContentType: true,
+		Users:                  []config.User{{Username: "test", Password: "test"}},
+	}
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func TestNewAppAppliesShutdownTimeout(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ShutdownTimeout = 3
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	assert.Equal(t, 3*time.Second, a.shutdownTimeout)
+}
+
+func TestNewAppServerAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		port int
+		addr string
+	}{
+		{name: "specific interface", host: "127.0.0.1", port: 9090, addr: "127.0.0.1:9090"},
+		{name: "empty host binds all interfaces", host: "", port: 8080, addr: ":8080"},
+		{name: "IPv6 host", host: "::1", port: 8080, addr: "[::1]:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Host = tt.host
+			cfg.Port = tt.port
+
+			a, err := newApp(cfg)
+			require.NoError(t, err)
+			defer a.close(context.Background())
+
+			assert.Equal(t, tt.addr, a.server.Addr)
+		})
+	}
+}
+
+func TestNewAppBuildsRateLimiterFromConfig(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.RequestsPerSecond = 7
+	cfg.BurstSize = 3
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	assert.Equal(t, rate.Limit(7), a.limiter.Limit())
+	assert.Equal(t, 3, a.limiter.Burst())
+}
+
+func TestNewAppDisableCompression(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		cfg := testConfig(t)
+		cfg.DisableCompression = disabled
+
+		a, err := newApp(cfg)
+		require.NoError(t, err)
+		defer a.close(context.Background())
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		a.handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		if disabled {
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.True(t, json.Valid(w.Body.Bytes()), "body should be plain JSON")
+		} else {
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		}
+	}
+}
+
+func TestNewAppStrictQueryParams(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		cfg := testConfig(t)
+		cfg.StrictQueryParams = strict
+
+		a, err := newApp(cfg)
+		require.NoError(t, err)
+		defer a.close(context.Background())
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics?limt=10", nil)
+		w := httptest.NewRecorder()
+		a.handler.ServeHTTP(w, req)
+
+		if strict {
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.JSONEq(t, `{"error":"unknown query parameters: limt"}`, w.Body.String())
+		} else {
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	}
+}
+
+func TestCompressionMiddlewareNegotiatesEncoding(t *testing.T) {
+	body := strings.Repeat(`{"id":"doc","pages":[1,2,3]}`, 100)
+	handler := func(encodings []string) http.Handler {
+		return compressionMiddleware(encodings)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, body)
+		}))
+	}
+
+	tests := []struct {
+		name           string
+		encodings      []string
+		acceptEncoding string
+		want           string
+	}{
+		{"brotli", nil, "br", "br"},
+		{"gzip", nil, "gzip", "gzip"},
+		{"brotli preferred", nil, "gzip, br", "br"},
+		{"client quality wins", nil, "br;q=0.5, gzip", "gzip"},
+		{"wildcard", nil, "*", "br"},
+		{"refused", nil, "gzip;q=0, br;q=0", ""},
+		{"unsupported", nil, "deflate", ""},
+		{"none", nil, "", ""},
+		{"configured gzip only", []string{"gzip"}, "br, gzip", "gzip"},
+		{"configured gzip only without gzip", []string{"gzip"}, "br", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/splits/s1", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler(tt.encodings).ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+			var reader io.Reader = w.Body
+			switch tt.want {
+			case "br":
+				reader = brotli.NewReader(w.Body)
+			case "gzip":
+				gz, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				reader = gz
+			}
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(decoded))
+		})
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := corsMiddleware([]string{"https://app.example.com"}, 10*time.Minute)(next)
+
+	// Preflight from an allowed origin is answered with the configured max age
+	req := httptest.NewRequest(http.MethodOptions, "/splits/split-1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, corsAllowedMethods, w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "authorization, content-type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Zero(t, served)
+
+	// Actual requests reach the routes with the origin allowed
+	req = httptest.NewRequest(http.MethodGet, "/splits/split-1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	exposed := w.Header().Get("Access-Control-Expose-Headers")
+	for _, header := range []string{"ETag", "Location", "Content-Range", "Accept-Ranges"} {
+		assert.Contains(t, exposed, header)
+	}
+	assert.Equal(t, 1, served)
+
+	// Other origins get no CORS headers
+	req = httptest.NewRequest(http.MethodOptions, "/splits/split-1", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestNewAppAppliesDBPoolSettings(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.DBMaxOpenConns = 3
+	cfg.DBMaxIdleConns = 1
+	cfg.DBConnMaxLifetime = 300
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	assert.Equal(t, 3, a.db.Stats().MaxOpenConnections)
+
+	// Only one of the three connections is kept once they are released
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for range 3 {
+		conn, err := a.db.Conn(ctx)
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+	stats := a.db.Stats()
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(2), stats.MaxIdleClosed)
+}
+
+func TestNewAppReadsDoNotWaitForWriteConnection(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.DBMaxOpenConns = 1
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	// A write transaction holds the only write connection, with a change pending
+	ctx := context.Background()
+	write, err := a.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer write.Rollback()
+	_, err = write.Exec(`INSERT INTO splits (id, client_id, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		"pending-split", "client1", "draft", time.Now(), time.Now())
+	require.NoError(t, err)
+
+	// Reads still get a connection of their own and see only committed data
+	readCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	read, err := a.readRegistry.BeginTx(readCtx, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+	defer read.Rollback(readCtx)
+	split, err := read.SplitRepository().Get(readCtx, "pending-split")
+	require.NoError(t, err)
+	assert.Nil(t, split)
+	assert.Equal(t, 1, a.db.Stats().MaxOpenConnections)
+}
+
+func TestNewAppCORSMaxAge(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.CORSAllowedOrigins = []string{"*"}
+	cfg.CORSMaxAge = 3600
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	req := httptest.NewRequest(http.MethodOptions, "/splits/split-1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	a.handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestSlowRequestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow/{id}", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	mux.HandleFunc("GET /fast", func(w http.ResponseWriter, r *http.Request) {})
+	m := &metrics{startTime: time.Now()}
+	handler := chain(requestIDMiddleware, slowRequestMiddleware(10*time.Millisecond, m))(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/fast", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, int64(0), m.slowRequests.Load())
+	assert.Empty(t, logs.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/slow/42", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, int64(1), m.slowRequests.Load())
+	assert.Equal(t, int64(1), m.getStats()["slow_requests_total"])
+	assert.Contains(t, logs.String(), "WARN slow request, method: GET, pattern: GET /slow/{id}, duration: ")
+	assert.Contains(t, logs.String(), "request_id: req-1")
+}
+
+func TestAppDownloadWithInjectedRenderService(t *testing.T) {
+	cfg := testConfig(t)
+
+	a, err := newApp(cfg, withRenderService(&fakeRenderService{}))
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	now := time.Now()
+	_, err = a.db.Exec(`INSERT INTO splits (id, client_id, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		"split1", "client1", "draft", now, now)
+	require.NoError(t, err)
+	for i, id := range []string{"page1", "page2"} {
+		_, err = a.db.Exec(`INSERT INTO pages (id, split_id, page_number, url) VALUES (?, ?, ?, ?)`,
+			id, "split1", i+1, id+".png")
+		require.NoError(t, err)
+	}
+
+	server := httptest.NewServer(a.handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/auth/login", "application/json", strings.NewReader(`{"username":"test","password":"test"}`))
+	require.NoError(t, err)
+	var login struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+	resp.Body.Close()
+
+	ctx := context.Background()
+	apiClient := client.NewClient(server.URL)
+	apiClient.SetToken(login.Token)
+
+	doc, err := apiClient.CreateDocument(ctx, client.CreateDocumentRequest{
+		SplitID:        "split1",
+		Name:           "W-2",
+		Classification: "W-2",
+		Filename:       "w2.pdf",
+		PageIDs:        []string{"page1", "page2"},
+	})
+	require.NoError(t, err)
+
+	// The download body is the raw rendered document, so fetch it directly
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/documents/"+doc.ID+"/download", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+login.Token)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "fake pdf "+doc.ID, string(data))
+
+	// Range requests get the requested slice, uncompressed
+	req.Header.Set("Range", "bytes=0-7")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	data, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "fake pdf", string(data))
+}
+
+func TestAppRoutesReadsToReplica(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ReplicaDatabasePath = filepath.Join(t.TempDir(), "replica.db")
+
+	// The replica has the same schema but, lagging behind, different contents
+	replica, err := sql.Open("sqlite3", cfg.ReplicaDatabasePath)
+	require.NoError(t, err)
+	defer replica.Close()
+	require.NoError(t, migrations.ApplyMigrations(replica))
+
+	a, err := newApp(cfg)
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	now := time.Now()
+	insertSplit := func(db *sql.DB, id string) {
+		_, err := db.Exec(`INSERT INTO splits (id, client_id, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+			id, "client1", "draft", now, now)
+		require.NoError(t, err)
+	}
+	insertSplit(a.db, "primary-split")
+	insertSplit(replica, "replica-split")
+
+	server := httptest.NewServer(a.handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/auth/login", "application/json", strings.NewReader(`{"username":"test","password":"test"}`))
+	require.NoError(t, err)
+	var login struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+	resp.Body.Close()
+
+	status := func(method, path string) int {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+login.Token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Reads see only the replica
+	assert.Equal(t, http.StatusOK, status(http.MethodGet, "/splits/replica-split"))
+	assert.Equal(t, http.StatusNotFound, status(http.MethodGet, "/splits/primary-split"))
+
+	// Writes see only the primary
+	assert.Equal(t, http.StatusNotFound, status(http.MethodDelete, "/splits/replica-split"))
+	assert.Equal(t, http.StatusNoContent, status(http.MethodDelete, "/splits/primary-split"))
+	var count int
+	require.NoError(t, a.db.QueryRow(`SELECT COUNT(*) FROM splits`).Scan(&count))
+	assert.Equal(t, 0, count)
+	require.NoError(t, replica.QueryRow(`SELECT COUNT(*) FROM splits`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestAppMissingDocumentReturnsNotFound(t *testing.T) {
+	a, err := newApp(testConfig(t))
+	require.NoError(t, err)
+	defer a.close(context.Background())
+
+	server := httptest.NewServer(a.handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/auth/login", "application/json", strings.NewReader(`{"username":"test","password":"test"}`))
+	require.NoError(t, err)
+	var login struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+	resp.Body.Close()
+
+	// The document lookup's not-found error must reach the client as a 404, not a 500
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPatch, "/documents/missing", `{"name":"Renamed"}`},
+		{http.MethodDelete, "/documents/missing", ""},
+		{http.MethodPost, "/documents/missing/reclassify", `{"classification":"W-2"}`},
+	}
+	for _, r := range requests {
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			req, err := http.NewRequest(r.method, server.URL+r.path, strings.NewReader(r.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+login.Token)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "document", body["resource"])
+			assert.Equal(t, "missing", body["id"])
+		})
+	}
+}
@@ -37,6 +37,7 @@ func setupTestDB() error {
 	DROP TABLE IF EXISTS pages;
 	DROP TABLE IF EXISTS documents;
 	DROP TABLE IF EXISTS splits;
+	DROP TABLE IF EXISTS split_tags;
 	`)
 	if err != nil {
 		return err
@@ -47,9 +48,12 @@ func setupTestDB() error {
 	CREATE TABLE splits (
 		id TEXT PRIMARY KEY,
 		client_id TEXT NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
 		status TEXT NOT NULL,
 		created_at TIMESTAMP NOT NULL,
-		updated_at TIMESTAMP NOT NULL
+		updated_at TIMESTAMP NOT NULL,
+		last_modified_by TEXT NOT NULL DEFAULT '',
+		finalized_at TIMESTAMP
 	);
 	CREATE TABLE documents (
 		id TEXT PRIMARY KEY,
@@ -60,6 +64,12 @@ func setupTestDB() error {
 		short_description TEXT,
 		start_page TEXT,
 		end_page TEXT,
+		start_page_number INTEGER NOT NULL DEFAULT 0,
+		sort_order INTEGER NOT NULL DEFAULT 0,
+		reviewed INTEGER NOT NULL DEFAULT 0,
+		reviewed_by TEXT NOT NULL DEFAULT '',
+		reviewed_at TIMESTAMP,
+		last_modified_by TEXT NOT NULL DEFAULT '',
 		FOREIGN KEY (split_id) REFERENCES splits(id)
 	);
 	CREATE TABLE pages (
@@ -68,9 +78,15 @@ func setupTestDB() error {
 		document_id TEXT,
 		page_number TEXT NOT NULL,
 		url TEXT NOT NULL,
+		sort_order INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY (split_id) REFERENCES splits(id),
 		FOREIGN KEY (document_id) REFERENCES documents(id)
 	);
+	CREATE TABLE split_tags (
+		split_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (split_id, tag)
+	);
 	`)
 	if err != nil {
 		return err